@@ -1,15 +1,15 @@
-package main
-
-import (
-	"log"
-	"tenders/internal/app"
-)
-
-func main() {
-	app, err := app.NewApp()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	app.Run()
-}
+package main
+
+import (
+	"log"
+	"tenders/internal/app"
+)
+
+func main() {
+	app, err := app.NewApp()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Run()
+}
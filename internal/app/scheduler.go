@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runScheduler periodically runs background jobs of service until ctx is cancelled,
+// job being executed at the moment of cancellation is interrupted through its context
+func (app *App) runScheduler(ctx context.Context) {
+	interval := app.cfg.SchedulerInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		app.runJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler stopped.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (app *App) runJobs(ctx context.Context) {
+	n, err := app.service.PublishScheduledTenders(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Println("Scheduler: publishing scheduled tenders:", err)
+	}
+	if n > 0 {
+		log.Printf("Scheduler: published %d scheduled tenders\n", n)
+	}
+
+	n, err = app.service.CloseExpiredTenders(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Println("Scheduler: closing expired tenders:", err)
+	}
+	if n > 0 {
+		log.Printf("Scheduler: closed %d tenders with passed submission deadline\n", n)
+	}
+
+	n, err = app.service.StartDueAuctions(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Println("Scheduler: starting due auctions:", err)
+	}
+	if n > 0 {
+		log.Printf("Scheduler: started %d auctions\n", n)
+	}
+
+	n, err = app.service.CloseExpiredAuctions(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Println("Scheduler: closing expired auctions:", err)
+	}
+	if n > 0 {
+		log.Printf("Scheduler: closed %d auctions\n", n)
+	}
+}
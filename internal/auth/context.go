@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"tenders/internal/models"
+)
+
+type ctxKey int
+
+const (
+	userKey ctxKey = iota
+	apiKeyKey
+	requestIdKey
+	legacyKey
+)
+
+// WithUser returns copy of ctx carrying authenticated user
+func WithUser(ctx context.Context, user models.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext returns user previously stored by WithUser
+func UserFromContext(ctx context.Context) (models.User, bool) {
+	user, ok := ctx.Value(userKey).(models.User)
+	return user, ok
+}
+
+// WithLegacyUser is the same as WithUser, but marks user as identified by username only, without credentials
+func WithLegacyUser(ctx context.Context, user models.User) context.Context {
+	return context.WithValue(WithUser(ctx, user), legacyKey, true)
+}
+
+// IsLegacy reports whether user of ctx is stored by WithLegacyUser
+func IsLegacy(ctx context.Context) bool {
+	legacy, _ := ctx.Value(legacyKey).(bool)
+	return legacy
+}
+
+// WithAPIKey returns copy of ctx carrying API key request is authenticated with
+func WithAPIKey(ctx context.Context, key models.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// APIKeyFromContext returns API key previously stored by WithAPIKey
+func APIKeyFromContext(ctx context.Context) (models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey).(models.APIKey)
+	return key, ok
+}
+
+// WithRequestID returns copy of ctx carrying id of request being served
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey, id)
+}
+
+// RequestIDFromContext returns request id previously stored by WithRequestID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey).(string)
+	return id
+}
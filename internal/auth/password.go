@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	passwordIterations = 100000
+	passwordSaltSize   = 16
+	passwordKeySize    = 32
+)
+
+// HashPassword returns salted PBKDF2-SHA256 hash of password in form "pbkdf2-sha256$iterations$salt$key"
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("auth.HashPassword: %w", err)
+	}
+
+	key := pbkdf2([]byte(password), salt, passwordIterations, passwordKeySize)
+	return strings.Join([]string{
+		"pbkdf2-sha256",
+		strconv.Itoa(passwordIterations),
+		encoding.EncodeToString(salt),
+		encoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// CheckPassword reports whether password matches hash produced by HashPassword
+func CheckPassword(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	key, err := encoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(key, pbkdf2([]byte(password), salt, iterations, len(key)))
+}
+
+// RandomSecret returns n random bytes encoded as base64 string
+func RandomSecret(n int) (string, error) {
+	buf := make([]byte, n)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("auth.RandomSecret: %w", err)
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// pbkdf2 implements PBKDF2 key derivation (RFC 8018) with HMAC-SHA256
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var buf [4]byte
+	dk := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf[:], uint32(block))
+		prf.Write(buf[:])
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = u[:0]
+			u = prf.Sum(u)
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+	return dk[:keyLen]
+}
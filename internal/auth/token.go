@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("token is malformed or has invalid signature")
+	ErrTokenExpired = errors.New("token is expired")
+)
+
+// Claims is a set of JWT claims issued by the service
+type Claims struct {
+	Subject   string `json:"sub"`
+	Username  string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// NewToken returns HS256 signed JWT containing provided claims
+func NewToken(claims Claims, secret []byte) (string, error) {
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("auth.NewToken: %w", err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("auth.NewToken: %w", err)
+	}
+
+	unsigned := encoding.EncodeToString(h) + "." + encoding.EncodeToString(c)
+	return unsigned + "." + encoding.EncodeToString(sign(unsigned, secret)), nil
+}
+
+// ParseToken validates signature and expiry of HS256 signed JWT and returns its claims
+func ParseToken(token string, secret []byte, now time.Time) (Claims, error) {
+	var claims Claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrInvalidToken
+	}
+
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	if !hmac.Equal(sig, sign(parts[0]+"."+parts[1], secret)) {
+		return claims, ErrInvalidToken
+	}
+
+	var h header
+	data, err := encoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(data, &h) != nil || h.Alg != "HS256" {
+		return claims, ErrInvalidToken
+	}
+
+	data, err = encoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(data, &claims) != nil || len(claims.Subject) == 0 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+func sign(data string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToken(t *testing.T) {
+	secret := []byte("test secret")
+	now := time.Now()
+	claims := Claims{
+		Subject:   "00000000-0000-0000-0000-000000000001",
+		Username:  "test",
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+
+	token, err := NewToken(claims, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseToken(token, secret, now)
+	if err != nil {
+		t.Fatalf("Could not parse issued token: %s", err)
+	}
+	if parsed != claims {
+		t.Errorf("Parsed claims differ from issued: expected %v, got %v", claims, parsed)
+	}
+
+	_, err = ParseToken(token, []byte("other secret"), now)
+	if err != ErrInvalidToken {
+		t.Errorf("Expected token signed with other secret to be invalid, got %v", err)
+	}
+
+	_, err = ParseToken(token+"a", secret, now)
+	if err != ErrInvalidToken {
+		t.Errorf("Expected tampered token to be invalid, got %v", err)
+	}
+
+	_, err = ParseToken(token, secret, now.Add(2*time.Hour))
+	if err != ErrTokenExpired {
+		t.Errorf("Expected token to be expired, got %v", err)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CheckPassword("password", hash) {
+		t.Error("Password does not match its own hash")
+	}
+	if CheckPassword("other password", hash) {
+		t.Error("Wrong password matches hash")
+	}
+	if CheckPassword("password", "") {
+		t.Error("Password matches empty hash")
+	}
+}
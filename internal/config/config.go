@@ -1,47 +1,77 @@
-package config
-
-import (
-	"fmt"
-
-	"github.com/caarlos0/env/v6"
-)
-
-type Config struct {
-	ServerAddress string `env:"SERVER_ADDRESS" envDefault:"0.0.0.0:8080"`
-	LogLevel      string `env:"LOG_LEVEL" envDefault:"DEBUG"`
-	PostgresConfig
-}
-
-func NewConfig() (*Config, error) {
-	config := &Config{}
-
-	err := env.Parse(config)
-	if err != nil {
-		err = fmt.Errorf("config.NewConfig: %w", err)
-	}
-	return config, err
-}
-
-type PostgresConfig struct {
-	Conn            string `env:"POSTGRES_CONN" envDefault:"postgres://test:test@db:5432/test?sslmode=disable"`
-	Host            string `env:"POSTGRES_HOST" envDefault:"db"`
-	Port            string `env:"POSTGRES_PORT" envDefault:"8812"`
-	Username        string `env:"POSTGRES_USERNAME" envDefault:"admin"`
-	Password        string `env:"POSTGRES_PASSWORD" envDefault:"quest"`
-	Database        string `env:"POSTGRES_DATABASE" envDefault:"qdb"`
-	AutoMigrateUp   string `env:"AUTO_MIGRATE_UP" envDefault:"true"`
-	AutoMigrateDown string `env:"AUTO_MIGRATE_DOWN" envDefault:"false"`
-	MigrationsURL   string `env:"MIGRATIONS_URL" envDefault:"file://D:/Work/Avito/Repos/Internship/zadanie-6105/internal/repository/db/migrations"`
-}
-
-//"file://D:/Work/Avito/Repos/Internship/zadanie-6105/internal/repository/db/migrations"
-
-func NewPostgresConfig() (*PostgresConfig, error) {
-	config := &PostgresConfig{}
-
-	err := env.Parse(config)
-	if err != nil {
-		err = fmt.Errorf("config.NewPostgresConfig: %w", err)
-	}
-	return config, err
-}
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+)
+
+type Config struct {
+	ServerAddress string `env:"SERVER_ADDRESS" envDefault:"0.0.0.0:8080"`
+	LogLevel      string `env:"LOG_LEVEL" envDefault:"DEBUG"`
+	// Period of background jobs, such as closing of tenders with passed submission deadline
+	SchedulerInterval time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1m"`
+	PostgresConfig
+	AuthConfig
+	StorageConfig
+}
+
+func NewConfig() (*Config, error) {
+	config := &Config{}
+
+	err := env.Parse(config)
+	if err != nil {
+		err = fmt.Errorf("config.NewConfig: %w", err)
+	}
+	return config, err
+}
+
+type PostgresConfig struct {
+	Conn            string `env:"POSTGRES_CONN" envDefault:"postgres://test:test@db:5432/test?sslmode=disable"`
+	Host            string `env:"POSTGRES_HOST" envDefault:"db"`
+	Port            string `env:"POSTGRES_PORT" envDefault:"8812"`
+	Username        string `env:"POSTGRES_USERNAME" envDefault:"admin"`
+	Password        string `env:"POSTGRES_PASSWORD" envDefault:"quest"`
+	Database        string `env:"POSTGRES_DATABASE" envDefault:"qdb"`
+	AutoMigrateUp   string `env:"AUTO_MIGRATE_UP" envDefault:"true"`
+	AutoMigrateDown string `env:"AUTO_MIGRATE_DOWN" envDefault:"false"`
+	MigrationsURL   string `env:"MIGRATIONS_URL" envDefault:"file://D:/Work/Avito/Repos/Internship/zadanie-6105/internal/repository/db/migrations"`
+}
+
+//"file://D:/Work/Avito/Repos/Internship/zadanie-6105/internal/repository/db/migrations"
+
+func NewPostgresConfig() (*PostgresConfig, error) {
+	config := &PostgresConfig{}
+
+	err := env.Parse(config)
+	if err != nil {
+		err = fmt.Errorf("config.NewPostgresConfig: %w", err)
+	}
+	return config, err
+}
+
+type AuthConfig struct {
+	// Secret used to sign access tokens, random one is generated on startup if empty
+	JWTSecret string        `env:"JWT_SECRET"`
+	JWTExpiry time.Duration `env:"JWT_EXPIRY" envDefault:"24h"`
+	// Allows identification by 'username' query parameter for requests without bearer token
+	LegacyUsernameAuth bool `env:"LEGACY_USERNAME_AUTH" envDefault:"false"`
+	// Time given to employee to set password with one-time token
+	PasswordTokenExpiry time.Duration `env:"PASSWORD_TOKEN_EXPIRY" envDefault:"72h"`
+	// Time given to invitee to accept organization membership invitation
+	InvitationExpiry time.Duration `env:"INVITATION_EXPIRY" envDefault:"168h"`
+	// Usernames of employees managing service-wide settings, such as service categories
+	Administrators []string `env:"ADMIN_USERNAMES" envSeparator:","`
+}
+
+type StorageConfig struct {
+	// Directory of local attachment storage, created if missing
+	AttachmentsDir string `env:"ATTACHMENTS_DIR" envDefault:"attachments"`
+	// Maximum size of single attachment in bytes
+	AttachmentMaxSize int64 `env:"ATTACHMENT_MAX_SIZE" envDefault:"10485760"`
+	// MIME types of accepted attachments, without parameters
+	AttachmentTypes []string `env:"ATTACHMENT_TYPES" envSeparator:"," envDefault:"application/pdf,application/zip,text/plain,text/csv,image/png,image/jpeg,application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document,application/vnd.ms-excel,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"`
+	// Base64-encoded 32-byte master key wrapping keys of sealed tenders, random one is generated on startup if empty
+	SealingKey string `env:"SEALING_KEY"`
+}
@@ -1,688 +1,2691 @@
-package controller
-
-import (
-	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"net/url"
-	"strconv"
-	"tenders/internal/models"
-)
-
-type Service interface {
-	AddTender(ctx context.Context, username string, tender models.Tender) (models.Tender, error)
-	GetTenders(ctx context.Context, limit, offset int, tenderId, userId string, serviceType []models.ServiceType) ([]models.Tender, error)
-	GetUserTenders(ctx context.Context, username string, limit, offset int) ([]models.Tender, error)
-	GetTenderStatus(ctx context.Context, username, tenderId string) (models.TenderStatus, error)
-	SetTenderStatus(ctx context.Context, username, tenderId string, status models.TenderStatus) (models.Tender, error)
-	EditTender(ctx context.Context, username, tenderId string, changes map[string]string) (models.Tender, error)
-	RollbackTender(ctx context.Context, username, tenderId string, version int) (models.Tender, error)
-
-	AddBid(ctx context.Context, bid models.Bid) (models.Bid, error)
-	GetUserBids(ctx context.Context, username string, limit, offset int) ([]models.Bid, error)
-	GetTenderBids(ctx context.Context, username, tenderId string, limit, offset int) ([]models.Bid, error)
-	GetBidStatus(ctx context.Context, username, bidId string) (models.BidStatus, error)
-	SetBidStatus(ctx context.Context, username, bidId string, status models.BidStatus) (models.Bid, error)
-	EditBid(ctx context.Context, username, bidId string, changes map[string]string) (models.Bid, error)
-	BidApproval(ctx context.Context, username, bidId string, status models.ApproveType) (models.Bid, error)
-	BidFeedback(ctx context.Context, username, bidId, feedback string) (models.Bid, error)
-	BidRollback(ctx context.Context, username, bidId string, version int) (models.Bid, error)
-	PastUserBidsReviews(ctx context.Context, tenderId, requesterName, authorName string, limit, offset int) ([]models.BidReview, error)
-}
-
-type Controller struct {
-	service Service
-}
-
-func NewController(service Service) *Controller {
-	return &Controller{service: service}
-}
-
-//// Tenders
-
-// GET /api/ping
-func (c *Controller) Ping(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, "ok")
-}
-
-// GET /api/tenders
-func (c *Controller) GetTenders(w http.ResponseWriter, r *http.Request) {
-	var serviceTypes []models.ServiceType
-
-	query := r.URL.Query()
-
-	limit, err := c.getQueryInt(query, "limit")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	offset, err := c.getQueryInt(query, "offset")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	for _, str := range query["service_type"] {
-		t := models.ServiceType(str)
-		if models.ValidServiceType(t) {
-			serviceTypes = append(serviceTypes, t)
-			continue
-		}
-		c.errorResponse(w, http.StatusBadRequest, "invalid service type supplied: "+string(t))
-		return
-	}
-
-	tenders, err := c.service.GetTenders(r.Context(), limit, offset, "", "", serviceTypes)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not fetch tenders")
-		return
-	}
-
-	c.marshalResponse(w, tenders)
-}
-
-// POST /api/tenders/new
-func (c *Controller) NewTender(w http.ResponseWriter, r *http.Request) {
-	data, err := c.readBody(r.Body)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
-		return
-	}
-
-	req, err := ParseNewTenderReq(data)
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	tender, err := c.service.AddTender(r.Context(), req.AuthorUsername, models.Tender{
-		Name:           req.Name,
-		Description:    req.Description,
-		ServiceType:    req.ServiceType,
-		Status:         req.Status,
-		OrganizationId: req.OrganizationId,
-	})
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, tender)
-}
-
-// GET /api/tenders/my
-func (c *Controller) MyTenders(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	limit, err := c.getQueryInt(query, "limit")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	offset, err := c.getQueryInt(query, "offset")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	tenders, err := c.service.GetUserTenders(r.Context(), username, limit, offset)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, tenders)
-}
-
-// GET /api/tenders/{tenderId}/status
-func (c *Controller) TenderStatus(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	tenderId := r.PathValue("tenderId")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
-		return
-	}
-
-	status, err := c.service.GetTenderStatus(r.Context(), username, tenderId)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	fmt.Fprint(w, status)
-}
-
-// PUT /api/tenders/{tenderId}/status
-func (c *Controller) SetTenderStatus(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	tenderId := r.PathValue("tenderId")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
-		return
-	}
-
-	status := models.TenderStatus(query.Get("status"))
-	if !models.ValidTenderStatus(status) {
-		c.errorResponse(w, http.StatusBadRequest, "empty or invalid status supplied")
-		return
-	}
-
-	tender, err := c.service.SetTenderStatus(r.Context(), username, tenderId, status)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, tender)
-}
-
-// PATCH /api/tenders/{tenderId}/edit
-func (c *Controller) EditTender(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	tenderId := r.PathValue("tenderId")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
-		return
-	}
-
-	data, err := c.readBody(r.Body)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
-		return
-	}
-	req, err := ParseTenderChangeReq(data)
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	tender, err := c.service.EditTender(r.Context(), username, tenderId, req)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, tender)
-}
-
-// PUT /api/tenders/{tenderId}/rollback/{version}
-func (c *Controller) RollbackTender(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	tenderId := r.PathValue("tenderId")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
-		return
-	}
-
-	versionStr := r.PathValue("version")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty version supplied")
-		return
-	}
-
-	version, err := strconv.Atoi(versionStr)
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "malformed version number supplied")
-		return
-	}
-
-	tender, err := c.service.RollbackTender(r.Context(), username, tenderId, version)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, tender)
-}
-
-//// Bids
-
-// POST /api/bids/new
-func (c *Controller) NewBid(w http.ResponseWriter, r *http.Request) {
-	data, err := c.readBody(r.Body)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
-		return
-	}
-
-	req, err := ParseNewBidReq(data)
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	bid, err := c.service.AddBid(r.Context(), models.Bid{
-		Name:        req.Name,
-		Description: req.Description,
-		Status:      models.BidCreated,
-		TenderId:    req.TenderId,
-		AuthorType:  req.AuthorType,
-		AuthorId:    req.AuthorId,
-	})
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bid)
-}
-
-// GET /api/bids/my
-func (c *Controller) MyBids(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	limit, err := c.getQueryInt(query, "limit")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	offset, err := c.getQueryInt(query, "offset")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	bids, err := c.service.GetUserBids(r.Context(), username, limit, offset)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bids)
-}
-
-// GET /api/bids/{tenderId}/list
-func (c *Controller) TenderBids(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	limit, err := c.getQueryInt(query, "limit")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	offset, err := c.getQueryInt(query, "offset")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	tenderId := r.PathValue("tenderId")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
-		return
-	}
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	bids, err := c.service.GetTenderBids(r.Context(), username, tenderId, limit, offset)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bids)
-}
-
-// GET /api/bids/{bidId}/status
-func (c *Controller) BidStatus(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	bidId := r.PathValue("bidId")
-	if len(bidId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
-		return
-	}
-
-	status, err := c.service.GetBidStatus(r.Context(), username, bidId)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-	}
-
-	fmt.Fprint(w, status)
-}
-
-// PUT /api/bids/{bidId}/status
-func (c *Controller) SetBidStatus(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-
-	status := query.Get("status")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty status supplied")
-		return
-	}
-
-	bidId := r.PathValue("bidId")
-	if len(bidId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
-		return
-	}
-
-	bid, err := c.service.SetBidStatus(r.Context(), username, bidId, models.BidStatus(status))
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-	}
-
-	c.marshalResponse(w, bid)
-}
-
-// PATCH /api/bids/{bidId}/edit
-func (c *Controller) EditBid(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-	bidId := r.PathValue("bidId")
-	if len(bidId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
-		return
-	}
-
-	data, err := c.readBody(r.Body)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
-		return
-	}
-
-	input := map[string]string{}
-	changes := map[string]string{}
-	err = json.Unmarshal(data, &input)
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "could not parse json")
-		return
-	}
-
-	if str, ok := input["name"]; ok {
-		if len(str) > 100 {
-			c.errorResponse(w, http.StatusBadRequest, "field name exceeds max length")
-			return
-		}
-		changes["name"] = str
-	}
-
-	if str, ok := input["description"]; ok {
-		if len(str) > 100 {
-			c.errorResponse(w, http.StatusBadRequest, "field description exceeds max length")
-			return
-		}
-		changes["description"] = str
-	}
-
-	bid, err := c.service.EditBid(r.Context(), username, bidId, changes)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bid)
-}
-
-// PUT /api/bids/{bidId}/submit_decision
-func (c *Controller) BidDecision(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-	bidId := r.PathValue("bidId")
-	if len(bidId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
-		return
-	}
-	decision := models.ApproveType(query.Get("decision"))
-	if len(decision) == 0 || !models.ValidApproveType(decision) {
-		c.errorResponse(w, http.StatusBadRequest, "empty or invalid decision supplied")
-		return
-	}
-
-	bid, err := c.service.BidApproval(r.Context(), username, bidId, decision)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bid)
-}
-
-// PUT /api/bids/{bidId}/feedback
-func (c *Controller) BidReview(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-	bidId := r.PathValue("bidId")
-	if len(bidId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
-		return
-	}
-	feedback := query.Get("bidFeedback")
-	if len(feedback) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidFeedback supplied")
-		return
-	}
-
-	bid, err := c.service.BidFeedback(r.Context(), username, bidId, feedback)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bid)
-}
-
-// PUT /api/bids/{bidId}/rollback/{version}
-func (c *Controller) BidRollback(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	username := query.Get("username")
-	if len(username) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty username supplied")
-		return
-	}
-	bidId := r.PathValue("bidId")
-	if len(bidId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
-		return
-	}
-	version, err := c.getQueryInt(query, "version")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'version' query parameter: "+query.Get("limit"))
-		return
-	}
-
-	bid, err := c.service.BidRollback(r.Context(), username, bidId, version)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, bid)
-}
-
-// GET /api/bids/{tenderId}/reviews
-func (c *Controller) GetBidReviews(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-
-	authorUsername := query.Get("authorUsername")
-	if len(authorUsername) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty authorUsername supplied")
-		return
-	}
-	requesterUsername := query.Get("requesterUsername")
-	if len(requesterUsername) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty requesterUsername supplied")
-		return
-	}
-	limit, err := c.getQueryInt(query, "limit")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-	offset, err := c.getQueryInt(query, "offset")
-	if err != nil {
-		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
-		return
-	}
-	tenderId := r.PathValue("tenderId")
-	if len(tenderId) == 0 {
-		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
-		return
-	}
-
-	reviews, err := c.service.PastUserBidsReviews(r.Context(), tenderId, requesterUsername, authorUsername, limit, offset)
-	if err != nil {
-		c.serviceErrorResponse(w, err)
-		return
-	}
-
-	c.marshalResponse(w, reviews)
-}
-
-// Service
-
-type ErrorResponse struct {
-	Reason string `json:"reason"`
-}
-
-func (c *Controller) getQueryInt(query url.Values, key string) (int, error) {
-	strs, ok := query[key]
-	if ok && len(strs) > 0 {
-		return strconv.Atoi(strs[0])
-	}
-	return 0, nil
-}
-
-func (c *Controller) errorResponse(w http.ResponseWriter, status int, text string) {
-	w.WriteHeader(status)
-
-	data, err := json.Marshal(ErrorResponse{Reason: text})
-	if err != nil {
-		log.Printf("contorller.Controller.errorResponse: %s", err)
-		return
-	}
-
-	_, err = w.Write(data)
-	if err != nil {
-		log.Printf("contorller.Controller.errorResponse: %s", err)
-		return
-	}
-}
-
-func (c *Controller) serviceErrorResponse(w http.ResponseWriter, err error) {
-	switch {
-	case errors.Is(err, models.ErrInvalidUser):
-		c.errorResponse(w, http.StatusUnauthorized, "user does not exist or have no rights for requested action")
-	case errors.Is(err, models.ErrForbidden):
-		c.errorResponse(w, http.StatusForbidden, "user have no permission for requested action")
-	case errors.Is(err, models.ErrNoTender):
-		c.errorResponse(w, http.StatusNotFound, "requested tender does not exist or unacessible")
-	case errors.Is(err, models.ErrTenderFinalized):
-		c.errorResponse(w, http.StatusForbidden, "requested tender is already closed, status cannot be changed")
-	case errors.Is(err, models.ErrNoBid):
-		c.errorResponse(w, http.StatusNotFound, "requested bid does not exist or unacessible")
-	case errors.Is(err, models.ErrNoVersion):
-		c.errorResponse(w, http.StatusNotFound, "requested version does not exist")
-	case errors.Is(err, models.ErrBidFinalized):
-		c.errorResponse(w, http.StatusForbidden, "requested bid is already approved or rejected, status cannot be changed")
-	case errors.Is(err, models.ErrBidCannotBeApprovedYet):
-		c.errorResponse(w, http.StatusForbidden, "requested bid does not have enough votes to be approved")
-	default:
-		log.Println("controller:", err)
-		c.errorResponse(w, http.StatusInternalServerError, "internal server error: "+err.Error())
-	}
-}
-
-func (c *Controller) marshalResponse(w http.ResponseWriter, data any) {
-	d, err := json.Marshal(data)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not marhsal response data")
-		return
-	}
-
-	_, err = w.Write(d)
-	if err != nil {
-		c.errorResponse(w, http.StatusInternalServerError, "could not write response data")
-		return
-	}
-}
-
-func (c *Controller) readBody(src io.ReadCloser) ([]byte, error) {
-	data, err := io.ReadAll(src)
-	if err != nil {
-		return nil, err
-	}
-	src.Close()
-	return data, nil
-}
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"tenders/internal/models"
+	"time"
+)
+
+type Service interface {
+	AddTender(ctx context.Context, tender models.Tender) (models.Tender, error)
+	GetTenders(ctx context.Context, limit, offset int, filter models.TenderFilter) ([]models.Tender, error)
+	GetUserTenders(ctx context.Context, limit, offset int, filter models.TenderFilter) ([]models.Tender, error)
+	GetTenderStatus(ctx context.Context, tenderId string) (models.Tender, error)
+	SetTenderStatus(ctx context.Context, tenderId string, status models.TenderStatus, reason string, expectedVersion int) (models.Tender, error)
+	EditTender(ctx context.Context, tenderId string, changes map[string]any, reason string, expectedVersion int) (models.Tender, error)
+	RollbackTender(ctx context.Context, tenderId string, version int, reason string, expectedVersion int) (models.Tender, error)
+	OpenTenderBids(ctx context.Context, tenderId string) (models.Tender, error)
+	GetTenderAmendments(ctx context.Context, tenderId string, limit, offset int) ([]models.Amendment, error)
+	GetTenderVersions(ctx context.Context, tenderId string, limit, offset int) ([]models.Tender, error)
+	DiffTenderVersions(ctx context.Context, tenderId string, from, to int) (models.VersionDiff, error)
+	GetTenderCriteria(ctx context.Context, tenderId string) ([]models.Criterion, error)
+	SetTenderCriteria(ctx context.Context, tenderId string, criteria []models.Criterion) ([]models.Criterion, error)
+	GetTenderLots(ctx context.Context, tenderId string) ([]models.Lot, error)
+	SetTenderLots(ctx context.Context, tenderId string, lots []models.Lot) ([]models.Lot, error)
+	SetLotStatus(ctx context.Context, tenderId, lotId string, status models.LotStatus) (models.Lot, error)
+	GetInvitedOrganizations(ctx context.Context, tenderId string) ([]models.InvitedOrganization, error)
+	SetInvitedOrganizations(ctx context.Context, tenderId string, organizationIds []string) ([]models.InvitedOrganization, error)
+	CloneTender(ctx context.Context, sourceId, name string) (models.Tender, error)
+
+	GetTenderAttachments(ctx context.Context, tenderId string) ([]models.Attachment, error)
+	AddTenderAttachment(ctx context.Context, tenderId string, attachment models.Attachment, content io.Reader) (models.Attachment, error)
+	OpenTenderAttachment(ctx context.Context, tenderId, attachmentId string) (models.Attachment, io.ReadCloser, error)
+	DeleteTenderAttachment(ctx context.Context, tenderId, attachmentId string) error
+
+	GetTemplates(ctx context.Context, organizationId string, limit, offset int) ([]models.Template, error)
+	AddTemplate(ctx context.Context, template models.Template) (models.Template, error)
+	DeleteTemplate(ctx context.Context, organizationId, templateId string) error
+
+	AskQuestion(ctx context.Context, tenderId, text string) (models.Question, error)
+	GetTenderQuestions(ctx context.Context, tenderId string, limit, offset int) ([]models.Question, error)
+	AnswerQuestion(ctx context.Context, tenderId, questionId, answer string) (models.Question, error)
+
+	GetCategories(ctx context.Context, includeInactive bool) ([]models.Category, error)
+	AddCategory(ctx context.Context, category models.Category) (models.Category, error)
+	EditCategory(ctx context.Context, code models.ServiceType, changes map[string]any) (models.Category, error)
+
+	AddBid(ctx context.Context, bid models.Bid) (models.Bid, error)
+	GetUserBids(ctx context.Context, limit, offset int, filter models.BidFilter) ([]models.Bid, error)
+	GetTenderBids(ctx context.Context, tenderId string, limit, offset int, filter models.BidFilter) ([]models.Bid, error)
+	GetBidStatus(ctx context.Context, bidId string) (models.Bid, error)
+	SetBidStatus(ctx context.Context, bidId string, status models.BidStatus, expectedVersion int) (models.Bid, error)
+	EditBid(ctx context.Context, bidId string, changes map[string]any, expectedVersion int) (models.Bid, error)
+	ReconfirmBid(ctx context.Context, bidId string) (models.Bid, error)
+	WithdrawBid(ctx context.Context, bidId, reason string, expectedVersion int) (models.Bid, error)
+	ResubmitBid(ctx context.Context, bidId, reason string, expectedVersion int) (models.Bid, error)
+	GetBidEvents(ctx context.Context, tenderId, bidId string, limit, offset int) ([]models.BidEvent, error)
+	BidApproval(ctx context.Context, bidId, lotId string, status models.ApproveType) (models.Bid, error)
+	BidFeedback(ctx context.Context, bidId, feedback string) (models.Bid, error)
+	BidRollback(ctx context.Context, bidId string, version, expectedVersion int) (models.Bid, error)
+	GetBidVersions(ctx context.Context, bidId string, limit, offset int) ([]models.Bid, error)
+	DiffBidVersions(ctx context.Context, bidId string, from, to int) (models.VersionDiff, error)
+	PastUserBidsReviews(ctx context.Context, tenderId, authorName string, limit, offset int) ([]models.BidReview, error)
+	ScoreBid(ctx context.Context, bidId string, scores []models.BidScore) ([]models.BidScore, error)
+	GetBidScores(ctx context.Context, bidId string) ([]models.BidScore, error)
+	GetTenderRanking(ctx context.Context, tenderId string) ([]models.BidRanking, error)
+	SetTenderAuction(ctx context.Context, auction models.Auction) (models.Auction, error)
+	GetTenderAuction(ctx context.Context, tenderId string) (models.Auction, error)
+	PlaceAuctionPrice(ctx context.Context, bidId string, price models.Money) (models.AuctionPrice, error)
+	GetAuctionRanking(ctx context.Context, tenderId string) (models.AuctionRanking, error)
+	GetAuctionLedger(ctx context.Context, tenderId string, limit, offset int) ([]models.AuctionPrice, error)
+	GetBidAttachments(ctx context.Context, bidId string) ([]models.Attachment, error)
+	AddBidAttachment(ctx context.Context, bidId string, attachment models.Attachment, content io.Reader) (models.Attachment, error)
+	OpenBidAttachment(ctx context.Context, bidId, attachmentId string) (models.Attachment, io.ReadCloser, error)
+	DeleteBidAttachment(ctx context.Context, bidId, attachmentId string) error
+
+	Login(ctx context.Context, username, password string) (string, time.Time, error)
+	SetPassword(ctx context.Context, currentPassword, password string) error
+	SetPasswordWithToken(ctx context.Context, token, password string) error
+
+	CreateAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, string, error)
+	GetAPIKeys(ctx context.Context, organizationId string) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, organizationId, keyId string) error
+
+	AddEmployee(ctx context.Context, user models.User, password string) (models.User, string, time.Time, error)
+	GetEmployees(ctx context.Context, limit, offset int) ([]models.User, error)
+	GetEmployee(ctx context.Context, employeeId string) (models.User, error)
+	EditEmployee(ctx context.Context, employeeId string, changes map[string]string) (models.User, error)
+	DeleteEmployee(ctx context.Context, employeeId string) error
+
+	AddOrganization(ctx context.Context, org models.Organization) (models.Organization, error)
+	GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error)
+	GetOrganization(ctx context.Context, organizationId string) (models.Organization, error)
+	EditOrganization(ctx context.Context, organizationId string, changes map[string]string) (models.Organization, error)
+	DeleteOrganization(ctx context.Context, organizationId string) error
+
+	GetMembers(ctx context.Context, organizationId string, limit, offset int) ([]models.Member, error)
+	SetMemberRole(ctx context.Context, organizationId, employeeId string, role models.Role) (models.Member, error)
+	RemoveMember(ctx context.Context, organizationId, employeeId string) error
+	ResetMemberPassword(ctx context.Context, organizationId, employeeId string) (string, time.Time, error)
+
+	InviteMember(ctx context.Context, organizationId, username string, role models.Role) (models.Invitation, string, error)
+	GetInvitations(ctx context.Context, organizationId string, status models.InvitationStatus, limit, offset int) ([]models.Invitation, error)
+	RevokeInvitation(ctx context.Context, organizationId, invitationId string) error
+	GetUserInvitations(ctx context.Context) ([]models.Invitation, error)
+	RespondInvitation(ctx context.Context, token string, accept bool) (models.Invitation, error)
+
+	GetAuditLog(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]models.AuditEntry, error)
+}
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+//// Tenders
+
+// GET /api/ping
+func (c *Controller) Ping(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// GET /api/tenders
+func (c *Controller) GetTenders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	categories, err := c.service.GetCategories(r.Context(), true)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	filter, err := ParseTenderFilter(query, categories)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenders, err := c.service.GetTenders(r.Context(), limit, offset, filter)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not fetch tenders")
+		return
+	}
+
+	c.marshalResponse(w, tenders)
+}
+
+// POST /api/tenders/new
+func (c *Controller) NewTender(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	categories, err := c.service.GetCategories(r.Context(), true)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	req, err := ParseNewTenderReq(data, categories)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tender, err := c.service.AddTender(r.Context(), models.Tender{
+		Name:               req.Name,
+		Description:        req.Description,
+		ServiceType:        req.ServiceType,
+		Status:             req.Status,
+		OrganizationId:     req.OrganizationId,
+		Budget:             req.Budget,
+		BudgetConfidential: req.BudgetConfidential,
+		Visibility:         req.Visibility,
+		PublishAt:          req.PublishAt,
+		SubmissionDeadline: req.SubmissionDeadline,
+		Sealed:             req.Sealed,
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, tender.Version, tender)
+}
+
+// GET /api/tenders/my
+func (c *Controller) MyTenders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	categories, err := c.service.GetCategories(r.Context(), true)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	filter, err := ParseTenderFilter(query, categories)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenders, err := c.service.GetUserTenders(r.Context(), limit, offset, filter)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, tenders)
+}
+
+// GET /api/tenders/{tenderId}/status
+func (c *Controller) TenderStatus(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	tender, err := c.service.GetTenderStatus(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(tender.Version))
+	fmt.Fprint(w, tender.Status)
+}
+
+// PUT /api/tenders/{tenderId}/status
+func (c *Controller) SetTenderStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	// tenders are awarded by bid decisions
+	status := models.TenderStatus(query.Get("status"))
+	if !models.ValidTenderStatus(status) || status == models.TenderAwarded {
+		c.errorResponse(w, http.StatusBadRequest, "empty or invalid status supplied")
+		return
+	}
+
+	reason := strings.TrimSpace(query.Get("reason"))
+	if err := checkLengthLimit(reason, "reason", 500); err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tender, err := c.service.SetTenderStatus(r.Context(), tenderId, status, reason, version)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, tender.Version, tender)
+}
+
+// PATCH /api/tenders/{tenderId}/edit
+func (c *Controller) EditTender(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+	categories, err := c.service.GetCategories(r.Context(), true)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	req, err := ParseTenderChangeReq(data, categories)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	reason, err := ParseAmendmentReason(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tender, err := c.service.EditTender(r.Context(), tenderId, req, reason, version)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, tender.Version, tender)
+}
+
+// PUT /api/tenders/{tenderId}/rollback/{version}
+func (c *Controller) RollbackTender(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	versionStr := r.PathValue("version")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty version supplied")
+		return
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "malformed version number supplied")
+		return
+	}
+
+	// required to roll back published tender with submitted bids to materially different version
+	reason := strings.TrimSpace(r.URL.Query().Get("reason"))
+	if err = checkLengthLimit(reason, "reason", 500); err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	expectedVersion, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tender, err := c.service.RollbackTender(r.Context(), tenderId, version, reason, expectedVersion)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, tender.Version, tender)
+}
+
+// POST /api/tenders/{tenderId}/open
+func (c *Controller) OpenTenderBids(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	tender, err := c.service.OpenTenderBids(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, tender.Version, tender)
+}
+
+// GET /api/tenders/{tenderId}/amendments
+func (c *Controller) TenderAmendments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	amendments, err := c.service.GetTenderAmendments(r.Context(), tenderId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, amendments)
+}
+
+// GET /api/tenders/{tenderId}/versions
+func (c *Controller) TenderVersions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	versions, err := c.service.GetTenderVersions(r.Context(), tenderId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, versions)
+}
+
+// GET /api/tenders/{tenderId}/versions/diff
+func (c *Controller) TenderVersionsDiff(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	from, to, err := ParseVersionDiffReq(r.URL.Query())
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	diff, err := c.service.DiffTenderVersions(r.Context(), tenderId, from, to)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, diff)
+}
+
+//// Bids
+
+// POST /api/bids/new
+func (c *Controller) NewBid(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseNewBidReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bid, err := c.service.AddBid(r.Context(), models.Bid{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      models.BidCreated,
+		TenderId:    req.TenderId,
+		AuthorType:  req.AuthorType,
+		AuthorId:    req.AuthorId,
+		Price:       req.Price,
+		LotIds:      req.LotIds,
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// GET /api/bids/my
+func (c *Controller) MyBids(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	filter, err := ParseBidFilter(query)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bids, err := c.service.GetUserBids(r.Context(), limit, offset, filter)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, bids)
+}
+
+// GET /api/bids/{tenderId}/list
+func (c *Controller) TenderBids(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	filter, err := ParseBidFilter(query)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bids, err := c.service.GetTenderBids(r.Context(), tenderId, limit, offset, filter)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, bids)
+}
+
+// GET /api/bids/{bidId}/status
+func (c *Controller) BidStatus(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	bid, err := c.service.GetBidStatus(r.Context(), bidId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(bid.Version))
+	fmt.Fprint(w, bid.Status)
+}
+
+// PUT /api/bids/{bidId}/status
+func (c *Controller) SetBidStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	if len(status) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty status supplied")
+		return
+	}
+
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	version, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bid, err := c.service.SetBidStatus(r.Context(), bidId, models.BidStatus(status), version)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// PATCH /api/bids/{bidId}/edit
+func (c *Controller) EditBid(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseBidChangeReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bid, err := c.service.EditBid(r.Context(), bidId, req, version)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// PUT /api/bids/{bidId}/reconfirm
+func (c *Controller) ReconfirmBid(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	bid, err := c.service.ReconfirmBid(r.Context(), bidId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// PUT /api/bids/{bidId}/withdraw
+func (c *Controller) WithdrawBid(w http.ResponseWriter, r *http.Request) {
+	c.changeWithdrawal(w, r, true)
+}
+
+// PUT /api/bids/{bidId}/resubmit
+func (c *Controller) ResubmitBid(w http.ResponseWriter, r *http.Request) {
+	c.changeWithdrawal(w, r, false)
+}
+
+func (c *Controller) changeWithdrawal(w http.ResponseWriter, r *http.Request, withdraw bool) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	// required for withdrawal, optional for resubmission
+	reason := strings.TrimSpace(r.URL.Query().Get("reason"))
+	if err := checkLengthLimit(reason, "reason", 500); err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	change := c.service.ResubmitBid
+	if withdraw {
+		change = c.service.WithdrawBid
+	}
+	bid, err := change(r.Context(), bidId, reason, version)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// GET /api/tenders/{tenderId}/bid_events
+func (c *Controller) BidEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	bidId := query.Get("bidId")
+	if len(bidId) > 0 && !validUUID(bidId) {
+		c.errorResponse(w, http.StatusBadRequest, "invalid bidId supplied: "+bidId)
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	events, err := c.service.GetBidEvents(r.Context(), tenderId, strings.ToLower(bidId), limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, events)
+}
+
+// PUT /api/bids/{bidId}/submit_decision
+func (c *Controller) BidDecision(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+	decision := models.ApproveType(query.Get("decision"))
+	if len(decision) == 0 || !models.ValidApproveType(decision) {
+		c.errorResponse(w, http.StatusBadRequest, "empty or invalid decision supplied")
+		return
+	}
+
+	// required for tenders split into lots
+	lotId := strings.ToLower(query.Get("lotId"))
+	if len(lotId) > 0 && !validUUID(lotId) {
+		c.errorResponse(w, http.StatusBadRequest, "invalid lotId supplied: "+lotId)
+		return
+	}
+
+	bid, err := c.service.BidApproval(r.Context(), bidId, lotId, decision)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// PUT /api/bids/{bidId}/feedback
+func (c *Controller) BidReview(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+	feedback := query.Get("bidFeedback")
+	if len(feedback) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidFeedback supplied")
+		return
+	}
+
+	bid, err := c.service.BidFeedback(r.Context(), bidId, feedback)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// PUT /api/bids/{bidId}/rollback/{version}
+func (c *Controller) BidRollback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+	version, err := c.getQueryInt(query, "version")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'version' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	expectedVersion, err := ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bid, err := c.service.BidRollback(r.Context(), bidId, version, expectedVersion)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, bid.Version, bid)
+}
+
+// GET /api/bids/{bidId}/versions
+func (c *Controller) BidVersions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	versions, err := c.service.GetBidVersions(r.Context(), bidId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, versions)
+}
+
+// GET /api/bids/{bidId}/versions/diff
+func (c *Controller) BidVersionsDiff(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	from, to, err := ParseVersionDiffReq(r.URL.Query())
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	diff, err := c.service.DiffBidVersions(r.Context(), bidId, from, to)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, diff)
+}
+
+// GET /api/bids/{tenderId}/reviews
+func (c *Controller) GetBidReviews(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	authorUsername := query.Get("authorUsername")
+	if len(authorUsername) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty authorUsername supplied")
+		return
+	}
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	reviews, err := c.service.PastUserBidsReviews(r.Context(), tenderId, authorUsername, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, reviews)
+}
+
+//// Evaluation
+
+// GET /api/tenders/{tenderId}/criteria
+func (c *Controller) TenderCriteria(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	criteria, err := c.service.GetTenderCriteria(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, criteria)
+}
+
+// PUT /api/tenders/{tenderId}/criteria
+func (c *Controller) SetTenderCriteria(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseCriteriaReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	criteria, err := c.service.SetTenderCriteria(r.Context(), tenderId, req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, criteria)
+}
+
+// PUT /api/bids/{bidId}/scores
+func (c *Controller) ScoreBid(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseScoresReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scores, err := c.service.ScoreBid(r.Context(), bidId, req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, scores)
+}
+
+// GET /api/bids/{bidId}/scores
+func (c *Controller) BidScores(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	scores, err := c.service.GetBidScores(r.Context(), bidId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, scores)
+}
+
+// GET /api/bids/{tenderId}/ranking
+func (c *Controller) TenderRanking(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	ranking, err := c.service.GetTenderRanking(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, ranking)
+}
+
+//// Auctions
+
+// PUT /api/tenders/{tenderId}/auction
+func (c *Controller) SetTenderAuction(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseAuctionReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	auction, err := c.service.SetTenderAuction(r.Context(), models.Auction{
+		TenderId:         tenderId,
+		StartAt:          *req.StartAt,
+		DurationMinutes:  req.DurationMinutes,
+		ExtensionMinutes: req.ExtensionMinutes,
+		MinDecrement:     *req.MinDecrement,
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, auction)
+}
+
+// GET /api/tenders/{tenderId}/auction
+func (c *Controller) TenderAuction(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	auction, err := c.service.GetTenderAuction(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, auction)
+}
+
+// GET /api/tenders/{tenderId}/auction/ranking
+func (c *Controller) AuctionRanking(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	ranking, err := c.service.GetAuctionRanking(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, ranking)
+}
+
+// GET /api/tenders/{tenderId}/auction/ledger
+func (c *Controller) AuctionLedger(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	prices, err := c.service.GetAuctionLedger(r.Context(), tenderId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, prices)
+}
+
+// POST /api/bids/{bidId}/auction/price
+func (c *Controller) PlaceAuctionPrice(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseAuctionPriceReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	price, err := c.service.PlaceAuctionPrice(r.Context(), bidId, *req.Price)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, price)
+}
+
+//// Lots
+
+// GET /api/tenders/{tenderId}/lots
+func (c *Controller) TenderLots(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	lots, err := c.service.GetTenderLots(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, lots)
+}
+
+// PUT /api/tenders/{tenderId}/lots
+func (c *Controller) SetTenderLots(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseLotsReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lots, err := c.service.SetTenderLots(r.Context(), tenderId, req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, lots)
+}
+
+// PUT /api/tenders/{tenderId}/lots/{lotId}/status
+func (c *Controller) SetLotStatus(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	lotId := r.PathValue("lotId")
+	if len(lotId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty lotId supplied")
+		return
+	}
+
+	// lots are awarded by bid decisions
+	status := models.LotStatus(r.URL.Query().Get("status"))
+	if status != models.LotCancelled {
+		c.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid lot status supplied: %s, should be %s", status, models.LotCancelled))
+		return
+	}
+
+	lot, err := c.service.SetLotStatus(r.Context(), tenderId, lotId, status)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, lot)
+}
+
+//// Visibility
+
+// GET /api/tenders/{tenderId}/invited_organizations
+func (c *Controller) InvitedOrganizations(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	invited, err := c.service.GetInvitedOrganizations(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, invited)
+}
+
+// PUT /api/tenders/{tenderId}/invited_organizations
+func (c *Controller) SetInvitedOrganizations(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseInvitedOrganizationsReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invited, err := c.service.SetInvitedOrganizations(r.Context(), tenderId, req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, invited)
+}
+
+//// Attachments
+
+// GET /api/tenders/{tenderId}/attachments
+func (c *Controller) TenderAttachments(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	attachments, err := c.service.GetTenderAttachments(r.Context(), tenderId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, attachments)
+}
+
+// POST /api/tenders/{tenderId}/attachments
+func (c *Controller) NewTenderAttachment(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	attachment, content, err := c.readAttachment(r)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachment, err = c.service.AddTenderAttachment(r.Context(), tenderId, attachment, content)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, attachment)
+}
+
+// GET /api/tenders/{tenderId}/attachments/{attachmentId}
+func (c *Controller) TenderAttachment(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	attachmentId := r.PathValue("attachmentId")
+	if len(attachmentId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty attachmentId supplied")
+		return
+	}
+
+	attachment, content, err := c.service.OpenTenderAttachment(r.Context(), tenderId, attachmentId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	defer content.Close()
+
+	c.attachmentResponse(w, attachment, content)
+}
+
+// DELETE /api/tenders/{tenderId}/attachments/{attachmentId}
+func (c *Controller) DeleteTenderAttachment(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	attachmentId := r.PathValue("attachmentId")
+	if len(attachmentId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty attachmentId supplied")
+		return
+	}
+
+	err := c.service.DeleteTenderAttachment(r.Context(), tenderId, attachmentId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// GET /api/bids/{bidId}/attachments
+func (c *Controller) BidAttachments(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	attachments, err := c.service.GetBidAttachments(r.Context(), bidId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, attachments)
+}
+
+// POST /api/bids/{bidId}/attachments
+func (c *Controller) NewBidAttachment(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	attachment, content, err := c.readAttachment(r)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachment, err = c.service.AddBidAttachment(r.Context(), bidId, attachment, content)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, attachment)
+}
+
+// GET /api/bids/{bidId}/attachments/{attachmentId}
+func (c *Controller) BidAttachment(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	attachmentId := r.PathValue("attachmentId")
+	if len(attachmentId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty attachmentId supplied")
+		return
+	}
+
+	attachment, content, err := c.service.OpenBidAttachment(r.Context(), bidId, attachmentId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	defer content.Close()
+
+	c.attachmentResponse(w, attachment, content)
+}
+
+// DELETE /api/bids/{bidId}/attachments/{attachmentId}
+func (c *Controller) DeleteBidAttachment(w http.ResponseWriter, r *http.Request) {
+	bidId := r.PathValue("bidId")
+	if len(bidId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty bidId supplied")
+		return
+	}
+
+	attachmentId := r.PathValue("attachmentId")
+	if len(attachmentId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty attachmentId supplied")
+		return
+	}
+
+	err := c.service.DeleteBidAttachment(r.Context(), bidId, attachmentId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+//// Templates
+
+// POST /api/tenders/{tenderId}/clone
+func (c *Controller) CloneTender(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseCloneReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tender, err := c.service.CloneTender(r.Context(), tenderId, req.Name)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.versionedResponse(w, tender.Version, tender)
+}
+
+// GET /api/organizations/{organizationId}/templates
+func (c *Controller) Templates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	templates, err := c.service.GetTemplates(r.Context(), organizationId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, templates)
+}
+
+// POST /api/organizations/{organizationId}/templates
+func (c *Controller) NewTemplate(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	categories, err := c.service.GetCategories(r.Context(), true)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+	req, err := ParseNewTemplateReq(data, categories)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	template, err := c.service.AddTemplate(r.Context(), models.Template{
+		OrganizationId:     organizationId,
+		Name:               req.Name,
+		SourceTenderId:     req.SourceTenderId,
+		Description:        req.Description,
+		ServiceType:        req.ServiceType,
+		Budget:             req.Budget,
+		BudgetConfidential: req.BudgetConfidential,
+		Visibility:         req.Visibility,
+		Lots:               models.TemplateLots(req.Lots),
+		Criteria:           models.TemplateCriteria(req.Criteria),
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, template)
+}
+
+// DELETE /api/organizations/{organizationId}/templates/{templateId}
+func (c *Controller) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	templateId := r.PathValue("templateId")
+	if len(templateId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty templateId supplied")
+		return
+	}
+
+	err := c.service.DeleteTemplate(r.Context(), organizationId, templateId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+//// Questions
+
+// POST /api/tenders/{tenderId}/questions
+func (c *Controller) AskQuestion(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseQuestionReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question, err := c.service.AskQuestion(r.Context(), tenderId, req.Question)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, question)
+}
+
+// GET /api/tenders/{tenderId}/questions
+func (c *Controller) TenderQuestions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	questions, err := c.service.GetTenderQuestions(r.Context(), tenderId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, questions)
+}
+
+// PUT /api/tenders/{tenderId}/questions/{questionId}/answer
+func (c *Controller) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
+	tenderId := r.PathValue("tenderId")
+	if len(tenderId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty tenderId supplied")
+		return
+	}
+
+	questionId := r.PathValue("questionId")
+	if len(questionId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty questionId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseAnswerReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question, err := c.service.AnswerQuestion(r.Context(), tenderId, questionId, req.Answer)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, question)
+}
+
+//// Categories
+
+// GET /api/categories
+func (c *Controller) Categories(w http.ResponseWriter, r *http.Request) {
+	includeInactive := r.URL.Query().Get("includeInactive") == "true"
+
+	categories, err := c.service.GetCategories(r.Context(), includeInactive)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, categories)
+}
+
+// POST /api/categories
+func (c *Controller) NewCategory(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseNewCategoryReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := c.service.AddCategory(r.Context(), models.Category{
+		Code:       req.Code,
+		ParentCode: req.ParentCode,
+		Names:      req.Names,
+		Active:     *req.Active,
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, category)
+}
+
+// PATCH /api/categories/{code}
+func (c *Controller) EditCategory(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if len(code) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty category code supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseCategoryChangeReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := c.service.EditCategory(r.Context(), models.ServiceType(code), req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, category)
+}
+
+//// Auth
+
+// POST /api/auth/token
+func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseLoginReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, expires, err := c.service.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, TokenResponse{Token: token, ExpiresAt: expires})
+}
+
+// PUT /api/auth/password
+func (c *Controller) SetPassword(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseSetPasswordReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = c.service.SetPassword(r.Context(), req.CurrentPassword, req.Password)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// POST /api/auth/password/reset
+func (c *Controller) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseResetPasswordReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = c.service.SetPasswordWithToken(r.Context(), req.Token, req.Password)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+//// API keys
+
+// POST /api/organizations/{organizationId}/api_keys
+func (c *Controller) NewAPIKey(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseNewAPIKeyReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, secret, err := c.service.CreateAPIKey(r.Context(), models.APIKey{
+		OrganizationId: organizationId,
+		Name:           req.Name,
+		Scopes:         req.Scopes,
+		ExpiresAt:      req.ExpiresAt,
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, NewAPIKeyResponse{APIKey: key, Key: secret})
+}
+
+// GET /api/organizations/{organizationId}/api_keys
+func (c *Controller) APIKeys(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	keys, err := c.service.GetAPIKeys(r.Context(), organizationId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, keys)
+}
+
+// DELETE /api/organizations/{organizationId}/api_keys/{keyId}
+func (c *Controller) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	keyId := r.PathValue("keyId")
+	if len(keyId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty keyId supplied")
+		return
+	}
+
+	err := c.service.RevokeAPIKey(r.Context(), organizationId, keyId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+//// Employees
+
+// POST /api/employees
+func (c *Controller) NewEmployee(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseNewEmployeeReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, token, expires, err := c.service.AddEmployee(r.Context(), models.User{
+		Username:  req.Username,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+	}, req.Password)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	resp := NewEmployeeResponse{User: user}
+	if len(token) > 0 {
+		resp.PasswordToken = &TokenResponse{Token: token, ExpiresAt: expires}
+	}
+	c.marshalResponse(w, resp)
+}
+
+// GET /api/employees
+func (c *Controller) Employees(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	users, err := c.service.GetEmployees(r.Context(), limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, users)
+}
+
+// GET /api/employees/{employeeId}
+func (c *Controller) Employee(w http.ResponseWriter, r *http.Request) {
+	employeeId := r.PathValue("employeeId")
+	if len(employeeId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty employeeId supplied")
+		return
+	}
+
+	user, err := c.service.GetEmployee(r.Context(), employeeId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, user)
+}
+
+// PATCH /api/employees/{employeeId}
+func (c *Controller) EditEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeId := r.PathValue("employeeId")
+	if len(employeeId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty employeeId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+	req, err := ParseEmployeeChangeReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := c.service.EditEmployee(r.Context(), employeeId, req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, user)
+}
+
+// DELETE /api/employees/{employeeId}
+func (c *Controller) DeleteEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeId := r.PathValue("employeeId")
+	if len(employeeId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty employeeId supplied")
+		return
+	}
+
+	err := c.service.DeleteEmployee(r.Context(), employeeId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+//// Organizations
+
+// POST /api/organizations
+func (c *Controller) NewOrganization(w http.ResponseWriter, r *http.Request) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseNewOrganizationReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	org, err := c.service.AddOrganization(r.Context(), models.Organization{
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+	})
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, org)
+}
+
+// GET /api/organizations
+func (c *Controller) Organizations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	orgs, err := c.service.GetOrganizations(r.Context(), limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, orgs)
+}
+
+// GET /api/organizations/{organizationId}
+func (c *Controller) Organization(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	org, err := c.service.GetOrganization(r.Context(), organizationId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, org)
+}
+
+// PATCH /api/organizations/{organizationId}
+func (c *Controller) EditOrganization(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+	req, err := ParseOrganizationChangeReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	org, err := c.service.EditOrganization(r.Context(), organizationId, req)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, org)
+}
+
+// DELETE /api/organizations/{organizationId}
+func (c *Controller) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	err := c.service.DeleteOrganization(r.Context(), organizationId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// GET /api/organizations/{organizationId}/members
+func (c *Controller) Members(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	members, err := c.service.GetMembers(r.Context(), organizationId, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, members)
+}
+
+// PUT /api/organizations/{organizationId}/members/{employeeId}
+func (c *Controller) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	employeeId := r.PathValue("employeeId")
+	if len(employeeId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty employeeId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseMemberRoleReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, err := c.service.SetMemberRole(r.Context(), organizationId, employeeId, req.Role)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, member)
+}
+
+// DELETE /api/organizations/{organizationId}/members/{employeeId}
+func (c *Controller) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	employeeId := r.PathValue("employeeId")
+	if len(employeeId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty employeeId supplied")
+		return
+	}
+
+	err := c.service.RemoveMember(r.Context(), organizationId, employeeId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// POST /api/organizations/{organizationId}/members/{employeeId}/password_reset
+func (c *Controller) ResetMemberPassword(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	employeeId := r.PathValue("employeeId")
+	if len(employeeId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty employeeId supplied")
+		return
+	}
+
+	token, expires, err := c.service.ResetMemberPassword(r.Context(), organizationId, employeeId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, TokenResponse{Token: token, ExpiresAt: expires})
+}
+
+//// Invitations
+
+// POST /api/organizations/{organizationId}/invitations
+func (c *Controller) NewInvitation(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseNewInvitationReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	inv, token, err := c.service.InviteMember(r.Context(), organizationId, req.Username, req.Role)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, NewInvitationResponse{Invitation: inv, Token: token})
+}
+
+// GET /api/organizations/{organizationId}/invitations
+func (c *Controller) Invitations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	status := models.InvitationStatus(query.Get("status"))
+	if len(status) > 0 && !models.ValidInvitationStatus(status) {
+		c.errorResponse(w, http.StatusBadRequest, "invalid status supplied: "+string(status))
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	invs, err := c.service.GetInvitations(r.Context(), organizationId, status, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, invs)
+}
+
+// DELETE /api/organizations/{organizationId}/invitations/{invitationId}
+func (c *Controller) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	organizationId := r.PathValue("organizationId")
+	if len(organizationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty organizationId supplied")
+		return
+	}
+
+	invitationId := r.PathValue("invitationId")
+	if len(invitationId) == 0 {
+		c.errorResponse(w, http.StatusBadRequest, "empty invitationId supplied")
+		return
+	}
+
+	err := c.service.RevokeInvitation(r.Context(), organizationId, invitationId)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// GET /api/invitations/my
+func (c *Controller) MyInvitations(w http.ResponseWriter, r *http.Request) {
+	invs, err := c.service.GetUserInvitations(r.Context())
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, invs)
+}
+
+// POST /api/invitations/accept
+func (c *Controller) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	c.respondInvitation(w, r, true)
+}
+
+// POST /api/invitations/decline
+func (c *Controller) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	c.respondInvitation(w, r, false)
+}
+
+func (c *Controller) respondInvitation(w http.ResponseWriter, r *http.Request, accept bool) {
+	data, err := c.readBody(r.Body)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not read request body")
+		return
+	}
+
+	req, err := ParseInvitationResponseReq(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	inv, err := c.service.RespondInvitation(r.Context(), req.Token, accept)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, inv)
+}
+
+//// Audit log
+
+// GET /api/audit
+func (c *Controller) AuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter, err := ParseAuditFilter(query)
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := c.getQueryInt(query, "limit")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'limit' query parameter: "+query.Get("limit"))
+		return
+	}
+
+	offset, err := c.getQueryInt(query, "offset")
+	if err != nil {
+		c.errorResponse(w, http.StatusBadRequest, "invalid value of 'offset' query parameter: "+query.Get("offset"))
+		return
+	}
+
+	entries, err := c.service.GetAuditLog(r.Context(), filter, limit, offset)
+	if err != nil {
+		c.serviceErrorResponse(w, err)
+		return
+	}
+
+	c.marshalResponse(w, entries)
+}
+
+// Service
+
+type ErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+func (c *Controller) getQueryInt(query url.Values, key string) (int, error) {
+	strs, ok := query[key]
+	if ok && len(strs) > 0 {
+		return strconv.Atoi(strs[0])
+	}
+	return 0, nil
+}
+
+func (c *Controller) errorResponse(w http.ResponseWriter, status int, text string) {
+	w.WriteHeader(status)
+
+	data, err := json.Marshal(ErrorResponse{Reason: text})
+	if err != nil {
+		log.Printf("contorller.Controller.errorResponse: %s", err)
+		return
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		log.Printf("contorller.Controller.errorResponse: %s", err)
+		return
+	}
+}
+
+func (c *Controller) serviceErrorResponse(w http.ResponseWriter, err error) {
+	var permErr *models.PermissionError
+
+	switch {
+	case errors.Is(err, models.ErrInvalidUser):
+		c.errorResponse(w, http.StatusUnauthorized, "user does not exist or have no rights for requested action")
+	case errors.Is(err, models.ErrInvalidCredentials):
+		c.errorResponse(w, http.StatusUnauthorized, "invalid or expired credentials supplied")
+	case errors.As(err, &permErr):
+		c.errorResponse(w, http.StatusForbidden, fmt.Sprintf("user have no permission for requested action: missing permission '%s'", permErr.Permission))
+	case errors.Is(err, models.ErrForbidden):
+		c.errorResponse(w, http.StatusForbidden, "user have no permission for requested action")
+	case errors.Is(err, models.ErrNoTender):
+		c.errorResponse(w, http.StatusNotFound, "requested tender does not exist or unacessible")
+	case errors.Is(err, models.ErrTenderFinalized):
+		c.errorResponse(w, http.StatusForbidden, "requested tender is already closed, status cannot be changed")
+	case errors.Is(err, models.ErrNoBid):
+		c.errorResponse(w, http.StatusNotFound, "requested bid does not exist or unacessible")
+	case errors.Is(err, models.ErrNoVersion):
+		c.errorResponse(w, http.StatusNotFound, "requested version does not exist")
+	case errors.Is(err, models.ErrVersionConflict):
+		c.errorResponse(w, http.StatusPreconditionFailed, "entity is changed since requested version, fetch it again and retry")
+	case errors.Is(err, models.ErrBidFinalized):
+		c.errorResponse(w, http.StatusForbidden, "requested bid is already approved or rejected, status cannot be changed")
+	case errors.Is(err, models.ErrBidCannotBeApprovedYet):
+		c.errorResponse(w, http.StatusForbidden, "requested bid does not have enough votes to be approved")
+	case errors.Is(err, models.ErrNoAPIKey):
+		c.errorResponse(w, http.StatusNotFound, "requested api key does not exist or already revoked")
+	case errors.Is(err, models.ErrNoEmployee):
+		c.errorResponse(w, http.StatusNotFound, "requested employee does not exist")
+	case errors.Is(err, models.ErrNoOrganization):
+		c.errorResponse(w, http.StatusNotFound, "requested organization does not exist")
+	case errors.Is(err, models.ErrNoMember):
+		c.errorResponse(w, http.StatusNotFound, "requested employee is not a member of organization")
+	case errors.Is(err, models.ErrUsernameTaken):
+		c.errorResponse(w, http.StatusConflict, "username is already taken")
+	case errors.Is(err, models.ErrAlreadyMember):
+		c.errorResponse(w, http.StatusConflict, "employee is already a member of organization")
+	case errors.Is(err, models.ErrLastAdmin):
+		c.errorResponse(w, http.StatusConflict, "organization must have at least one administrator")
+	case errors.Is(err, models.ErrNoInvitation):
+		c.errorResponse(w, http.StatusNotFound, "requested invitation does not exist")
+	case errors.Is(err, models.ErrInvitationExists):
+		c.errorResponse(w, http.StatusConflict, "employee already has pending invitation to organization")
+	case errors.Is(err, models.ErrInvitationClosed):
+		c.errorResponse(w, http.StatusGone, "invitation is already accepted, declined, revoked or expired")
+	case errors.Is(err, models.ErrDeadlinePassed):
+		c.errorResponse(w, http.StatusForbidden, "tender submission deadline has passed, bids are not accepted")
+	case errors.Is(err, models.ErrInvalidSchedule):
+		c.errorResponse(w, http.StatusConflict, "only tenders in Created status can be scheduled for publication, which should precede submission deadline")
+	case errors.Is(err, models.ErrCurrencyMismatch):
+		c.errorResponse(w, http.StatusConflict, "bid price should be in currency of tender budget")
+	case errors.Is(err, models.ErrNoCriterion):
+		c.errorResponse(w, http.StatusNotFound, "requested evaluation criterion does not exist")
+	case errors.Is(err, models.ErrCriteriaLocked):
+		c.errorResponse(w, http.StatusConflict, "evaluation criteria can not be changed after bids are scored")
+	case errors.Is(err, models.ErrNoLot):
+		c.errorResponse(w, http.StatusNotFound, "requested lot does not exist or bid is not submitted for it")
+	case errors.Is(err, models.ErrLotRequired):
+		c.errorResponse(w, http.StatusBadRequest, "tender is split into lots, bids should be submitted and decided on for specific lots")
+	case errors.Is(err, models.ErrLotsLocked):
+		c.errorResponse(w, http.StatusConflict, "lots can not be changed after bids are submitted")
+	case errors.Is(err, models.ErrLotFinalized):
+		c.errorResponse(w, http.StatusConflict, "requested lot is already awarded or cancelled")
+	case errors.Is(err, models.ErrNoQuestion):
+		c.errorResponse(w, http.StatusNotFound, "requested question does not exist")
+	case errors.Is(err, models.ErrQuestionsClosed):
+		c.errorResponse(w, http.StatusConflict, "questions are only accepted and answered while tender is published")
+	case errors.Is(err, models.ErrAmendmentReason):
+		c.errorResponse(w, http.StatusBadRequest, "published tender with submitted bids can only be amended with reason")
+	case errors.Is(err, models.ErrReconfirmationRequired):
+		c.errorResponse(w, http.StatusConflict, "bid is submitted against outdated version of tender and has to be reconfirmed or revised before approval")
+	case errors.Is(err, models.ErrCancellationReason):
+		c.errorResponse(w, http.StatusBadRequest, "tender can only be cancelled with reason")
+	case errors.Is(err, models.ErrTenderNameTaken):
+		c.errorResponse(w, http.StatusConflict, "tender with this name already exists")
+	case errors.Is(err, models.ErrNoTemplate):
+		c.errorResponse(w, http.StatusNotFound, "requested template does not exist")
+	case errors.Is(err, models.ErrTemplateExists):
+		c.errorResponse(w, http.StatusConflict, "organization already has template with this name")
+	case errors.Is(err, models.ErrNoAttachment):
+		c.errorResponse(w, http.StatusNotFound, "requested attachment does not exist")
+	case errors.Is(err, models.ErrAttachmentTooLarge):
+		c.errorResponse(w, http.StatusRequestEntityTooLarge, "attachment exceeds maximum size")
+	case errors.Is(err, models.ErrAttachmentType):
+		c.errorResponse(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, models.ErrBidsSealed):
+		c.errorResponse(w, http.StatusConflict, "bids of sealed tender can not be decided on, scored or have their attachments viewed until they are opened")
+	case errors.Is(err, models.ErrSealingUnavailable):
+		c.errorResponse(w, http.StatusConflict, "sealed tenders are not available, sealing key is not configured")
+	case errors.Is(err, models.ErrOpeningNotDue):
+		c.errorResponse(w, http.StatusConflict, "bids can only be opened once after submission deadline of sealed tender")
+	case errors.Is(err, models.ErrNoAuction):
+		c.errorResponse(w, http.StatusNotFound, "tender has no auction")
+	case errors.Is(err, models.ErrAuctionUnavailable):
+		c.errorResponse(w, http.StatusConflict, "auction is only held for published or closed tender without lots, bids of which are not sealed")
+	case errors.Is(err, models.ErrAuctionSchedule):
+		c.errorResponse(w, http.StatusBadRequest, "auction should start after submission deadline of tender, which has to be set")
+	case errors.Is(err, models.ErrAuctionStarted):
+		c.errorResponse(w, http.StatusConflict, "auction has already started, its terms and prices of its bids can not be changed")
+	case errors.Is(err, models.ErrAuctionNotRunning):
+		c.errorResponse(w, http.StatusConflict, "auction is not running, prices are not accepted")
+	case errors.Is(err, models.ErrNotQualified):
+		c.errorResponse(w, http.StatusConflict, "only published bids priced in currency of auction and not awaiting reconfirmation take part in auction")
+	case errors.Is(err, models.ErrPriceDecrement):
+		c.errorResponse(w, http.StatusConflict, "new price should be lower than current one at least by minimum decrement of auction")
+	case errors.Is(err, models.ErrWithdrawalReason):
+		c.errorResponse(w, http.StatusBadRequest, "bid can only be withdrawn with reason via PUT /api/bids/{bidId}/withdraw")
+	case errors.Is(err, models.ErrBidWithdrawn):
+		c.errorResponse(w, http.StatusConflict, "bid is withdrawn, it can only be resubmitted via PUT /api/bids/{bidId}/resubmit")
+	case errors.Is(err, models.ErrBidNotWithdrawn):
+		c.errorResponse(w, http.StatusConflict, "only withdrawn bid can be resubmitted")
+	case errors.Is(err, models.ErrResubmissionClosed):
+		c.errorResponse(w, http.StatusConflict, "bid can only be resubmitted while its tender is published and accepts bids")
+	case errors.Is(err, models.ErrLegacyAuth):
+		c.errorResponse(w, http.StatusForbidden, "operation requires authentication with token, username is not accepted")
+	case errors.Is(err, models.ErrNoCategory):
+		c.errorResponse(w, http.StatusNotFound, "requested category does not exist")
+	case errors.Is(err, models.ErrCategoryExists):
+		c.errorResponse(w, http.StatusConflict, "category with this code already exists")
+	case errors.Is(err, models.ErrCategoryCycle):
+		c.errorResponse(w, http.StatusConflict, "category can not be moved under itself or its subcategory")
+	default:
+		log.Println("controller:", err)
+		c.errorResponse(w, http.StatusInternalServerError, "internal server error: "+err.Error())
+	}
+}
+
+func (c *Controller) marshalResponse(w http.ResponseWriter, data any) {
+	d, err := json.Marshal(data)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not marhsal response data")
+		return
+	}
+
+	_, err = w.Write(d)
+	if err != nil {
+		c.errorResponse(w, http.StatusInternalServerError, "could not write response data")
+		return
+	}
+}
+
+// versionedResponse writes tender or bid along with its version as ETag, which clients send back
+// in If-Match header to make sure they change the version they have seen
+func (c *Controller) versionedResponse(w http.ResponseWriter, version int, data any) {
+	w.Header().Set("ETag", entityTag(version))
+	c.marshalResponse(w, data)
+}
+
+func (c *Controller) readBody(src io.ReadCloser) ([]byte, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	src.Close()
+	return data, nil
+}
+
+// readAttachment finds uploaded file in multipart/form-data request, it is read from request body as it is consumed
+func (c *Controller) readAttachment(r *http.Request) (models.Attachment, io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("request should be multipart/form-data upload: %w", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return models.Attachment{}, nil, fmt.Errorf("no 'file' part supplied")
+		} else if err != nil {
+			return models.Attachment{}, nil, fmt.Errorf("malformed multipart body: %w", err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		name := part.FileName()
+		if len(name) == 0 {
+			return models.Attachment{}, nil, fmt.Errorf("empty file name supplied")
+		}
+		if err = checkLengthLimit(name, "FileName", 255); err != nil {
+			return models.Attachment{}, nil, err
+		}
+		return models.Attachment{FileName: name, ContentType: part.Header.Get("Content-Type")}, part, nil
+	}
+}
+
+func (c *Controller) attachmentResponse(w http.ResponseWriter, attachment models.Attachment, content io.Reader) {
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Checksum-SHA256", attachment.Checksum)
+	w.WriteHeader(http.StatusOK)
+
+	_, err := io.Copy(w, content)
+	if err != nil {
+		log.Printf("controller.Controller.attachmentResponse: %s", err)
+	}
+}
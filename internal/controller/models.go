@@ -1,156 +1,1536 @@
-package controller
-
-import (
-	"encoding/json"
-	"fmt"
-	"tenders/internal/models"
-)
-
-// New tender request
-
-type NewTenderReq struct {
-	Name           string              `json:"name"`
-	Description    string              `json:"description"`
-	ServiceType    models.ServiceType  `json:"serviceType"`
-	Status         models.TenderStatus `json:"status"`
-	OrganizationId string              `json:"organizationId"`
-	AuthorUsername string              `json:"creatorUsername"`
-}
-
-func ParseNewTenderReq(data []byte) (*NewTenderReq, error) {
-	t := &NewTenderReq{}
-
-	err := json.Unmarshal(data, t)
-	if err != nil {
-		return nil, err
-	}
-
-	if !models.ValidServiceType(t.ServiceType) {
-		return nil, fmt.Errorf("invalid service type supplied: %s, should be one of: %s, %s, %s", string(t.ServiceType), models.STConstruction, models.STDelivery, models.STManufacture)
-	}
-
-	if len(t.Status) == 0 {
-		t.Status = models.TenderCreated
-	} else if !models.ValidTenderStatus(t.Status) {
-		return nil, fmt.Errorf("invalid tender status supplied: %s, should be one of: %s, %s, %s", string(t.Status), models.TenderCreated, models.TenderPublished, models.TenderClosed)
-	}
-
-	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
-		return nil, err
-	}
-	if err = checkLengthLimit(t.OrganizationId, "OrganizationId", 100); err != nil {
-		return nil, err
-	}
-	if err = checkLengthLimit(t.Description, "Description", 500); err != nil {
-		return nil, err
-	}
-
-	return t, nil
-}
-
-// Edit tender request
-
-type TenderChangeReq map[string]string
-
-func ParseTenderChangeReq(data []byte) (TenderChangeReq, error) {
-	t := TenderChangeReq{}
-	vals := make(map[string]interface{})
-
-	err := json.Unmarshal(data, &vals)
-	if err != nil {
-		return nil, err
-	}
-
-	str, ok, err := checkRequestField(vals, "serviceType", 100)
-	if err != nil {
-		return nil, err
-	}
-	if !models.ValidServiceType(models.ServiceType(str)) {
-		return nil, fmt.Errorf("invalid service type supplied: %s", str)
-	}
-	if ok {
-		t["serviceType"] = str
-	}
-
-	str, ok, err = checkRequestField(vals, "name", 100)
-	if err != nil {
-		return nil, err
-	}
-	if ok {
-		t["name"] = str
-	}
-
-	str, ok, err = checkRequestField(vals, "description", 100)
-	if err != nil {
-		return nil, err
-	}
-	if ok {
-		t["description"] = str
-	}
-
-	return t, nil
-}
-
-// New bid request
-
-type NewBidReq struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	TenderId    string            `json:"tenderId"`
-	AuthorType  models.AuthorType `json:"authorType"`
-	AuthorId    string            `json:"authorId"`
-}
-
-func ParseNewBidReq(data []byte) (*NewBidReq, error) {
-	t := &NewBidReq{}
-
-	err := json.Unmarshal(data, t)
-	if err != nil {
-		return nil, err
-	}
-
-	if !models.ValidAuthorType(t.AuthorType) {
-		return nil, fmt.Errorf("invalid service type supplied: %s", t.AuthorType)
-	}
-	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
-		return nil, err
-	}
-	if err = checkLengthLimit(t.TenderId, "TenderId", 100); err != nil {
-		return nil, err
-	}
-	if err = checkLengthLimit(t.AuthorId, "AuthorId", 100); err != nil {
-		return nil, err
-	}
-	if err = checkLengthLimit(t.Description, "Description", 500); err != nil {
-		return nil, err
-	}
-
-	return t, nil
-}
-
-// Service
-
-func checkLengthLimit(str, fieldName string, limit int) error {
-	if len(str) > limit {
-		return fmt.Errorf("field '%s' exceeds length limit: %d / %d", fieldName, len(str), 100)
-	}
-	return nil
-}
-
-func checkRequestField(vals map[string]interface{}, key string, lentghLimit int) (string, bool, error) {
-	val, ok := vals[key]
-	if !ok {
-		return "", false, nil
-	}
-
-	str, ok := val.(string)
-	if !ok {
-		return "", false, fmt.Errorf("invalid type od '%s' field", key)
-	}
-
-	if err := checkLengthLimit(str, key, lentghLimit); err != nil {
-		return "", false, err
-	}
-
-	return str, true, nil
-}
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"tenders/internal/models"
+	"time"
+)
+
+// New tender request
+
+type NewTenderReq struct {
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	ServiceType    models.ServiceType  `json:"serviceType"`
+	Status         models.TenderStatus `json:"status"`
+	OrganizationId string              `json:"organizationId"`
+	// Optional, estimated budget and whether it is hidden from other organizations
+	Budget             *models.Money `json:"budget"`
+	BudgetConfidential bool          `json:"budgetConfidential"`
+	// Optional, tenders are public by default
+	Visibility models.TenderVisibility `json:"visibility"`
+	// Optional, tender in Created status is published at this moment
+	PublishAt *time.Time `json:"publishAt"`
+	// Optional, bids are accepted until this moment
+	SubmissionDeadline *time.Time `json:"submissionDeadline"`
+	// Optional, bids of sealed tender are encrypted until opened after submission deadline, which is required then
+	Sealed bool `json:"sealed"`
+	// Only read by legacy username authentication, author is taken from request context
+	AuthorUsername string `json:"creatorUsername"`
+}
+
+// ParseNewTenderReq reads new tender, its service type should be code of one of active categories
+func ParseNewTenderReq(data []byte, categories []models.Category) (*NewTenderReq, error) {
+	t := &NewTenderReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkServiceType(t.ServiceType, categories, true); err != nil {
+		return nil, err
+	}
+
+	if len(t.Status) == 0 {
+		t.Status = models.TenderCreated
+	} else if !models.ValidTenderStatus(t.Status) || t.Status.Outcome() {
+		return nil, fmt.Errorf("invalid tender status supplied: %s, should be one of: %s, %s, %s", string(t.Status), models.TenderCreated, models.TenderPublished, models.TenderClosed)
+	}
+
+	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.OrganizationId, "OrganizationId", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.Description, "Description", 500); err != nil {
+		return nil, err
+	}
+
+	if t.Budget != nil {
+		if err = t.Budget.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.Visibility) == 0 {
+		t.Visibility = models.VisibilityPublic
+	} else if !models.ValidTenderVisibility(t.Visibility) {
+		return nil, fmt.Errorf("invalid tender visibility supplied: %s, should be one of: %s, %s", t.Visibility, models.VisibilityPublic, models.VisibilityInviteOnly)
+	}
+
+	if t.SubmissionDeadline != nil && !t.SubmissionDeadline.After(time.Now()) {
+		return nil, fmt.Errorf("submission deadline should be in the future: %s", t.SubmissionDeadline.Format(time.RFC3339))
+	}
+	if t.Sealed && t.SubmissionDeadline == nil {
+		return nil, fmt.Errorf("sealed tender requires submission deadline, its bids are opened after it")
+	}
+
+	if t.PublishAt != nil {
+		if t.Status != models.TenderCreated {
+			return nil, fmt.Errorf("only tenders in %s status can be scheduled for publication", models.TenderCreated)
+		}
+		if !t.PublishAt.After(time.Now()) {
+			return nil, fmt.Errorf("publication time should be in the future: %s", t.PublishAt.Format(time.RFC3339))
+		}
+		if t.SubmissionDeadline != nil && !t.PublishAt.Before(*t.SubmissionDeadline) {
+			return nil, fmt.Errorf("publication time should precede submission deadline")
+		}
+	}
+
+	return t, nil
+}
+
+// Edit tender request
+
+type TenderChangeReq map[string]any
+
+// ParseTenderChangeReq reads tender changes, its service type can only be changed to one of active categories
+func ParseTenderChangeReq(data []byte, categories []models.Category) (TenderChangeReq, error) {
+	t := TenderChangeReq{}
+	vals := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &vals)
+	if err != nil {
+		return nil, err
+	}
+
+	// budget is read once more, so its amount is not rounded by float conversion
+	money := struct {
+		Budget *models.Money `json:"budget"`
+	}{}
+	err = json.Unmarshal(data, &money)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := vals["budget"]; ok {
+		// null removes budget
+		if money.Budget != nil {
+			if err = money.Budget.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		t["budget"] = money.Budget
+	}
+
+	if val, ok := vals["budgetConfidential"]; ok {
+		confidential, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid type od 'budgetConfidential' field")
+		}
+		t["budgetConfidential"] = confidential
+	}
+
+	str, ok, err := checkRequestField(vals, "serviceType", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err = checkServiceType(models.ServiceType(str), categories, true); err != nil {
+			return nil, err
+		}
+		t["serviceType"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "name", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["name"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "description", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["description"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "visibility", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if !models.ValidTenderVisibility(models.TenderVisibility(str)) {
+			return nil, fmt.Errorf("invalid tender visibility supplied: %s, should be one of: %s, %s", str, models.VisibilityPublic, models.VisibilityInviteOnly)
+		}
+		t["visibility"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "submissionDeadline", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		deadline, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid submission deadline supplied: %s, should be RFC 3339 time", str)
+		}
+		if !deadline.After(time.Now()) {
+			return nil, fmt.Errorf("submission deadline should be in the future: %s", str)
+		}
+		t["submissionDeadline"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "publishAt", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		publishAt, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid publication time supplied: %s, should be RFC 3339 time", str)
+		}
+		if !publishAt.After(time.Now()) {
+			return nil, fmt.Errorf("publication time should be in the future: %s", str)
+		}
+		t["publishAt"] = str
+	}
+
+	return t, nil
+}
+
+// ParseAmendmentReason reads reason of tender changes, which is required to amend published tender with submitted bids
+func ParseAmendmentReason(data []byte) (string, error) {
+	t := struct {
+		Reason string `json:"amendmentReason"`
+	}{}
+
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return "", err
+	}
+
+	if err = checkLengthLimit(t.Reason, "amendmentReason", 500); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(t.Reason), nil
+}
+
+// New bid request
+
+type NewBidReq struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	TenderId    string            `json:"tenderId"`
+	AuthorType  models.AuthorType `json:"authorType"`
+	AuthorId    string            `json:"authorId"`
+	Price       *models.Money     `json:"price"`
+	// Required for tenders split into lots
+	LotIds []string `json:"lotIds"`
+}
+
+func ParseNewBidReq(data []byte) (*NewBidReq, error) {
+	t := &NewBidReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if !models.ValidAuthorType(t.AuthorType) {
+		return nil, fmt.Errorf("invalid service type supplied: %s", t.AuthorType)
+	}
+	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.TenderId, "TenderId", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.AuthorId, "AuthorId", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.Description, "Description", 500); err != nil {
+		return nil, err
+	}
+
+	if t.Price == nil {
+		return nil, fmt.Errorf("empty price supplied")
+	}
+	if err = t.Price.Validate(); err != nil {
+		return nil, err
+	}
+
+	t.LotIds, err = parseLotIds(t.LotIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Edit bid request
+
+type BidChangeReq map[string]any
+
+func ParseBidChangeReq(data []byte) (BidChangeReq, error) {
+	t := BidChangeReq{}
+	vals := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &vals)
+	if err != nil {
+		return nil, err
+	}
+
+	str, ok, err := checkRequestField(vals, "name", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["name"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "description", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["description"] = str
+	}
+
+	// price is read once more, so its amount is not rounded by float conversion
+	money := struct {
+		Price *models.Money `json:"price"`
+	}{}
+	err = json.Unmarshal(data, &money)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := vals["price"]; ok {
+		if money.Price == nil {
+			return nil, fmt.Errorf("empty price supplied")
+		}
+		if err = money.Price.Validate(); err != nil {
+			return nil, err
+		}
+		t["price"] = money.Price
+	}
+
+	if val, ok := vals["lotIds"]; ok {
+		list, ok := val.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid type od 'lotIds' field")
+		}
+		lotIds := make([]string, 0, len(list))
+		for _, item := range list {
+			lotId, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid type od 'lotIds' field")
+			}
+			lotIds = append(lotIds, lotId)
+		}
+		t["lotIds"], err = parseLotIds(lotIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// parseLotIds validates ids of lots bid is submitted for and converts them to lower case
+func parseLotIds(lotIds []string) ([]string, error) {
+	if len(lotIds) > 100 {
+		return nil, fmt.Errorf("too many lots supplied: %d / %d", len(lotIds), 100)
+	}
+
+	result := make([]string, 0, len(lotIds))
+	for _, lotId := range lotIds {
+		if !validUUID(lotId) {
+			return nil, fmt.Errorf("invalid lot id supplied: %s", lotId)
+		}
+		lotId = strings.ToLower(lotId)
+		if slices.Contains(result, lotId) {
+			return nil, fmt.Errorf("duplicate lot id supplied: %s", lotId)
+		}
+		result = append(result, lotId)
+	}
+	return result, nil
+}
+
+// Evaluation criteria request
+
+type CriterionReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Weight      int    `json:"weight"`
+}
+
+// ParseCriteriaReq reads full list of tender's evaluation criteria, empty list removes all of them
+func ParseCriteriaReq(data []byte) ([]models.Criterion, error) {
+	var reqs []CriterionReq
+
+	err := json.Unmarshal(data, &reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reqs) > 20 {
+		return nil, fmt.Errorf("too many criteria supplied: %d / %d", len(reqs), 20)
+	}
+
+	criteria := make([]models.Criterion, 0, len(reqs))
+	names := map[string]bool{}
+	for _, req := range reqs {
+		if len(req.Name) == 0 {
+			return nil, fmt.Errorf("empty criterion name supplied")
+		}
+		if err = checkLengthLimit(req.Name, "Name", 100); err != nil {
+			return nil, err
+		}
+		if err = checkLengthLimit(req.Description, "Description", 500); err != nil {
+			return nil, err
+		}
+		if names[req.Name] {
+			return nil, fmt.Errorf("duplicate criterion name supplied: %s", req.Name)
+		}
+		names[req.Name] = true
+		if req.Weight < 1 || req.Weight > 100 {
+			return nil, fmt.Errorf("invalid weight of criterion '%s' supplied: %d, should be from 1 to 100", req.Name, req.Weight)
+		}
+
+		criteria = append(criteria, models.Criterion{Name: req.Name, Description: req.Description, Weight: req.Weight})
+	}
+
+	return criteria, nil
+}
+
+// Lots request
+
+type LotReq struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Quantity    int           `json:"quantity"`
+	Budget      *models.Money `json:"budget"`
+}
+
+// ParseLotsReq reads full list of tender's lots, empty list turns tender back into single indivisible unit
+func ParseLotsReq(data []byte) ([]models.Lot, error) {
+	var reqs []LotReq
+
+	err := json.Unmarshal(data, &reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reqs) > 100 {
+		return nil, fmt.Errorf("too many lots supplied: %d / %d", len(reqs), 100)
+	}
+
+	lots := make([]models.Lot, 0, len(reqs))
+	names := map[string]bool{}
+	for _, req := range reqs {
+		if len(req.Name) == 0 {
+			return nil, fmt.Errorf("empty lot name supplied")
+		}
+		if err = checkLengthLimit(req.Name, "Name", 100); err != nil {
+			return nil, err
+		}
+		if err = checkLengthLimit(req.Description, "Description", 500); err != nil {
+			return nil, err
+		}
+		if names[req.Name] {
+			return nil, fmt.Errorf("duplicate lot name supplied: %s", req.Name)
+		}
+		names[req.Name] = true
+		if req.Quantity < 1 {
+			return nil, fmt.Errorf("invalid quantity of lot '%s' supplied: %d, should be positive", req.Name, req.Quantity)
+		}
+		if req.Budget != nil {
+			if err = req.Budget.Validate(); err != nil {
+				return nil, err
+			}
+		}
+
+		lots = append(lots, models.Lot{Name: req.Name, Description: req.Description, Quantity: req.Quantity, Budget: req.Budget})
+	}
+
+	return lots, nil
+}
+
+// Invited organizations request
+
+// ParseInvitedOrganizationsReq reads full list of organizations invited to tender, empty list revokes all invitations
+func ParseInvitedOrganizationsReq(data []byte) ([]string, error) {
+	var organizationIds []string
+
+	err := json.Unmarshal(data, &organizationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(organizationIds) > 1000 {
+		return nil, fmt.Errorf("too many organizations supplied: %d / %d", len(organizationIds), 1000)
+	}
+
+	result := make([]string, 0, len(organizationIds))
+	for _, organizationId := range organizationIds {
+		if !validUUID(organizationId) {
+			return nil, fmt.Errorf("invalid organization id supplied: %s", organizationId)
+		}
+		organizationId = strings.ToLower(organizationId)
+		if !slices.Contains(result, organizationId) {
+			result = append(result, organizationId)
+		}
+	}
+	return result, nil
+}
+
+// New template request
+
+type NewTemplateReq struct {
+	Name string `json:"name"`
+	// Optional, template takes content, lots and criteria of this tender and ignores fields below
+	SourceTenderId     string                  `json:"sourceTenderId"`
+	Description        string                  `json:"description"`
+	ServiceType        models.ServiceType      `json:"serviceType"`
+	Budget             *models.Money           `json:"budget"`
+	BudgetConfidential bool                    `json:"budgetConfidential"`
+	Visibility         models.TenderVisibility `json:"visibility"`
+	LotsData           json.RawMessage         `json:"lots"`
+	CriteriaData       json.RawMessage         `json:"criteria"`
+
+	Lots     []models.Lot       `json:"-"`
+	Criteria []models.Criterion `json:"-"`
+}
+
+// ParseNewTemplateReq reads new template, its lots and criteria are validated the same way as tender's
+func ParseNewTemplateReq(data []byte, categories []models.Category) (*NewTemplateReq, error) {
+	t := &NewTemplateReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Name) == 0 {
+		return nil, fmt.Errorf("empty template name supplied")
+	}
+	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
+		return nil, err
+	}
+
+	if len(t.SourceTenderId) > 0 {
+		if !validUUID(t.SourceTenderId) {
+			return nil, fmt.Errorf("invalid source tender id supplied: %s", t.SourceTenderId)
+		}
+		return t, nil
+	}
+
+	if err = checkServiceType(t.ServiceType, categories, true); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.Description, "Description", 500); err != nil {
+		return nil, err
+	}
+
+	if t.Budget != nil {
+		if err = t.Budget.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.Visibility) == 0 {
+		t.Visibility = models.VisibilityPublic
+	} else if !models.ValidTenderVisibility(t.Visibility) {
+		return nil, fmt.Errorf("invalid tender visibility supplied: %s, should be one of: %s, %s", t.Visibility, models.VisibilityPublic, models.VisibilityInviteOnly)
+	}
+
+	if len(t.LotsData) > 0 {
+		if t.Lots, err = ParseLotsReq(t.LotsData); err != nil {
+			return nil, err
+		}
+	}
+	if len(t.CriteriaData) > 0 {
+		if t.Criteria, err = ParseCriteriaReq(t.CriteriaData); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Clone tender request
+
+type CloneReq struct {
+	// Name of new tender, tender names are unique
+	Name string `json:"name"`
+}
+
+func ParseCloneReq(data []byte) (*CloneReq, error) {
+	req := &CloneReq{}
+
+	err := json.Unmarshal(data, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Name) == 0 {
+		return nil, fmt.Errorf("empty tender name supplied")
+	}
+	if err = checkLengthLimit(req.Name, "Name", 100); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Bid scores request
+
+type ScoreReq struct {
+	CriterionId string `json:"criterionId"`
+	Score       *int   `json:"score"`
+	Comment     string `json:"comment"`
+}
+
+// ParseScoresReq reads scores evaluator gives bid, criteria not mentioned keep their previous scores
+func ParseScoresReq(data []byte) ([]models.BidScore, error) {
+	var reqs []ScoreReq
+
+	err := json.Unmarshal(data, &reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("empty scores supplied")
+	}
+
+	scores := make([]models.BidScore, 0, len(reqs))
+	criteria := map[string]bool{}
+	for _, req := range reqs {
+		if !validUUID(req.CriterionId) {
+			return nil, fmt.Errorf("invalid criterion id supplied: %s", req.CriterionId)
+		}
+		id := strings.ToLower(req.CriterionId)
+		if criteria[id] {
+			return nil, fmt.Errorf("duplicate criterion id supplied: %s", req.CriterionId)
+		}
+		criteria[id] = true
+		if req.Score == nil {
+			return nil, fmt.Errorf("empty score of criterion %s supplied", req.CriterionId)
+		}
+		if *req.Score < 0 || *req.Score > models.MaxScore {
+			return nil, fmt.Errorf("invalid score of criterion %s supplied: %d, should be from 0 to %d", req.CriterionId, *req.Score, models.MaxScore)
+		}
+		if err = checkLengthLimit(req.Comment, "Comment", 500); err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, models.BidScore{CriterionId: id, Score: *req.Score, Comment: req.Comment})
+	}
+
+	return scores, nil
+}
+
+// Auction request
+
+type AuctionReq struct {
+	StartAt          *time.Time    `json:"startAt"`
+	DurationMinutes  int           `json:"durationMinutes"`
+	ExtensionMinutes int           `json:"extensionMinutes"`
+	MinDecrement     *models.Money `json:"minDecrement"`
+}
+
+func ParseAuctionReq(data []byte) (*AuctionReq, error) {
+	t := &AuctionReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.StartAt == nil {
+		return nil, fmt.Errorf("empty start time supplied")
+	}
+	if !t.StartAt.After(time.Now()) {
+		return nil, fmt.Errorf("start time should be in the future: %s", t.StartAt.Format(time.RFC3339))
+	}
+	if t.DurationMinutes < 1 || t.DurationMinutes > 1440 {
+		return nil, fmt.Errorf("invalid duration supplied: %d, should be from 1 to 1440 minutes", t.DurationMinutes)
+	}
+	if t.ExtensionMinutes < 0 || t.ExtensionMinutes > 60 {
+		return nil, fmt.Errorf("invalid extension supplied: %d, should be from 0 to 60 minutes", t.ExtensionMinutes)
+	}
+
+	if t.MinDecrement == nil {
+		return nil, fmt.Errorf("empty minimum decrement supplied")
+	}
+	if err = t.MinDecrement.Validate(); err != nil {
+		return nil, err
+	}
+	if t.MinDecrement.Amount.Cmp("0") <= 0 {
+		return nil, fmt.Errorf("minimum decrement should be positive")
+	}
+
+	return t, nil
+}
+
+// Auction price request
+
+type AuctionPriceReq struct {
+	Price *models.Money `json:"price"`
+}
+
+func ParseAuctionPriceReq(data []byte) (*AuctionPriceReq, error) {
+	t := &AuctionPriceReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Price == nil {
+		return nil, fmt.Errorf("empty price supplied")
+	}
+	if err = t.Price.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Question request
+
+type QuestionReq struct {
+	Question string `json:"question"`
+}
+
+func ParseQuestionReq(data []byte) (*QuestionReq, error) {
+	t := &QuestionReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(strings.TrimSpace(t.Question)) == 0 {
+		return nil, fmt.Errorf("empty question supplied")
+	}
+	if err = checkLengthLimit(t.Question, "Question", 1000); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Answer request
+
+type AnswerReq struct {
+	Answer string `json:"answer"`
+}
+
+func ParseAnswerReq(data []byte) (*AnswerReq, error) {
+	t := &AnswerReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(strings.TrimSpace(t.Answer)) == 0 {
+		return nil, fmt.Errorf("empty answer supplied")
+	}
+	if err = checkLengthLimit(t.Answer, "Answer", 2000); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// New category request
+
+var categoryCodeRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,49}$`)
+var languageRe = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+type NewCategoryReq struct {
+	Code       models.ServiceType `json:"code"`
+	ParentCode models.ServiceType `json:"parentCode"`
+	Names      map[string]string  `json:"names"`
+	// Optional, new categories are active by default
+	Active *bool `json:"active"`
+}
+
+func ParseNewCategoryReq(data []byte) (*NewCategoryReq, error) {
+	t := &NewCategoryReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if !categoryCodeRe.MatchString(string(t.Code)) {
+		return nil, fmt.Errorf("invalid category code supplied: %s, should start with letter and consist of up to 50 letters, digits, '_', '.' or '-'", t.Code)
+	}
+	if len(t.ParentCode) > 0 && !categoryCodeRe.MatchString(string(t.ParentCode)) {
+		return nil, fmt.Errorf("invalid parent category code supplied: %s", t.ParentCode)
+	}
+	if err = checkCategoryNames(t.Names); err != nil {
+		return nil, err
+	}
+	if t.Active == nil {
+		active := true
+		t.Active = &active
+	}
+
+	return t, nil
+}
+
+// Edit category request
+
+type CategoryChangeReq map[string]any
+
+func ParseCategoryChangeReq(data []byte) (CategoryChangeReq, error) {
+	t := CategoryChangeReq{}
+	vals := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &vals)
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := vals["parentCode"]; ok {
+		// null moves category to the top level
+		code, _ := val.(string)
+		if val != nil && !categoryCodeRe.MatchString(code) {
+			return nil, fmt.Errorf("invalid parent category code supplied: %v", val)
+		}
+		t["parentCode"] = code
+	}
+
+	if _, ok := vals["names"]; ok {
+		names := struct {
+			Names map[string]string `json:"names"`
+		}{}
+		err = json.Unmarshal(data, &names)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type of 'names' field")
+		}
+		if err = checkCategoryNames(names.Names); err != nil {
+			return nil, err
+		}
+		t["names"] = names.Names
+	}
+
+	if val, ok := vals["active"]; ok {
+		active, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid type of 'active' field")
+		}
+		t["active"] = active
+	}
+
+	return t, nil
+}
+
+// Login request
+
+type LoginReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type TokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func ParseLoginReq(data []byte) (*LoginReq, error) {
+	t := &LoginReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Username) == 0 {
+		return nil, fmt.Errorf("empty username supplied")
+	}
+	if err = checkLengthLimit(t.Username, "Username", 50); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.Password, "Password", 100); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Reset password request
+
+type ResetPasswordReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func ParseResetPasswordReq(data []byte) (*ResetPasswordReq, error) {
+	t := &ResetPasswordReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Token) == 0 {
+		return nil, fmt.Errorf("empty token supplied")
+	}
+	if err = checkLengthLimit(t.Token, "Token", 100); err != nil {
+		return nil, err
+	}
+	if len(t.Password) < 8 {
+		return nil, fmt.Errorf("password should be at least 8 characters long")
+	}
+	if err = checkLengthLimit(t.Password, "Password", 100); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Set password request
+
+type SetPasswordReq struct {
+	CurrentPassword string `json:"currentPassword"`
+	Password        string `json:"password"`
+}
+
+func ParseSetPasswordReq(data []byte) (*SetPasswordReq, error) {
+	t := &SetPasswordReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Password) < 8 {
+		return nil, fmt.Errorf("password should be at least 8 characters long")
+	}
+	if err = checkLengthLimit(t.Password, "Password", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.CurrentPassword, "CurrentPassword", 100); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// New API key request
+
+type NewAPIKeyReq struct {
+	Name      string            `json:"name"`
+	Scopes    []models.APIScope `json:"scopes"`
+	ExpiresAt *time.Time        `json:"expiresAt"`
+}
+
+type NewAPIKeyResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+func ParseNewAPIKeyReq(data []byte) (*NewAPIKeyReq, error) {
+	t := &NewAPIKeyReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Name) == 0 {
+		return nil, fmt.Errorf("empty name supplied")
+	}
+	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
+		return nil, err
+	}
+
+	if len(t.Scopes) == 0 {
+		return nil, fmt.Errorf("empty scopes supplied, should be any of: %s, %s", models.ScopeTendersWrite, models.ScopeBidsRead)
+	}
+	for _, scope := range t.Scopes {
+		if !models.ValidAPIScope(scope) {
+			return nil, fmt.Errorf("invalid scope supplied: %s, should be any of: %s, %s", scope, models.ScopeTendersWrite, models.ScopeBidsRead)
+		}
+	}
+
+	if t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("expiresAt should be in the future")
+	}
+
+	return t, nil
+}
+
+// New employee request
+
+type NewEmployeeReq struct {
+	Username  string `json:"username"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Password  string `json:"password"`
+}
+
+func ParseNewEmployeeReq(data []byte) (*NewEmployeeReq, error) {
+	t := &NewEmployeeReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Username) == 0 {
+		return nil, fmt.Errorf("empty username supplied")
+	}
+	if err = checkLengthLimit(t.Username, "Username", 50); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.FirstName, "FirstName", 50); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.LastName, "LastName", 50); err != nil {
+		return nil, err
+	}
+
+	// password is optional, employee registered without it is given one-time token to set it later
+	if len(t.Password) > 0 && len(t.Password) < 8 {
+		return nil, fmt.Errorf("password should be at least 8 characters long")
+	}
+	if err = checkLengthLimit(t.Password, "Password", 100); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// NewEmployeeResponse carries one-time password token for employee registered without password
+type NewEmployeeResponse struct {
+	models.User
+	PasswordToken *TokenResponse `json:"passwordToken,omitempty"`
+}
+
+// Edit employee request
+
+type EmployeeChangeReq map[string]string
+
+func ParseEmployeeChangeReq(data []byte) (EmployeeChangeReq, error) {
+	t := EmployeeChangeReq{}
+	vals := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &vals)
+	if err != nil {
+		return nil, err
+	}
+
+	str, ok, err := checkRequestField(vals, "username", 50)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if len(str) == 0 {
+			return nil, fmt.Errorf("empty username supplied")
+		}
+		t["username"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "firstName", 50)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["firstName"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "lastName", 50)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["lastName"] = str
+	}
+
+	return t, nil
+}
+
+// New organization request
+
+type NewOrganizationReq struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Type        models.OrganizationType `json:"type"`
+}
+
+func ParseNewOrganizationReq(data []byte) (*NewOrganizationReq, error) {
+	t := &NewOrganizationReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Name) == 0 {
+		return nil, fmt.Errorf("empty name supplied")
+	}
+	if err = checkLengthLimit(t.Name, "Name", 100); err != nil {
+		return nil, err
+	}
+	if err = checkLengthLimit(t.Description, "Description", 500); err != nil {
+		return nil, err
+	}
+	if !models.ValidOrganizationType(t.Type) {
+		return nil, fmt.Errorf("invalid organization type supplied: %s, should be one of: %s, %s, %s", t.Type, models.IE, models.LLC, models.JSC)
+	}
+
+	return t, nil
+}
+
+// Edit organization request
+
+type OrganizationChangeReq map[string]string
+
+func ParseOrganizationChangeReq(data []byte) (OrganizationChangeReq, error) {
+	t := OrganizationChangeReq{}
+	vals := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &vals)
+	if err != nil {
+		return nil, err
+	}
+
+	str, ok, err := checkRequestField(vals, "name", 100)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if len(str) == 0 {
+			return nil, fmt.Errorf("empty name supplied")
+		}
+		t["name"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "description", 500)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t["description"] = str
+	}
+
+	str, ok, err = checkRequestField(vals, "type", 10)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if !models.ValidOrganizationType(models.OrganizationType(str)) {
+			return nil, fmt.Errorf("invalid organization type supplied: %s", str)
+		}
+		t["type"] = str
+	}
+
+	return t, nil
+}
+
+// New invitation request
+
+type NewInvitationReq struct {
+	Username string      `json:"username"`
+	Role     models.Role `json:"role"`
+}
+
+type NewInvitationResponse struct {
+	models.Invitation
+	Token string `json:"token"`
+}
+
+func ParseNewInvitationReq(data []byte) (*NewInvitationReq, error) {
+	t := &NewInvitationReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Username) == 0 {
+		return nil, fmt.Errorf("empty username supplied")
+	}
+	if err = checkLengthLimit(t.Username, "Username", 50); err != nil {
+		return nil, err
+	}
+
+	if len(t.Role) == 0 {
+		t.Role = models.RoleViewer
+	} else if !models.ValidRole(t.Role) {
+		return nil, fmt.Errorf("invalid role supplied: %s, should be one of: %s, %s, %s, %s", t.Role, models.RoleViewer, models.RoleEditor, models.RoleApprover, models.RoleAdmin)
+	}
+
+	return t, nil
+}
+
+// Invitation response request
+
+type InvitationResponseReq struct {
+	Token string `json:"token"`
+}
+
+func ParseInvitationResponseReq(data []byte) (*InvitationResponseReq, error) {
+	t := &InvitationResponseReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Token) == 0 {
+		return nil, fmt.Errorf("empty token supplied")
+	}
+	if err = checkLengthLimit(t.Token, "Token", 100); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Member role request
+
+type MemberRoleReq struct {
+	Role models.Role `json:"role"`
+}
+
+func ParseMemberRoleReq(data []byte) (*MemberRoleReq, error) {
+	t := &MemberRoleReq{}
+
+	err := json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if !models.ValidRole(t.Role) {
+		return nil, fmt.Errorf("invalid role supplied: %s, should be one of: %s, %s, %s, %s", t.Role, models.RoleViewer, models.RoleEditor, models.RoleApprover, models.RoleAdmin)
+	}
+
+	return t, nil
+}
+
+// Audit log request
+
+// ParseAuditFilter reads audit log filter from query parameters, organizationId is required
+func ParseAuditFilter(query url.Values) (models.AuditFilter, error) {
+	f := models.AuditFilter{
+		OrganizationId: query.Get("organizationId"),
+		ActorId:        query.Get("actorId"),
+		Action:         models.AuditAction(query.Get("action")),
+		EntityType:     models.AuditEntity(query.Get("entityType")),
+		EntityId:       query.Get("entityId"),
+	}
+
+	if len(f.OrganizationId) == 0 {
+		return f, fmt.Errorf("query parameter 'organizationId' is required")
+	}
+	for key, val := range map[string]string{"organizationId": f.OrganizationId, "actorId": f.ActorId, "entityId": f.EntityId} {
+		if len(val) > 0 && !validUUID(val) {
+			return f, fmt.Errorf("query parameter '%s' is not a valid UUID: %s", key, val)
+		}
+	}
+
+	r, err := ParseTimeRange(query, "from", "to")
+	if err != nil {
+		return f, err
+	}
+	f.From, f.To = r.From, r.To
+
+	return f, nil
+}
+
+// Version diff request
+
+// ParseVersionDiffReq reads numbers of versions to compare from 'from' and 'to' query parameters, both are required
+func ParseVersionDiffReq(query url.Values) (from, to int, err error) {
+	versions := make([]int, 2)
+	for i, key := range []string{"from", "to"} {
+		val := query.Get(key)
+		if len(val) == 0 {
+			return 0, 0, fmt.Errorf("query parameter '%s' is required", key)
+		}
+		versions[i], err = strconv.Atoi(val)
+		if err != nil || versions[i] < 1 {
+			return 0, 0, fmt.Errorf("query parameter '%s' is not a valid version number: %s", key, val)
+		}
+	}
+	return versions[0], versions[1], nil
+}
+
+// Precondition request
+
+// ParseIfMatch reads version of entity client expects to change from If-Match header, which should hold
+// single entity tag returned by entityTag or '*'. Zero is returned if any version is acceptable
+func ParseIfMatch(val string) (int, error) {
+	val = strings.TrimSpace(val)
+	if len(val) == 0 || val == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(val)
+	if err != nil || !strings.HasPrefix(val, `"`) {
+		return 0, fmt.Errorf("malformed If-Match header supplied: %s, should be single entity tag", val)
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("malformed If-Match header supplied: %s, should be single entity tag", val)
+	}
+	return version, nil
+}
+
+// entityTag formats version of tender or bid as strong entity tag
+func entityTag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// List requests
+
+// ParseTenderFilter reads tender list filter, full-text query and sort order from query parameters, service types
+// may refer to inactive categories as well, so that their existing tenders can still be found
+func ParseTenderFilter(query url.Values, categories []models.Category) (models.TenderFilter, error) {
+	var err error
+	f := models.TenderFilter{OrganizationId: query.Get("organizationId"), AuthorId: query.Get("authorId")}
+
+	for key, val := range map[string]string{"organizationId": f.OrganizationId, "authorId": f.AuthorId} {
+		if len(val) > 0 && !validUUID(val) {
+			return f, fmt.Errorf("query parameter '%s' is not a valid UUID: %s", key, val)
+		}
+	}
+
+	for _, str := range query["status"] {
+		status := models.TenderStatus(str)
+		if !models.ValidTenderStatus(status) {
+			return f, fmt.Errorf("query parameter 'status' is not a valid tender status: %s", str)
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	f.Created, err = ParseTimeRange(query, "createdFrom", "createdTo")
+	if err != nil {
+		return f, err
+	}
+	f.Updated, err = ParseTimeRange(query, "updatedFrom", "updatedTo")
+	if err != nil {
+		return f, err
+	}
+
+	for _, str := range query["service_type"] {
+		t := models.ServiceType(str)
+		if err = checkServiceType(t, categories, false); err != nil {
+			return f, err
+		}
+		f.ServiceTypes = append(f.ServiceTypes, t)
+	}
+
+	f.Budget, err = ParseMoneyRange(query, "budgetMin", "budgetMax")
+	if err != nil {
+		return f, err
+	}
+
+	f.Query = strings.TrimSpace(query.Get("q"))
+	if err = checkLengthLimit(f.Query, "q", 200); err != nil {
+		return f, err
+	}
+
+	f.Sort, err = ParseSort(query, "name", "budget", "status", "createdAt", "updatedAt", "relevance")
+	if err != nil {
+		return f, err
+	}
+	if len(f.Query) == 0 && slices.ContainsFunc(f.Sort, func(field models.SortField) bool { return field.Field == "relevance" }) {
+		return f, fmt.Errorf("query parameter 'sort' can only contain relevance along with 'q' one")
+	}
+
+	return f, nil
+}
+
+// ParseBidFilter reads bid list filter and sort order from query parameters, author filter is ignored
+// by listing of user's own bids
+func ParseBidFilter(query url.Values) (models.BidFilter, error) {
+	var err error
+	f := models.BidFilter{LotId: query.Get("lotId"), OrganizationId: query.Get("organizationId"), UserId: query.Get("authorId")}
+
+	for key, val := range map[string]string{"lotId": f.LotId, "organizationId": f.OrganizationId, "authorId": f.UserId} {
+		if len(val) > 0 && !validUUID(val) {
+			return f, fmt.Errorf("query parameter '%s' is not a valid UUID: %s", key, val)
+		}
+	}
+
+	for _, str := range query["status"] {
+		status := models.BidStatus(str)
+		if !models.ValidBidStatus(status) {
+			return f, fmt.Errorf("query parameter 'status' is not a valid bid status: %s", str)
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	f.Created, err = ParseTimeRange(query, "createdFrom", "createdTo")
+	if err != nil {
+		return f, err
+	}
+	f.Updated, err = ParseTimeRange(query, "updatedFrom", "updatedTo")
+	if err != nil {
+		return f, err
+	}
+
+	f.Price, err = ParseMoneyRange(query, "priceMin", "priceMax")
+	if err != nil {
+		return f, err
+	}
+
+	f.Sort, err = ParseSort(query, "name", "price", "status", "createdAt", "updatedAt")
+	if err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// ParseMoneyRange reads amount range from minKey and maxKey query parameters and currency from 'currency' one
+func ParseMoneyRange(query url.Values, minKey, maxKey string) (models.MoneyRange, error) {
+	var err error
+	r := models.MoneyRange{Currency: query.Get("currency")}
+
+	if len(r.Currency) > 0 && !models.ValidCurrency(r.Currency) {
+		return r, fmt.Errorf("query parameter 'currency' is not ISO 4217 alphabetic code: %s", r.Currency)
+	}
+
+	for key, dst := range map[string]*models.Decimal{minKey: &r.Min, maxKey: &r.Max} {
+		val := query.Get(key)
+		if len(val) == 0 {
+			continue
+		}
+		*dst, err = models.ParseDecimal(val)
+		if err != nil {
+			return r, fmt.Errorf("query parameter '%s': %w", key, err)
+		}
+	}
+
+	return r, nil
+}
+
+// ParseTimeRange reads RFC 3339 bounds of time range from fromKey and toKey query parameters
+func ParseTimeRange(query url.Values, fromKey, toKey string) (models.TimeRange, error) {
+	r := models.TimeRange{}
+
+	for key, dst := range map[string]**time.Time{fromKey: &r.From, toKey: &r.To} {
+		val := query.Get(key)
+		if len(val) == 0 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return r, fmt.Errorf("query parameter '%s' is not a valid RFC 3339 time: %s", key, val)
+		}
+		*dst = &t
+	}
+
+	if r.From != nil && r.To != nil && !r.From.Before(*r.To) {
+		return r, fmt.Errorf("query parameter '%s' should precede '%s' one", fromKey, toKey)
+	}
+	return r, nil
+}
+
+// ParseSort reads comma separated list of fields from 'sort' query parameter, field prefixed with '-' is sorted
+// in descending order. Only allowed fields are accepted
+func ParseSort(query url.Values, allowed ...string) ([]models.SortField, error) {
+	val := query.Get("sort")
+	if len(val) == 0 {
+		return nil, nil
+	}
+
+	var fields []models.SortField
+	for _, str := range strings.Split(val, ",") {
+		field := models.SortField{Field: strings.TrimSpace(str)}
+		if strings.HasPrefix(field.Field, "-") {
+			field.Field = field.Field[1:]
+			field.Desc = true
+		}
+		if !slices.Contains(allowed, field.Field) {
+			return nil, fmt.Errorf("query parameter 'sort' contains unknown field: %s, should be any of: %s", field.Field, strings.Join(allowed, ", "))
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Service
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validUUID(str string) bool {
+	return uuidRegexp.MatchString(str)
+}
+
+// checkServiceType ensures service type is code of one of categories, which is active if activeOnly is set
+func checkServiceType(t models.ServiceType, categories []models.Category, activeOnly bool) error {
+	i := slices.IndexFunc(categories, func(c models.Category) bool { return c.Code == t })
+	if i < 0 {
+		return fmt.Errorf("invalid service type supplied: %s, should be code of existing category", t)
+	}
+	if activeOnly && !categories[i].Active {
+		return fmt.Errorf("service type %s is no longer active", t)
+	}
+	return nil
+}
+
+// checkCategoryNames ensures category is named in at least one language
+func checkCategoryNames(names map[string]string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("category should be named in at least one language")
+	}
+	for lang, name := range names {
+		if !languageRe.MatchString(lang) {
+			return fmt.Errorf("invalid language code supplied: %s", lang)
+		}
+		if len(strings.TrimSpace(name)) == 0 {
+			return fmt.Errorf("category name in '%s' should not be empty", lang)
+		}
+		if err := checkLengthLimit(name, "Name", 100); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkLengthLimit(str, fieldName string, limit int) error {
+	if len(str) > limit {
+		return fmt.Errorf("field '%s' exceeds length limit: %d / %d", fieldName, len(str), 100)
+	}
+	return nil
+}
+
+func checkRequestField(vals map[string]interface{}, key string, lentghLimit int) (string, bool, error) {
+	val, ok := vals[key]
+	if !ok {
+		return "", false, nil
+	}
+
+	str, ok := val.(string)
+	if !ok {
+		return "", false, fmt.Errorf("invalid type od '%s' field", key)
+	}
+
+	if err := checkLengthLimit(str, key, lentghLimit); err != nil {
+		return "", false, err
+	}
+
+	return str, true, nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Amendment is material change of published tender, bids submitted against
+// earlier versions have to be reconfirmed or revised after it
+type Amendment struct {
+	Id       string `json:"id"`
+	TenderId string `json:"tenderId"`
+	// Version of tender introduced by amendment
+	Version   int       `json:"version"`
+	Reason    string    `json:"reason"`
+	AuthorId  string    `json:"authorId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type APIScope string
+
+const (
+	ScopeTendersWrite APIScope = "tenders:write"
+	ScopeBidsRead     APIScope = "bids:read"
+)
+
+// scopePermissions maps API key scopes to permissions of organization member
+var scopePermissions = map[APIScope][]Permission{
+	ScopeTendersWrite: {PermTenderCreate},
+	ScopeBidsRead:     {PermTenderView, PermBidsView},
+}
+
+func ValidAPIScope(s APIScope) bool {
+	switch s {
+	case ScopeTendersWrite, ScopeBidsRead:
+		return true
+	default:
+		return false
+	}
+}
+
+type APIKey struct {
+	Id             string     `json:"id"`
+	OrganizationId string     `json:"organizationId"`
+	Name           string     `json:"name"`
+	Scopes         []APIScope `json:"scopes"`
+	Hash           string     `json:"-"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+}
+
+// Allows reports whether any of key's scopes grants permission p
+func (k APIKey) Allows(p Permission) bool {
+	for _, s := range k.Scopes {
+		for _, perm := range scopePermissions[s] {
+			if perm == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (k APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Attachment is a document attached to either tender or bid, its content is kept by attachment storage
+type Attachment struct {
+	Id          string `json:"id"`
+	TenderId    string `json:"tenderId,omitempty"`
+	BidId       string `json:"bidId,omitempty"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	// Hex-encoded SHA-256 of content
+	Checksum   string    `json:"sha256"`
+	StorageKey string    `json:"-"`
+	AuthorId   string    `json:"-"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
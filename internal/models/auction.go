@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+type AuctionStatus string
+
+const (
+	AuctionScheduled AuctionStatus = "Scheduled"
+	AuctionRunning   AuctionStatus = "Running"
+	AuctionClosed    AuctionStatus = "Closed"
+)
+
+// Auction is a reverse auction phase of tender, during which its qualified bidders lower prices of their bids.
+// Bid qualifies while it is published, is not to be reconfirmed and is priced in currency of auction
+type Auction struct {
+	TenderId string        `json:"tenderId"`
+	Status   AuctionStatus `json:"status"`
+	StartAt  time.Time     `json:"startAt"`
+	// Prices are accepted until this moment, price submitted less than ExtensionMinutes before it
+	// pushes it back to ExtensionMinutes after submission
+	EndAt            time.Time `json:"endAt"`
+	DurationMinutes  int       `json:"durationMinutes"`
+	ExtensionMinutes int       `json:"extensionMinutes"`
+	Extensions       int       `json:"extensions"`
+	// New price of bid should be lower than its current one at least by this amount
+	MinDecrement Money      `json:"minDecrement"`
+	CreatedBy    string     `json:"-"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ClosedAt     *time.Time `json:"closedAt,omitempty"`
+}
+
+// Running reports whether auction accepts prices at provided moment
+func (a Auction) Running(now time.Time) bool {
+	return a.Status != AuctionClosed && !now.Before(a.StartAt) && now.Before(a.EndAt)
+}
+
+// AuctionPrice is an entry of auction ledger: price submitted for bid during auction
+type AuctionPrice struct {
+	Id       string `json:"id"`
+	TenderId string `json:"tenderId"`
+	BidId    string `json:"bidId"`
+	Price    Money  `json:"price"`
+	// Price of bid before submission
+	PreviousPrice *Money    `json:"previousPrice,omitempty"`
+	UserId        string    `json:"userId,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// AuctionRank is a position of qualified bid by current price, lower price ranks higher and the same
+// price ranks higher when submitted earlier. Price is only shown to tender owner and to bid's author
+type AuctionRank struct {
+	Rank     int       `json:"rank"`
+	BidId    string    `json:"bidId"`
+	Price    *Money    `json:"price,omitempty"`
+	PricedAt time.Time `json:"-"`
+}
+
+// AuctionRanking lists ranks of bids visible to requester along with amount of qualified bids
+type AuctionRanking struct {
+	Status       AuctionStatus `json:"status"`
+	EndAt        time.Time     `json:"endAt"`
+	Participants int           `json:"participants"`
+	Ranks        []AuctionRank `json:"ranks"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditActorType string
+
+const (
+	ActorUser      AuditActorType = "user"
+	ActorAPIKey    AuditActorType = "api_key"
+	ActorAnonymous AuditActorType = "anonymous"
+	// Background jobs of service itself
+	ActorSystem AuditActorType = "system"
+)
+
+type AuditEntity string
+
+const (
+	EntityTender       AuditEntity = "tender"
+	EntityBid          AuditEntity = "bid"
+	EntityEmployee     AuditEntity = "employee"
+	EntityOrganization AuditEntity = "organization"
+	EntityMember       AuditEntity = "member"
+	EntityInvitation   AuditEntity = "invitation"
+	EntityAPIKey       AuditEntity = "api_key"
+	EntityCategory     AuditEntity = "category"
+	EntityQuestion     AuditEntity = "question"
+	EntityTemplate     AuditEntity = "template"
+)
+
+type AuditAction string
+
+const (
+	ActionTenderCreate       AuditAction = "tender.create"
+	ActionTenderStatus       AuditAction = "tender.status"
+	ActionTenderEdit         AuditAction = "tender.edit"
+	ActionTenderRollback     AuditAction = "tender.rollback"
+	ActionTenderCriteria     AuditAction = "tender.criteria"
+	ActionTenderLots         AuditAction = "tender.lots"
+	ActionTenderLotStatus    AuditAction = "tender.lot_status"
+	ActionTenderAmend        AuditAction = "tender.amend"
+	ActionTenderInvited      AuditAction = "tender.invited_organizations"
+	ActionTenderClone        AuditAction = "tender.clone"
+	ActionTenderAttach       AuditAction = "tender.attach"
+	ActionTenderDetach       AuditAction = "tender.detach"
+	ActionTenderOpen         AuditAction = "tender.open"
+	ActionTenderAuction      AuditAction = "tender.auction"
+	ActionAuctionStart       AuditAction = "tender.auction_start"
+	ActionAuctionClose       AuditAction = "tender.auction_close"
+	ActionBidCreate          AuditAction = "bid.create"
+	ActionBidStatus          AuditAction = "bid.status"
+	ActionBidEdit            AuditAction = "bid.edit"
+	ActionBidDecision        AuditAction = "bid.decision"
+	ActionBidFeedback        AuditAction = "bid.feedback"
+	ActionBidRollback        AuditAction = "bid.rollback"
+	ActionBidScore           AuditAction = "bid.score"
+	ActionBidReconfirm       AuditAction = "bid.reconfirm"
+	ActionBidAttach          AuditAction = "bid.attach"
+	ActionBidDetach          AuditAction = "bid.detach"
+	ActionBidAuctionPrice    AuditAction = "bid.auction_price"
+	ActionBidWithdraw        AuditAction = "bid.withdraw"
+	ActionBidResubmit        AuditAction = "bid.resubmit"
+	ActionEmployeeCreate     AuditAction = "employee.create"
+	ActionEmployeeEdit       AuditAction = "employee.edit"
+	ActionEmployeeDelete     AuditAction = "employee.delete"
+	ActionEmployeePassword   AuditAction = "employee.password"
+	ActionEmployeeReset      AuditAction = "employee.password_reset"
+	ActionOrganizationCreate AuditAction = "organization.create"
+	ActionOrganizationEdit   AuditAction = "organization.edit"
+	ActionOrganizationDelete AuditAction = "organization.delete"
+	ActionMemberRole         AuditAction = "member.role"
+	ActionMemberRemove       AuditAction = "member.remove"
+	ActionInvitationCreate   AuditAction = "invitation.create"
+	ActionInvitationRevoke   AuditAction = "invitation.revoke"
+	ActionInvitationAccept   AuditAction = "invitation.accept"
+	ActionInvitationDecline  AuditAction = "invitation.decline"
+	ActionAPIKeyCreate       AuditAction = "api_key.create"
+	ActionAPIKeyRevoke       AuditAction = "api_key.revoke"
+	ActionCategoryCreate     AuditAction = "category.create"
+	ActionCategoryEdit       AuditAction = "category.edit"
+	ActionQuestionCreate     AuditAction = "question.create"
+	ActionQuestionAnswer     AuditAction = "question.answer"
+	ActionTemplateCreate     AuditAction = "template.create"
+	ActionTemplateDelete     AuditAction = "template.delete"
+)
+
+// AuditEntry records single mutation along with state of entity before and after it
+type AuditEntry struct {
+	Id              string          `json:"id"`
+	ActorType       AuditActorType  `json:"actorType"`
+	ActorId         string          `json:"actorId,omitempty"`
+	ActorName       string          `json:"actorName,omitempty"`
+	Action          AuditAction     `json:"action"`
+	EntityType      AuditEntity     `json:"entityType"`
+	EntityId        string          `json:"entityId,omitempty"` // UUID of entity, or code of category
+	OrganizationIds []string        `json:"organizationIds"`
+	Before          json.RawMessage `json:"before,omitempty"`
+	After           json.RawMessage `json:"after,omitempty"`
+	RequestId       string          `json:"requestId,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+// AuditFilter narrows audit log of organization, zero fields are ignored
+type AuditFilter struct {
+	OrganizationId string
+	ActorId        string
+	Action         AuditAction
+	EntityType     AuditEntity
+	EntityId       string
+	From           *time.Time
+	To             *time.Time
+}
@@ -1,53 +1,87 @@
-package models
-
-import "time"
-
-type AuthorType string
-
-const (
-	AuthorUser         AuthorType = "User"
-	AuthorOrganization AuthorType = "Organization"
-)
-
-func ValidAuthorType(t AuthorType) bool {
-	switch t {
-	case AuthorUser, AuthorOrganization:
-		return true
-	default:
-		return false
-	}
-}
-
-type BidStatus string
-
-const (
-	BidCreated   BidStatus = "Created"
-	BidPublished BidStatus = "Published"
-	BidCanceled  BidStatus = "Canceled"
-	BidApproved  BidStatus = "Approved"
-	BidRejected  BidStatus = "Rejected"
-)
-
-func ValidBidStatus(t BidStatus) bool {
-	switch t {
-	case BidCreated, BidPublished, BidCanceled, BidApproved, BidRejected:
-		return true
-	default:
-		return false
-	}
-}
-
-type Bid struct {
-	Id             string     `json:"id"`
-	Version        int        `json:"version"`
-	TenderId       string     `json:"tenderId"`
-	AuthorType     AuthorType `json:"authorType"`
-	AuthorId       string     `json:"authorId"`
-	UserId         string     `json:"-"`
-	OrganizationId string     `json:"-"`
-	Status         BidStatus  `json:"status"`
-	Name           string     `json:"name"`
-	Description    string     `json:"description"`
-	CreatedAt      time.Time  `json:"createdAt"`
-	UpdatedAt      time.Time  `json:"-"`
-}
+package models
+
+import "time"
+
+type AuthorType string
+
+const (
+	AuthorUser         AuthorType = "User"
+	AuthorOrganization AuthorType = "Organization"
+)
+
+func ValidAuthorType(t AuthorType) bool {
+	switch t {
+	case AuthorUser, AuthorOrganization:
+		return true
+	default:
+		return false
+	}
+}
+
+type BidStatus string
+
+const (
+	BidCreated   BidStatus = "Created"
+	BidPublished BidStatus = "Published"
+	BidCanceled  BidStatus = "Canceled"
+	BidApproved  BidStatus = "Approved"
+	BidRejected  BidStatus = "Rejected"
+)
+
+func ValidBidStatus(t BidStatus) bool {
+	switch t {
+	case BidCreated, BidPublished, BidCanceled, BidApproved, BidRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+type Bid struct {
+	Id             string     `json:"id"`
+	Version        int        `json:"version"`
+	TenderId       string     `json:"tenderId"`
+	AuthorType     AuthorType `json:"authorType"`
+	AuthorId       string     `json:"authorId"`
+	UserId         string     `json:"-"`
+	OrganizationId string     `json:"-"`
+	Status         BidStatus  `json:"status"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	// Required for new bids, bids created before prices were introduced have none
+	Price *Money `json:"price,omitempty"`
+	// Lots of tender bid is submitted for, required if and only if tender is split into lots
+	LotIds []string `json:"lotIds,omitempty"`
+	// Version of tender bid is submitted against, bid has to be reconfirmed or revised
+	// before it can be approved once tender is amended
+	TenderVersion          int  `json:"tenderVersion"`
+	ReconfirmationRequired bool `json:"reconfirmationRequired"`
+	// Sealed bid has its content encrypted in SealedContent, name, description and price are blank
+	// unless bid is shown to its author
+	Sealed        bool      `json:"sealed,omitempty"`
+	SealedContent []byte    `json:"-"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"-"`
+}
+
+// BidContent is part of bid encrypted while bids of sealed tender are not opened
+type BidContent struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       *Money `json:"price,omitempty"`
+}
+
+// BidFilter narrows list of bids, zero fields are ignored
+type BidFilter struct {
+	BidId    string
+	UserId   string
+	TenderId string
+	LotId    string
+	// Organization bid is submitted on behalf of
+	OrganizationId string
+	Statuses       []BidStatus
+	Created        TimeRange
+	Updated        TimeRange
+	Price          MoneyRange
+	Sort           []SortField
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type BidEventType string
+
+const (
+	BidWithdrawn   BidEventType = "Withdrawn"
+	BidResubmitted BidEventType = "Resubmitted"
+)
+
+// BidEvent records withdrawal or resubmission of bid by its author. Reason is required for withdrawal
+// and optional for resubmission
+type BidEvent struct {
+	Id       string       `json:"id"`
+	BidId    string       `json:"bidId"`
+	TenderId string       `json:"tenderId"`
+	Type     BidEventType `json:"type"`
+	Reason   string       `json:"reason,omitempty"`
+	// Status of bid before event
+	PreviousStatus BidStatus `json:"previousStatus"`
+	UserId         string    `json:"userId,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
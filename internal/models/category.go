@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Category is a node of service type taxonomy, its code is used as service type of tenders
+type Category struct {
+	Code       ServiceType `json:"code"`
+	ParentCode ServiceType `json:"parentCode,omitempty"`
+	// Names of category by language code, e.g. {"en": "Delivery", "ru": "Доставка"}
+	Names map[string]string `json:"names"`
+	// Inactive categories can not be assigned to tenders anymore, but are kept by existing ones
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"-"`
+}
@@ -1,14 +1,64 @@
-package models
-
-import "errors"
-
-var (
-	ErrInvalidUser            = errors.New("provided user either does not exist or has no permission for this operation")
-	ErrForbidden              = errors.New("provided user does not have permission for this operation")
-	ErrNoTender               = errors.New("requestd tender does not exist")
-	ErrTenderFinalized        = errors.New("tender is already closed")
-	ErrNoBid                  = errors.New("requestd bid does not exist")
-	ErrNoVersion              = errors.New("required version does not exist")
-	ErrBidFinalized           = errors.New("bid is already approved or rejected")
-	ErrBidCannotBeApprovedYet = errors.New("bid has not enough votes to be approved")
-)
+package models
+
+import "errors"
+
+var (
+	ErrInvalidUser            = errors.New("provided user either does not exist or has no permission for this operation")
+	ErrForbidden              = errors.New("provided user does not have permission for this operation")
+	ErrInvalidCredentials     = errors.New("provided credentials are invalid or expired")
+	ErrLegacyAuth             = errors.New("operation is not available with legacy username authentication")
+	ErrNoTender               = errors.New("requestd tender does not exist")
+	ErrTenderFinalized        = errors.New("tender is already closed")
+	ErrNoBid                  = errors.New("requestd bid does not exist")
+	ErrNoVersion              = errors.New("required version does not exist")
+	ErrVersionConflict        = errors.New("entity is changed since requested version, fetch it again and retry")
+	ErrBidFinalized           = errors.New("bid is already approved or rejected")
+	ErrBidCannotBeApprovedYet = errors.New("bid has not enough votes to be approved")
+	ErrNoAPIKey               = errors.New("requested api key does not exist")
+	ErrNoEmployee             = errors.New("requested employee does not exist")
+	ErrNoOrganization         = errors.New("requested organization does not exist")
+	ErrUsernameTaken          = errors.New("username is already taken")
+	ErrAlreadyMember          = errors.New("employee is already a member of organization")
+	ErrNoMember               = errors.New("employee is not a member of organization")
+	ErrLastAdmin              = errors.New("organization must have at least one administrator")
+	ErrNoInvitation           = errors.New("requested invitation does not exist")
+	ErrInvitationExists       = errors.New("employee already has pending invitation to organization")
+	ErrInvitationClosed       = errors.New("invitation is already accepted, declined, revoked or expired")
+	ErrDeadlinePassed         = errors.New("tender submission deadline has passed")
+	ErrInvalidSchedule        = errors.New("only tenders in Created status can be scheduled, publication should precede submission deadline")
+	ErrCurrencyMismatch       = errors.New("bid price currency differs from tender budget currency")
+	ErrNoCriterion            = errors.New("requested evaluation criterion does not exist")
+	ErrCriteriaLocked         = errors.New("evaluation criteria can not be changed after bids are scored")
+	ErrNoLot                  = errors.New("requested lot does not exist")
+	ErrLotRequired            = errors.New("tender is split into lots, lot should be specified")
+	ErrLotsLocked             = errors.New("lots can not be changed after bids are submitted")
+	ErrLotFinalized           = errors.New("lot is already awarded or cancelled")
+	ErrNoCategory             = errors.New("requested category does not exist")
+	ErrCategoryExists         = errors.New("category with this code already exists")
+	ErrCategoryCycle          = errors.New("category can not descend from itself")
+	ErrNoQuestion             = errors.New("requested question does not exist")
+	ErrQuestionsClosed        = errors.New("questions are only accepted and answered while tender is published")
+	ErrAmendmentReason        = errors.New("reason is required to amend published tender with submitted bids")
+	ErrReconfirmationRequired = errors.New("bid is submitted against outdated version of tender and has to be reconfirmed or revised")
+	ErrCancellationReason     = errors.New("reason is required to cancel tender")
+	ErrTenderNameTaken        = errors.New("tender with this name already exists")
+	ErrNoTemplate             = errors.New("requested template does not exist")
+	ErrTemplateExists         = errors.New("organization already has template with this name")
+	ErrNoAttachment           = errors.New("requested attachment does not exist")
+	ErrAttachmentTooLarge     = errors.New("attachment exceeds maximum size")
+	ErrAttachmentType         = errors.New("attachment type is not accepted")
+	ErrBidsSealed             = errors.New("bids of sealed tender are not opened yet")
+	ErrSealingUnavailable     = errors.New("sealed tenders are not available, sealing key is not configured")
+	ErrOpeningNotDue          = errors.New("bids can only be opened once after submission deadline of sealed tender")
+	ErrNoAuction              = errors.New("tender has no auction")
+	ErrAuctionUnavailable     = errors.New("auction is only held for published or closed tender without lots, bids of which are not sealed")
+	ErrAuctionSchedule        = errors.New("auction should start after submission deadline of tender, which has to be set")
+	ErrAuctionStarted         = errors.New("auction has already started, its terms and prices of its bids can not be changed")
+	ErrAuctionNotRunning      = errors.New("auction is not running, prices are not accepted")
+	ErrNotQualified           = errors.New("bid does not qualify for auction")
+	ErrPriceDecrement         = errors.New("new price should be lower than current one at least by minimum decrement of auction")
+	ErrWithdrawalReason       = errors.New("reason is required to withdraw bid")
+	ErrBidWithdrawn           = errors.New("bid is withdrawn, it can only be resubmitted")
+	ErrBidNotWithdrawn        = errors.New("only withdrawn bid can be resubmitted")
+	ErrResubmissionClosed     = errors.New("bid can only be resubmitted while its tender is published and accepts bids")
+)
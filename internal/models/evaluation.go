@@ -0,0 +1,75 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MaxScore is the highest score evaluator can give bid by single criterion, lowest one is 0
+const MaxScore = 10
+
+// Criterion is a weighted aspect bids of tender are evaluated by, e.g. price or delivery time
+type Criterion struct {
+	Id          string    `json:"id"`
+	TenderId    string    `json:"tenderId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Weight      int       `json:"weight"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// BidScore is a score given to bid by single evaluator by single criterion
+type BidScore struct {
+	BidId       string    `json:"bidId"`
+	CriterionId string    `json:"criterionId"`
+	UserId      string    `json:"userId"`
+	Score       int       `json:"score"`
+	Comment     string    `json:"comment"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CriterionResult is an average score of bid by criterion
+type CriterionResult struct {
+	CriterionId string  `json:"criterionId"`
+	Weight      int     `json:"weight"`
+	Average     float64 `json:"average"`
+	Evaluations int     `json:"evaluations"`
+}
+
+// BidRanking is a position of bid among bids of tender by weighted score
+type BidRanking struct {
+	Rank     int               `json:"rank"`
+	BidId    string            `json:"bidId"`
+	BidName  string            `json:"bidName"`
+	Score    float64           `json:"score"`
+	Criteria []CriterionResult `json:"criteria"`
+}
+
+// RankBids computes weighted score of each bid, which is an average of criteria scores weighted by criteria
+// weights, and orders bids by it. Criteria no one has scored yet count as 0. Bids with equal scores share rank
+func RankBids(rankings []BidRanking) []BidRanking {
+	for i := range rankings {
+		total, weights := 0.0, 0
+		for _, c := range rankings[i].Criteria {
+			total += c.Average * float64(c.Weight)
+			weights += c.Weight
+		}
+		if weights > 0 {
+			rankings[i].Score = math.Round(total/float64(weights)*100) / 100
+		}
+	}
+
+	sort.SliceStable(rankings, func(i, j int) bool {
+		return rankings[i].Score > rankings[j].Score
+	})
+	for i := range rankings {
+		if i > 0 && rankings[i].Score == rankings[i-1].Score {
+			rankings[i].Rank = rankings[i-1].Rank
+		} else {
+			rankings[i].Rank = i + 1
+		}
+	}
+	return rankings
+}
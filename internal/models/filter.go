@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// TimeRange matches moments from From inclusive to To exclusive, either of bounds is optional
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+	// Expired is not stored, pending invitations get it once their expiry time passes
+	InvitationExpired InvitationStatus = "expired"
+)
+
+func ValidInvitationStatus(s InvitationStatus) bool {
+	switch s {
+	case InvitationPending, InvitationAccepted, InvitationDeclined, InvitationRevoked, InvitationExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+type Invitation struct {
+	Id             string           `json:"id"`
+	OrganizationId string           `json:"organizationId"`
+	Username       string           `json:"username"`
+	Role           Role             `json:"role"`
+	Status         InvitationStatus `json:"status"`
+	InvitedBy      string           `json:"invitedBy,omitempty"`
+	TokenHash      string           `json:"-"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	ExpiresAt      time.Time        `json:"expiresAt"`
+	RespondedAt    *time.Time       `json:"respondedAt,omitempty"`
+}
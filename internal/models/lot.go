@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type LotStatus string
+
+const (
+	LotOpen      LotStatus = "Open"
+	LotAwarded   LotStatus = "Awarded"
+	LotCancelled LotStatus = "Cancelled"
+)
+
+// Lot is an independently awarded part of tender, tender with lots is closed once each of them is
+// either awarded or cancelled
+type Lot struct {
+	Id          string `json:"id"`
+	TenderId    string `json:"tenderId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	// Estimated budget, optional. Confidentiality of tender budget applies to it as well
+	Budget *Money    `json:"budget,omitempty"`
+	Status LotStatus `json:"status"`
+	// Bid lot is awarded to, set once it is approved for the lot
+	AwardedBidId string    `json:"awardedBidId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"-"`
+}
+
+// LotsSettled reports whether no lot is open anymore, empty list is never settled
+func LotsSettled(lots []Lot) bool {
+	for _, lot := range lots {
+		if lot.Status == LotOpen {
+			return false
+		}
+	}
+	return len(lots) > 0
+}
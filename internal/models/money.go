@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Decimal is exact non-negative decimal number kept in canonical textual form: without
+// leading zeros in integer part and trailing zeros in fractional one
+type Decimal string
+
+var decimalRegexp = regexp.MustCompile(`^(\d{1,16})(?:\.(\d{1,4}))?$`)
+
+// ParseDecimal validates and canonicalizes decimal with at most 16 integer and 4 fractional digits
+func ParseDecimal(str string) (Decimal, error) {
+	m := decimalRegexp.FindStringSubmatch(str)
+	if m == nil {
+		return "", fmt.Errorf("invalid amount supplied: %s, should be non-negative number with at most 16 integer and 4 fractional digits", str)
+	}
+
+	integer := strings.TrimLeft(m[1], "0")
+	if len(integer) == 0 {
+		integer = "0"
+	}
+	fraction := strings.TrimRight(m[2], "0")
+	if len(fraction) == 0 {
+		return Decimal(integer), nil
+	}
+	return Decimal(integer + "." + fraction), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and strings, numbers are read as is without rounding
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if strings.HasPrefix(str, `"`) {
+		err := json.Unmarshal(data, &str)
+		if err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDecimal(str)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON writes decimal as string, so clients do not lose precision
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(d))
+}
+
+// Cmp compares decimals numerically and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	return d.units().Cmp(other.units())
+}
+
+// Add returns sum of decimals, it may exceed limits of ParseDecimal
+func (d Decimal) Add(other Decimal) Decimal {
+	digits := new(big.Int).Add(d.units(), other.units()).String()
+	if len(digits) <= 4 {
+		digits = strings.Repeat("0", 5-len(digits)) + digits
+	}
+
+	integer := strings.TrimLeft(digits[:len(digits)-4], "0")
+	if len(integer) == 0 {
+		integer = "0"
+	}
+	fraction := strings.TrimRight(digits[len(digits)-4:], "0")
+	if len(fraction) == 0 {
+		return Decimal(integer)
+	}
+	return Decimal(integer + "." + fraction)
+}
+
+// units returns decimal as integer amount of ten-thousandths, the smallest fraction decimal keeps
+func (d Decimal) units() *big.Int {
+	integer, fraction, _ := strings.Cut(string(d), ".")
+	units, ok := new(big.Int).SetString(integer+(fraction + "0000")[:4], 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return units
+}
+
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func (m Money) Validate() error {
+	if len(m.Amount) == 0 {
+		return fmt.Errorf("empty amount supplied")
+	}
+	if !ValidCurrency(m.Currency) {
+		return fmt.Errorf("invalid currency supplied: %s, should be ISO 4217 alphabetic code", m.Currency)
+	}
+	return nil
+}
+
+// MoneyRange narrows list by amount, zero fields are ignored. Amounts in different currencies are never compared
+type MoneyRange struct {
+	Currency string
+	Min      Decimal
+	Max      Decimal
+}
+
+// ValidCurrency reports whether code is active ISO 4217 alphabetic currency code
+func ValidCurrency(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+var currencies = map[string]struct{}{}
+
+func init() {
+	codes := `AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BOV BRL BSD BTN BWP BYN BZD
+	CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC CUC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP
+	GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR
+	LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP
+	PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY
+	TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF XAG XAU XBA XBB XBC XBD XCD XDR XOF XPD XPF XPT
+	XSU XUA YER ZAR ZMW ZWG`
+
+	for _, code := range strings.Fields(codes) {
+		currencies[code] = struct{}{}
+	}
+}
@@ -1,20 +1,35 @@
-package models
-
-import "time"
-
-type OrganizationType string
-
-const (
-	IE  OrganizationType = "IE"
-	LLC OrganizationType = "LLC"
-	JSC OrganizationType = "JSC"
-)
-
-type Organization struct {
-	Id          int
-	Name        string
-	Description string
-	Type        OrganizationType
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-}
+package models
+
+import "time"
+
+type OrganizationType string
+
+const (
+	IE  OrganizationType = "IE"
+	LLC OrganizationType = "LLC"
+	JSC OrganizationType = "JSC"
+)
+
+func ValidOrganizationType(t OrganizationType) bool {
+	switch t {
+	case IE, LLC, JSC:
+		return true
+	default:
+		return false
+	}
+}
+
+type Organization struct {
+	Id          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Type        OrganizationType `json:"type"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+// Member is an employee of organization along with their role in it
+type Member struct {
+	User
+	Role Role `json:"role"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type QuestionStatus string
+
+const (
+	QuestionOpen     QuestionStatus = "Open"
+	QuestionAnswered QuestionStatus = "Answered"
+	// Questions left unanswered are closed along with their tender
+	QuestionClosed QuestionStatus = "Closed"
+)
+
+// Question is clarification requested by bidder on published tender, its author and
+// answering employee are never disclosed
+type Question struct {
+	Id         string         `json:"id"`
+	TenderId   string         `json:"tenderId"`
+	AuthorId   string         `json:"-"`
+	Question   string         `json:"question"`
+	Answer     string         `json:"answer,omitempty"`
+	AnsweredBy string         `json:"-"`
+	AnsweredAt *time.Time     `json:"answeredAt,omitempty"`
+	Status     QuestionStatus `json:"status"`
+	// Set on questions asked by request principal
+	Mine      bool      `json:"mine,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// QuestionFilter narrows list of tender questions, unanswered ones are only listed
+// if All is set or they are asked by AuthorId
+type QuestionFilter struct {
+	All      bool
+	AuthorId string
+}
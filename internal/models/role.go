@@ -0,0 +1,90 @@
+package models
+
+import "fmt"
+
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleEditor   Role = "editor"
+	RoleApprover Role = "approver"
+	RoleAdmin    Role = "admin"
+)
+
+func ValidRole(r Role) bool {
+	switch r {
+	case RoleViewer, RoleEditor, RoleApprover, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+type Permission string
+
+const (
+	PermTenderView         Permission = "tender:view"
+	PermTenderCreate       Permission = "tender:create"
+	PermTenderEdit         Permission = "tender:edit"
+	PermTenderStatus       Permission = "tender:status"
+	PermBidsView           Permission = "bids:view"
+	PermBidSubmit          Permission = "bid:submit"
+	PermBidApprove         Permission = "bid:approve"
+	PermBidFeedback        Permission = "bid:feedback"
+	PermBidScore           Permission = "bid:score"
+	PermOrganizationManage Permission = "organization:manage"
+	PermAuditView          Permission = "audit:view"
+	// Granted to service administrators rather than organization roles, see config.AuthConfig
+	PermCategoriesManage Permission = "categories:manage"
+)
+
+// rolePermissions is a permission matrix of organization members
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {
+		PermTenderView, PermBidsView,
+	},
+	RoleEditor: {
+		PermTenderView, PermTenderCreate, PermTenderEdit, PermTenderStatus, PermBidsView, PermBidSubmit,
+	},
+	RoleApprover: {
+		PermTenderView, PermBidsView, PermBidApprove, PermBidFeedback, PermBidScore,
+	},
+	RoleAdmin: {
+		PermTenderView, PermTenderCreate, PermTenderEdit, PermTenderStatus, PermBidsView, PermBidSubmit,
+		PermBidApprove, PermBidFeedback, PermBidScore, PermOrganizationManage, PermAuditView,
+	},
+}
+
+// Can reports whether member with role r is granted permission p
+func (r Role) Can(p Permission) bool {
+	for _, perm := range rolePermissions[r] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWith returns all roles granted permission p
+func RolesWith(p Permission) []Role {
+	var roles []Role
+	for _, r := range []Role{RoleViewer, RoleEditor, RoleApprover, RoleAdmin} {
+		if r.Can(p) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// PermissionError is returned when principal lacks permission required for operation
+type PermissionError struct {
+	Permission Permission
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: missing permission '%s'", ErrForbidden, e.Permission)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrForbidden
+}
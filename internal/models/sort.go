@@ -0,0 +1,7 @@
+package models
+
+// SortField is a field list is ordered by
+type SortField struct {
+	Field string
+	Desc  bool
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Template is a reusable draft of tender, new tenders are cloned from it along with its lots and criteria
+type Template struct {
+	Id             string `json:"id"`
+	OrganizationId string `json:"organizationId"`
+	// Name of template itself, unique within organization
+	Name string `json:"name"`
+	// Tender template is created from, if any
+	SourceTenderId     string              `json:"sourceTenderId,omitempty"`
+	Description        string              `json:"description"`
+	ServiceType        ServiceType         `json:"serviceType"`
+	Budget             *Money              `json:"budget,omitempty"`
+	BudgetConfidential bool                `json:"budgetConfidential"`
+	Visibility         TenderVisibility    `json:"visibility"`
+	Lots               []TemplateLot       `json:"lots"`
+	Criteria           []TemplateCriterion `json:"criteria"`
+	AuthorId           string              `json:"-"`
+	CreatedAt          time.Time           `json:"createdAt"`
+	UpdatedAt          time.Time           `json:"-"`
+}
+
+// TemplateLot is a lot of tender cloned from template
+type TemplateLot struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	Budget      *Money `json:"budget,omitempty"`
+}
+
+// TemplateCriterion is an evaluation criterion of tender cloned from template
+type TemplateCriterion struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Weight      int    `json:"weight"`
+}
+
+// TemplateLots converts lots of tender into ones of template
+func TemplateLots(lots []Lot) []TemplateLot {
+	result := make([]TemplateLot, 0, len(lots))
+	for _, lot := range lots {
+		result = append(result, TemplateLot{Name: lot.Name, Description: lot.Description, Quantity: lot.Quantity, Budget: lot.Budget})
+	}
+	return result
+}
+
+// TenderLots converts lots of template into ones of tender
+func (t Template) TenderLots() []Lot {
+	result := make([]Lot, 0, len(t.Lots))
+	for _, lot := range t.Lots {
+		result = append(result, Lot{Name: lot.Name, Description: lot.Description, Quantity: lot.Quantity, Budget: lot.Budget})
+	}
+	return result
+}
+
+// TemplateCriteria converts evaluation criteria of tender into ones of template
+func TemplateCriteria(criteria []Criterion) []TemplateCriterion {
+	result := make([]TemplateCriterion, 0, len(criteria))
+	for _, c := range criteria {
+		result = append(result, TemplateCriterion{Name: c.Name, Description: c.Description, Weight: c.Weight})
+	}
+	return result
+}
+
+// TenderCriteria converts evaluation criteria of template into ones of tender
+func (t Template) TenderCriteria() []Criterion {
+	result := make([]Criterion, 0, len(t.Criteria))
+	for _, c := range t.Criteria {
+		result = append(result, Criterion{Name: c.Name, Description: c.Description, Weight: c.Weight})
+	}
+	return result
+}
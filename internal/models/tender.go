@@ -1,50 +1,124 @@
-package models
-
-import "time"
-
-type TenderStatus string
-
-const (
-	TenderCreated   TenderStatus = "Created"
-	TenderPublished TenderStatus = "Published"
-	TenderClosed    TenderStatus = "Closed"
-)
-
-func ValidTenderStatus(t TenderStatus) bool {
-	switch t {
-	case TenderCreated, TenderPublished, TenderClosed:
-		return true
-	default:
-		return false
-	}
-}
-
-type ServiceType string
-
-const (
-	STConstruction ServiceType = "Construction"
-	STDelivery     ServiceType = "Delivery"
-	STManufacture  ServiceType = "Manufacture"
-)
-
-func ValidServiceType(t ServiceType) bool {
-	switch t {
-	case STConstruction, STDelivery, STManufacture:
-		return true
-	default:
-		return false
-	}
-}
-
-type Tender struct {
-	Id             string       `json:"id"`
-	Version        int          `json:"version"`
-	OrganizationId string       `json:"organizationId"`
-	Author         string       `json:"-"`
-	Status         TenderStatus `json:"status"`
-	ServiceType    ServiceType  `json:"serviceType"`
-	Name           string       `json:"name"`
-	Description    string       `json:"description"`
-	CreatedAt      time.Time    `json:"createdAt"`
-	UpdatedAt      time.Time    `json:"-"`
-}
+package models
+
+import "time"
+
+type TenderStatus string
+
+const (
+	TenderCreated   TenderStatus = "Created"
+	TenderPublished TenderStatus = "Published"
+	TenderClosed    TenderStatus = "Closed"
+	// Outcomes of tender, cancelled one has reason, awarded one has winning bids
+	TenderCancelled TenderStatus = "Cancelled"
+	TenderAwarded   TenderStatus = "Awarded"
+)
+
+func ValidTenderStatus(t TenderStatus) bool {
+	switch t {
+	case TenderCreated, TenderPublished, TenderClosed, TenderCancelled, TenderAwarded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Final reports whether tender no longer accepts bids and can not be changed
+func (t TenderStatus) Final() bool {
+	return t == TenderClosed || t == TenderCancelled || t == TenderAwarded
+}
+
+// Outcome reports whether tender is either cancelled or awarded, closed tender may still get one of them
+func (t TenderStatus) Outcome() bool {
+	return t == TenderCancelled || t == TenderAwarded
+}
+
+// ServiceType is a code of category from service type taxonomy, see Category
+type ServiceType string
+
+// Categories created by migrations, others are managed at runtime
+const (
+	STConstruction ServiceType = "Construction"
+	STDelivery     ServiceType = "Delivery"
+	STManufacture  ServiceType = "Manufacture"
+)
+
+// DeadlinePassed reports whether bids are no longer accepted at provided moment
+func (t Tender) DeadlinePassed(now time.Time) bool {
+	return t.SubmissionDeadline != nil && !now.Before(*t.SubmissionDeadline)
+}
+
+// BidsSealed reports whether bids of tender are sealed and not opened yet
+func (t Tender) BidsSealed() bool {
+	return t.Sealed && t.OpenedAt == nil
+}
+
+// MateriallyDiffers reports whether tenders differ in terms bids are based on: scope of work, budget or deadline
+func (t Tender) MateriallyDiffers(other Tender) bool {
+	return t.Description != other.Description || t.ServiceType != other.ServiceType ||
+		!moneyEqual(t.Budget, other.Budget) || !timeEqual(t.SubmissionDeadline, other.SubmissionDeadline)
+}
+
+func moneyEqual(a, b *Money) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func timeEqual(a, b *time.Time) bool {
+	return a == nil && b == nil || a != nil && b != nil && a.Equal(*b)
+}
+
+// TenderFilter narrows list of tenders, zero fields are ignored
+type TenderFilter struct {
+	TenderId       string
+	AuthorId       string
+	OrganizationId string
+	Statuses       []TenderStatus
+	Created        TimeRange
+	Updated        TimeRange
+	// Tenders of descendant categories are matched as well
+	ServiceTypes []ServiceType
+	// Confidential budgets are only matched for tenders of organizations in VisibleTo, if Restricted is set
+	Budget MoneyRange
+	// Full-text query matched against name and description, matching tenders are ordered
+	// by relevance unless Sort is provided
+	Query string
+	Sort  []SortField
+	// When set, tenders not open to public (e.g. scheduled ones) are only
+	// listed if they belong to one of organizations in VisibleTo
+	Restricted bool
+	VisibleTo  []string
+}
+
+type Tender struct {
+	Id             string       `json:"id"`
+	Version        int          `json:"version"`
+	OrganizationId string       `json:"organizationId"`
+	Author         string       `json:"-"`
+	Status         TenderStatus `json:"status"`
+	ServiceType    ServiceType  `json:"serviceType"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	// Estimated budget, optional
+	Budget *Money `json:"budget,omitempty"`
+	// Confidential budget is only shown to employees of owning organization
+	BudgetConfidential bool `json:"budgetConfidential"`
+	// Invite-only tender is hidden from organizations not invited to it
+	Visibility TenderVisibility `json:"visibility"`
+	// Tender in Created status is published automatically at this moment, cleared once it is published
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+	// Bids are not accepted after deadline, tender is closed automatically once it passes
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	// Tender or template tender is cloned from, if any
+	SourceTenderId   string `json:"sourceTenderId,omitempty"`
+	SourceTemplateId string `json:"sourceTemplateId,omitempty"`
+	// Set once tender is cancelled
+	CancellationReason string `json:"cancellationReason,omitempty"`
+	// Bids tender is awarded to, tenders split into lots are awarded to bids winning any of them
+	AwardedBidIds []string `json:"awardedBidIds,omitempty"`
+	// Bids of sealed tender are encrypted and not shown to anyone but their authors until opened
+	// by tender owner after submission deadline
+	Sealed    bool       `json:"sealed"`
+	OpenedAt  *time.Time `json:"openedAt,omitempty"`
+	OpenedBy  string     `json:"openedBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"-"`
+}
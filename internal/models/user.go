@@ -1,12 +1,12 @@
-package models
-
-import "time"
-
-type User struct {
-	Id        string
-	Username  string
-	FirstName string
-	LastName  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-}
+package models
+
+import "time"
+
+type User struct {
+	Id        string    `json:"id"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "encoding/json"
+
+// FieldChange is change of single field between two versions of tender or bid, values are
+// rendered the same way as in entity itself, absent value is null
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// VersionDiff lists fields changed between two versions of tender or bid, ordered by field name
+type VersionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
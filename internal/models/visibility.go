@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type TenderVisibility string
+
+const (
+	VisibilityPublic TenderVisibility = "Public"
+	// Invite-only tender is only visible to owning organization and organizations it invites,
+	// others are not told it exists
+	VisibilityInviteOnly TenderVisibility = "InviteOnly"
+)
+
+func ValidTenderVisibility(v TenderVisibility) bool {
+	switch v {
+	case VisibilityPublic, VisibilityInviteOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// InvitedOrganization is an organization allowed to see invite-only tender and submit bids for it
+type InvitedOrganization struct {
+	OrganizationId string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	InvitedBy      string    `json:"-"`
+	InvitedAt      time.Time `json:"invitedAt"`
+}
@@ -1,53 +1,53 @@
-package db
-
-import (
-	"database/sql"
-	"fmt"
-	"log"
-
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-)
-
-func MigrateUp(db *sql.DB, migrationsURL string) error {
-	log.Println("Migrating up:", migrationsURL)
-
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("repository.Migrate: %w", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(migrationsURL, "postgres", driver)
-	if err != nil {
-		return fmt.Errorf("repository.Migrate: %w", err)
-	}
-
-	err = m.Up()
-	if err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("repository.Migrate: %w", err)
-	}
-
-	return nil
-}
-
-func MigrateDown(db *sql.DB, migrationsURL string) error {
-	log.Println("Migrating down:", migrationsURL)
-
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("repository.Migrate: %w", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(migrationsURL, "postgres", driver)
-	if err != nil {
-		return fmt.Errorf("repository.Migrate: %w", err)
-	}
-
-	err = m.Down()
-	if err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("repository.Migrate: %w", err)
-	}
-
-	return nil
-}
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+func MigrateUp(db *sql.DB, migrationsURL string) error {
+	log.Println("Migrating up:", migrationsURL)
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("repository.Migrate: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(migrationsURL, "postgres", driver)
+	if err != nil {
+		return fmt.Errorf("repository.Migrate: %w", err)
+	}
+
+	err = m.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("repository.Migrate: %w", err)
+	}
+
+	return nil
+}
+
+func MigrateDown(db *sql.DB, migrationsURL string) error {
+	log.Println("Migrating down:", migrationsURL)
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("repository.Migrate: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(migrationsURL, "postgres", driver)
+	if err != nil {
+		return fmt.Errorf("repository.Migrate: %w", err)
+	}
+
+	err = m.Down()
+	if err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("repository.Migrate: %w", err)
+	}
+
+	return nil
+}
@@ -1,3 +1,3 @@
-DROP TABLE IF EXISTS tenders, tenders_versions CASCADE;
-
+DROP TABLE IF EXISTS tenders, tenders_versions CASCADE;
+
 DROP TYPE IF EXISTS tender_status, tender_service_type CASCADE;
\ No newline at end of file
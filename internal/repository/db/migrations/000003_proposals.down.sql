@@ -1,3 +1,3 @@
-DROP TABLE IF EXISTS proposal_reviews, proposals_versions, proposals CASCADE;
-
+DROP TABLE IF EXISTS proposal_reviews, proposals_versions, proposals CASCADE;
+
 DROP TYPE IF EXISTS proposal_status, proposal_authorType CASCADE;
\ No newline at end of file
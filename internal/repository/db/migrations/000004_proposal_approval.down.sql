@@ -1,3 +1,3 @@
-DROP TABLE IF EXISTS proposal_approval CASCADE;
-
+DROP TABLE IF EXISTS proposal_approval CASCADE;
+
 DROP TYPE IF EXISTS approval_status CASCADE;
\ No newline at end of file
@@ -1,17 +1,17 @@
-DO $$ BEGIN
-    CREATE TYPE approval_status AS ENUM (
-        'Approved',
-        'Rejected'
-    );
-EXCEPTION
-    WHEN duplicate_object THEN null;
-END $$;
-
-CREATE TABLE IF NOT EXISTS proposal_approval (
-    proposal_id UUID REFERENCES proposals(id) ON DELETE CASCADE,
-    user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
-    status approval_status,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(proposal_id, user_id)
+DO $$ BEGIN
+    CREATE TYPE approval_status AS ENUM (
+        'Approved',
+        'Rejected'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS proposal_approval (
+    proposal_id UUID REFERENCES proposals(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+    status approval_status,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(proposal_id, user_id)
 );
\ No newline at end of file
@@ -0,0 +1,13 @@
+DO $$ BEGIN
+    CREATE TYPE organization_role AS ENUM (
+        'viewer',
+        'editor',
+        'approver',
+        'admin'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Existing members keep full access they had before roles were introduced
+ALTER TABLE organization_responsible ADD COLUMN IF NOT EXISTS role organization_role NOT NULL DEFAULT 'admin';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS organization_invitations CASCADE;
+DROP TYPE IF EXISTS invitation_status;
@@ -0,0 +1,27 @@
+DO $$ BEGIN
+    CREATE TYPE invitation_status AS ENUM (
+        'pending',
+        'accepted',
+        'declined',
+        'revoked'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Invitee is referenced by username, so employees can be invited before registration
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID REFERENCES organization(id) ON DELETE CASCADE,
+    username VARCHAR(50) NOT NULL,
+    role organization_role NOT NULL DEFAULT 'viewer',
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    status invitation_status NOT NULL DEFAULT 'pending',
+    invited_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS organization_invitations_organization_id_idx ON organization_invitations (organization_id);
+CREATE INDEX IF NOT EXISTS organization_invitations_username_idx ON organization_invitations (username);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tenders_submission_deadline_idx;
+
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS submission_deadline;
+ALTER TABLE tenders DROP COLUMN IF EXISTS submission_deadline;
@@ -0,0 +1,5 @@
+-- Deadlines are stored in UTC
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMP;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS tenders_submission_deadline_idx ON tenders (submission_deadline) WHERE status = 'Published';
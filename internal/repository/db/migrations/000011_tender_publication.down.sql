@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tenders_publish_at_idx;
+
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE tenders DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,5 @@
+-- Publication times are stored in UTC
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS tenders_publish_at_idx ON tenders (publish_at) WHERE status = 'Created';
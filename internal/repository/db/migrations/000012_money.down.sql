@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS proposals_price_idx;
+DROP INDEX IF EXISTS tenders_budget_idx;
+
+ALTER TABLE proposals_versions DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE proposals_versions DROP COLUMN IF EXISTS price_amount;
+ALTER TABLE proposals DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE proposals DROP COLUMN IF EXISTS price_amount;
+
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS budget_confidential;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS budget_currency;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS budget_amount;
+ALTER TABLE tenders DROP COLUMN IF EXISTS budget_confidential;
+ALTER TABLE tenders DROP COLUMN IF EXISTS budget_currency;
+ALTER TABLE tenders DROP COLUMN IF EXISTS budget_amount;
@@ -0,0 +1,16 @@
+-- Amounts are exact decimals, currencies are ISO 4217 alphabetic codes
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS budget_amount NUMERIC(20, 4) CHECK (budget_amount >= 0);
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS budget_currency CHAR(3);
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS budget_confidential BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS budget_amount NUMERIC(20, 4);
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS budget_currency CHAR(3);
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS budget_confidential BOOLEAN NOT NULL DEFAULT false;
+
+-- Bids created before prices were introduced have none
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS price_amount NUMERIC(20, 4) CHECK (price_amount >= 0);
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS price_currency CHAR(3);
+ALTER TABLE proposals_versions ADD COLUMN IF NOT EXISTS price_amount NUMERIC(20, 4);
+ALTER TABLE proposals_versions ADD COLUMN IF NOT EXISTS price_currency CHAR(3);
+
+CREATE INDEX IF NOT EXISTS tenders_budget_idx ON tenders (budget_currency, budget_amount);
+CREATE INDEX IF NOT EXISTS proposals_price_idx ON proposals (tender_id, price_currency, price_amount);
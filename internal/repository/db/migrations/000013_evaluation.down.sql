@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS proposal_scores_criterion_idx;
+DROP TABLE IF EXISTS proposal_scores;
+DROP TABLE IF EXISTS tender_criteria;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS tender_criteria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    weight INT NOT NULL CHECK (weight > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tender_id, name)
+);
+
+-- Scores are kept apart from approval votes, each evaluator scores each criterion of bid once
+CREATE TABLE IF NOT EXISTS proposal_scores (
+    proposal_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    criterion_id UUID NOT NULL REFERENCES tender_criteria(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    score INT NOT NULL CHECK (score BETWEEN 0 AND 10),
+    comment VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(proposal_id, criterion_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS proposal_scores_criterion_idx ON proposal_scores (criterion_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS proposal_approval_vote_idx;
+DELETE FROM proposal_approval WHERE lot_id IS NOT NULL;
+ALTER TABLE proposal_approval DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE proposal_approval ADD CONSTRAINT proposal_approval_proposal_id_user_id_key UNIQUE (proposal_id, user_id);
+
+DROP INDEX IF EXISTS proposals_lot_ids_idx;
+ALTER TABLE proposals_versions DROP COLUMN IF EXISTS lot_ids;
+ALTER TABLE proposals DROP COLUMN IF EXISTS lot_ids;
+
+DROP TABLE IF EXISTS tender_lots;
+DROP TYPE IF EXISTS lot_status;
@@ -0,0 +1,37 @@
+DO $$ BEGIN
+    CREATE TYPE lot_status AS ENUM (
+        'Open',
+        'Awarded',
+        'Cancelled'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS tender_lots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    quantity INT NOT NULL CHECK (quantity > 0),
+    budget_amount NUMERIC(20, 4) CHECK (budget_amount >= 0),
+    budget_currency CHAR(3),
+    status lot_status NOT NULL DEFAULT 'Open',
+    awarded_bid_id UUID REFERENCES proposals(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tender_id, name)
+);
+
+-- Lots bid is submitted for are versioned with the rest of bid, tenders without lots have bids with none
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS lot_ids UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE proposals_versions ADD COLUMN IF NOT EXISTS lot_ids UUID[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS proposals_lot_ids_idx ON proposals USING GIN (lot_ids);
+
+-- Bids of tenders with lots are decided on per lot, each employee votes once per bid and lot
+ALTER TABLE proposal_approval ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES tender_lots(id) ON DELETE CASCADE;
+ALTER TABLE proposal_approval DROP CONSTRAINT IF EXISTS proposal_approval_proposal_id_user_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS proposal_approval_vote_idx
+    ON proposal_approval (proposal_id, user_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'::uuid));
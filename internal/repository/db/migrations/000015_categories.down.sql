@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS tenders_service_type_idx;
+ALTER TABLE tenders DROP CONSTRAINT IF EXISTS tenders_service_type_fkey;
+
+DO $$ BEGIN
+    CREATE TYPE tender_service_type AS ENUM (
+        'Construction',
+        'Delivery',
+        'Manufacture'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Categories added since can not be represented by predefined service types
+UPDATE tenders SET service_type = 'Construction' WHERE service_type NOT IN ('Construction', 'Delivery', 'Manufacture');
+UPDATE tenders_versions SET service_type = 'Construction' WHERE service_type NOT IN ('Construction', 'Delivery', 'Manufacture');
+ALTER TABLE tenders ALTER COLUMN service_type TYPE tender_service_type USING service_type::tender_service_type;
+ALTER TABLE tenders_versions ALTER COLUMN service_type TYPE tender_service_type USING service_type::tender_service_type;
+
+DROP INDEX IF EXISTS service_categories_parent_idx;
+DROP TABLE IF EXISTS service_categories;
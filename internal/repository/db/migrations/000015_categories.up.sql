@@ -0,0 +1,27 @@
+-- Service types are nodes of category taxonomy managed at runtime, codes of predefined ones are kept
+CREATE TABLE IF NOT EXISTS service_categories (
+    code VARCHAR(50) PRIMARY KEY,
+    parent_code VARCHAR(50) REFERENCES service_categories(code),
+    names JSONB NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent_code <> code)
+);
+
+CREATE INDEX IF NOT EXISTS service_categories_parent_idx ON service_categories (parent_code);
+
+INSERT INTO service_categories (code, names)
+VALUES
+    ('Construction', '{"en": "Construction", "ru": "Строительство"}'),
+    ('Delivery', '{"en": "Delivery", "ru": "Доставка"}'),
+    ('Manufacture', '{"en": "Manufacture", "ru": "Производство"}')
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE tenders ALTER COLUMN service_type TYPE VARCHAR(50) USING service_type::text;
+ALTER TABLE tenders_versions ALTER COLUMN service_type TYPE VARCHAR(50) USING service_type::text;
+ALTER TABLE tenders DROP CONSTRAINT IF EXISTS tenders_service_type_fkey;
+ALTER TABLE tenders ADD CONSTRAINT tenders_service_type_fkey FOREIGN KEY (service_type) REFERENCES service_categories(code);
+DROP TYPE IF EXISTS tender_service_type;
+
+CREATE INDEX IF NOT EXISTS tenders_service_type_idx ON tenders (service_type);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tender_questions_tender_idx;
+DROP TABLE IF EXISTS tender_questions;
+DROP TYPE IF EXISTS question_status;
@@ -0,0 +1,25 @@
+DO $$ BEGIN
+    CREATE TYPE question_status AS ENUM (
+        'Open',
+        'Answered',
+        'Closed'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Author of question is only kept to show askers their own questions, it is never disclosed
+CREATE TABLE IF NOT EXISTS tender_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    author_user_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    question VARCHAR(1000) NOT NULL,
+    answer VARCHAR(2000),
+    answered_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    answered_at TIMESTAMP,
+    status question_status NOT NULL DEFAULT 'Open',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS tender_questions_tender_idx ON tender_questions (tender_id, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE proposals_versions DROP COLUMN IF EXISTS reconfirmation_required;
+ALTER TABLE proposals DROP COLUMN IF EXISTS reconfirmation_required;
+ALTER TABLE proposals_versions DROP COLUMN IF EXISTS tender_version;
+ALTER TABLE proposals DROP COLUMN IF EXISTS tender_version;
+DROP TABLE IF EXISTS tender_amendments;
@@ -0,0 +1,19 @@
+-- Material changes of published tenders, version is the one tender is amended into
+CREATE TABLE IF NOT EXISTS tender_amendments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    author_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tender_id, version)
+);
+
+-- Version of tender bid is submitted against, existing bids are considered submitted against current one
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS tender_version INT NOT NULL DEFAULT 1;
+ALTER TABLE proposals_versions ADD COLUMN IF NOT EXISTS tender_version INT NOT NULL DEFAULT 1;
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS reconfirmation_required BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE proposals_versions ADD COLUMN IF NOT EXISTS reconfirmation_required BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE proposals SET tender_version = tenders.version FROM tenders WHERE tenders.id = proposals.tender_id;
+UPDATE proposals_versions SET tender_version = tenders.version FROM tenders WHERE tenders.id = proposals_versions.tender_id;
@@ -0,0 +1,23 @@
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS awarded_bid_ids;
+ALTER TABLE tenders DROP COLUMN IF EXISTS awarded_bid_ids;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS cancellation_reason;
+ALTER TABLE tenders DROP COLUMN IF EXISTS cancellation_reason;
+
+DROP INDEX IF EXISTS tenders_submission_deadline_idx;
+DROP INDEX IF EXISTS tenders_publish_at_idx;
+
+-- Both outcomes are represented by Closed status
+ALTER TYPE tender_status RENAME TO tender_status_new;
+CREATE TYPE tender_status AS ENUM (
+    'Created',
+    'Published',
+    'Closed'
+);
+ALTER TABLE tenders ALTER COLUMN status TYPE tender_status
+    USING (CASE WHEN status IN ('Cancelled', 'Awarded') THEN 'Closed' ELSE status::text END)::tender_status;
+ALTER TABLE tenders_versions ALTER COLUMN status TYPE tender_status
+    USING (CASE WHEN status IN ('Cancelled', 'Awarded') THEN 'Closed' ELSE status::text END)::tender_status;
+DROP TYPE tender_status_new;
+
+CREATE INDEX IF NOT EXISTS tenders_submission_deadline_idx ON tenders (submission_deadline) WHERE status = 'Published';
+CREATE INDEX IF NOT EXISTS tenders_publish_at_idx ON tenders (publish_at) WHERE status = 'Created';
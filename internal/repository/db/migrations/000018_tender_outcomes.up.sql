@@ -0,0 +1,39 @@
+-- Enum is recreated, since values added with ALTER TYPE can not be used by data migration in the same transaction
+DROP INDEX IF EXISTS tenders_submission_deadline_idx;
+DROP INDEX IF EXISTS tenders_publish_at_idx;
+
+ALTER TYPE tender_status RENAME TO tender_status_old;
+CREATE TYPE tender_status AS ENUM (
+    'Created',
+    'Published',
+    'Closed',
+    'Cancelled',
+    'Awarded'
+);
+ALTER TABLE tenders ALTER COLUMN status TYPE tender_status USING status::text::tender_status;
+ALTER TABLE tenders_versions ALTER COLUMN status TYPE tender_status USING status::text::tender_status;
+DROP TYPE tender_status_old;
+
+CREATE INDEX IF NOT EXISTS tenders_submission_deadline_idx ON tenders (submission_deadline) WHERE status = 'Published';
+CREATE INDEX IF NOT EXISTS tenders_publish_at_idx ON tenders (publish_at) WHERE status = 'Created';
+
+-- Outcome of tender: reason it is cancelled for or bids it is awarded to
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS cancellation_reason VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS cancellation_reason VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS awarded_bid_ids UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS awarded_bid_ids UUID[] NOT NULL DEFAULT '{}';
+
+-- Closed tenders with approved bids are awarded to them, ones with all lots cancelled are cancelled
+UPDATE tenders SET status = 'Awarded', awarded_bid_ids = awarded.ids
+FROM (
+    SELECT tender_id, array_agg(id ORDER BY created_at) AS ids
+    FROM proposals
+    WHERE status = 'Approved'
+    GROUP BY tender_id
+) awarded
+WHERE tenders.id = awarded.tender_id AND tenders.status = 'Closed';
+
+UPDATE tenders SET status = 'Cancelled', cancellation_reason = 'All lots are cancelled'
+WHERE status = 'Closed'
+    AND EXISTS (SELECT 1 FROM tender_lots WHERE tender_id = tenders.id)
+    AND NOT EXISTS (SELECT 1 FROM tender_lots WHERE tender_id = tenders.id AND status <> 'Cancelled');
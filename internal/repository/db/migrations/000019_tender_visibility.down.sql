@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tender_invited_organizations_organization_idx;
+DROP TABLE IF EXISTS tender_invited_organizations;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS visibility;
+ALTER TABLE tenders DROP COLUMN IF EXISTS visibility;
+DROP TYPE IF EXISTS tender_visibility;
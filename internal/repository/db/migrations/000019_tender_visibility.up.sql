@@ -0,0 +1,23 @@
+DO $$ BEGIN
+    CREATE TYPE tender_visibility AS ENUM (
+        'Public',
+        'InviteOnly'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Existing tenders stay public
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS visibility tender_visibility NOT NULL DEFAULT 'Public';
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS visibility tender_visibility NOT NULL DEFAULT 'Public';
+
+-- Organizations invite-only tender is visible to besides owning one
+CREATE TABLE IF NOT EXISTS tender_invited_organizations (
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    invited_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tender_id, organization_id)
+);
+
+CREATE INDEX IF NOT EXISTS tender_invited_organizations_organization_idx ON tender_invited_organizations (organization_id);
@@ -0,0 +1,5 @@
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS source_template_id;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS source_tender_id;
+ALTER TABLE tenders DROP COLUMN IF EXISTS source_template_id;
+ALTER TABLE tenders DROP COLUMN IF EXISTS source_tender_id;
+DROP TABLE IF EXISTS tender_templates;
@@ -0,0 +1,25 @@
+-- Reusable drafts of tenders, lots and criteria are stored along with the rest of template
+CREATE TABLE IF NOT EXISTS tender_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    source_tender_id UUID REFERENCES tenders(id) ON DELETE SET NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    service_type VARCHAR(50) NOT NULL REFERENCES service_categories(code),
+    budget_amount NUMERIC(20, 4) CHECK (budget_amount >= 0),
+    budget_currency CHAR(3),
+    budget_confidential BOOLEAN NOT NULL DEFAULT false,
+    visibility tender_visibility NOT NULL DEFAULT 'Public',
+    lots JSONB NOT NULL DEFAULT '[]',
+    criteria JSONB NOT NULL DEFAULT '[]',
+    author_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(organization_id, name)
+);
+
+-- Tender or template tender is cloned from, versions keep them as they are not changed afterwards
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS source_tender_id UUID REFERENCES tenders(id) ON DELETE SET NULL;
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS source_template_id UUID REFERENCES tender_templates(id) ON DELETE SET NULL;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS source_tender_id UUID;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS source_template_id UUID;
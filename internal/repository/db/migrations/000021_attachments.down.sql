@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS attachments_bid_idx;
+DROP INDEX IF EXISTS attachments_tender_idx;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,17 @@
+-- Attachment belongs either to tender or to bid, its content is kept by attachment storage under storage_key
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tender_id UUID REFERENCES tenders(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES proposals(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    storage_key VARCHAR(500) NOT NULL UNIQUE,
+    author_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((tender_id IS NULL) <> (bid_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS attachments_tender_idx ON attachments (tender_id, created_at) WHERE tender_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS attachments_bid_idx ON attachments (bid_id, created_at) WHERE bid_id IS NOT NULL;
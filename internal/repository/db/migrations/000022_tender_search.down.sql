@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS tenders_search_idx;
+ALTER TABLE tenders DROP COLUMN IF EXISTS search_vector;
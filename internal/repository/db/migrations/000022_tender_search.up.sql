@@ -0,0 +1,8 @@
+-- Language-neutral configuration, tenders are named and described in different languages.
+-- Generated column is computed for existing rows as it is added
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS tenders_search_idx ON tenders USING GIN (search_vector);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS tender_keys;
+ALTER TABLE proposals_versions DROP COLUMN IF EXISTS sealed_content;
+ALTER TABLE proposals DROP COLUMN IF EXISTS sealed_content;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS opened_by;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS opened_at;
+ALTER TABLE tenders_versions DROP COLUMN IF EXISTS sealed;
+ALTER TABLE tenders DROP COLUMN IF EXISTS opened_by;
+ALTER TABLE tenders DROP COLUMN IF EXISTS opened_at;
+ALTER TABLE tenders DROP COLUMN IF EXISTS sealed;
@@ -0,0 +1,17 @@
+-- Bids of sealed tender keep name, description and price encrypted in sealed_content until they are opened
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS opened_at TIMESTAMP;
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS opened_by UUID REFERENCES employee(id) ON DELETE SET NULL;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS opened_at TIMESTAMP;
+ALTER TABLE tenders_versions ADD COLUMN IF NOT EXISTS opened_by UUID;
+
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS sealed_content BYTEA;
+ALTER TABLE proposals_versions ADD COLUMN IF NOT EXISTS sealed_content BYTEA;
+
+-- Key of sealed tender, wrapped by master key of service
+CREATE TABLE IF NOT EXISTS tender_keys (
+    tender_id UUID PRIMARY KEY REFERENCES tenders(id) ON DELETE CASCADE,
+    wrapped_key BYTEA NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS auction_prices_bid_idx;
+DROP TABLE IF EXISTS auction_prices;
+DROP INDEX IF EXISTS tender_auctions_status_idx;
+DROP TABLE IF EXISTS tender_auctions;
+DROP TYPE IF EXISTS auction_status;
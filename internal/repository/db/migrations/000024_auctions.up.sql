@@ -0,0 +1,42 @@
+DO $$ BEGIN
+    CREATE TYPE auction_status AS ENUM (
+        'Scheduled',
+        'Running',
+        'Closed'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Reverse auction held among bids of tender, end_at is pushed back by anti-sniping extensions
+CREATE TABLE IF NOT EXISTS tender_auctions (
+    tender_id UUID PRIMARY KEY REFERENCES tenders(id) ON DELETE CASCADE,
+    status auction_status NOT NULL DEFAULT 'Scheduled',
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    extension_minutes INT NOT NULL DEFAULT 0 CHECK (extension_minutes >= 0),
+    extensions INT NOT NULL DEFAULT 0,
+    min_decrement_amount NUMERIC(20, 4) NOT NULL CHECK (min_decrement_amount > 0),
+    currency CHAR(3) NOT NULL,
+    created_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS tender_auctions_status_idx ON tender_auctions (status, end_at);
+
+-- Ledger of prices submitted during auction, latest entry of bid is its current price
+CREATE TABLE IF NOT EXISTS auction_prices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tender_id UUID NOT NULL REFERENCES tender_auctions(tender_id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    amount NUMERIC(20, 4) NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL,
+    previous_amount NUMERIC(20, 4),
+    user_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS auction_prices_bid_idx ON auction_prices (tender_id, bid_id, created_at);
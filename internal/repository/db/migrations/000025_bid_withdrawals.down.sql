@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bid_events_tender_idx;
+DROP TABLE IF EXISTS bid_events;
+DROP TYPE IF EXISTS bid_event_type;
@@ -0,0 +1,22 @@
+DO $$ BEGIN
+    CREATE TYPE bid_event_type AS ENUM (
+        'Withdrawn',
+        'Resubmitted'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+-- Withdrawals and resubmissions of bids along with reasons given by their authors
+CREATE TABLE IF NOT EXISTS bid_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bid_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    tender_id UUID NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    type bid_event_type NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    previous_status proposal_status NOT NULL,
+    user_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bid_events_tender_idx ON bid_events (tender_id, created_at);
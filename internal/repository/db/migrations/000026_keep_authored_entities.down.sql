@@ -0,0 +1,9 @@
+ALTER TABLE proposals_versions DROP CONSTRAINT IF EXISTS proposals_versions_author_user_id_fkey;
+ALTER TABLE proposals_versions ADD CONSTRAINT proposals_versions_author_user_id_fkey FOREIGN KEY (author_user_id) REFERENCES employee(id) ON DELETE CASCADE;
+ALTER TABLE proposals DROP CONSTRAINT IF EXISTS proposals_author_user_id_fkey;
+ALTER TABLE proposals ADD CONSTRAINT proposals_author_user_id_fkey FOREIGN KEY (author_user_id) REFERENCES employee(id) ON DELETE CASCADE;
+
+ALTER TABLE tenders_versions DROP CONSTRAINT IF EXISTS tenders_versions_author_id_fkey;
+ALTER TABLE tenders_versions ADD CONSTRAINT tenders_versions_author_id_fkey FOREIGN KEY (author_id) REFERENCES employee(id) ON DELETE CASCADE;
+ALTER TABLE tenders DROP CONSTRAINT IF EXISTS tenders_author_id_fkey;
+ALTER TABLE tenders ADD CONSTRAINT tenders_author_id_fkey FOREIGN KEY (author_id) REFERENCES employee(id) ON DELETE CASCADE;
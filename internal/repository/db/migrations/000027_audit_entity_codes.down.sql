@@ -0,0 +1,3 @@
+-- Entries of entities identified by codes lose their ids
+ALTER TABLE audit_log ALTER COLUMN entity_id TYPE UUID
+    USING CASE WHEN entity_type = 'category' THEN NULL ELSE entity_id::uuid END;
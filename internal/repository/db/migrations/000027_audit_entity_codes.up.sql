@@ -0,0 +1,2 @@
+-- Entities are identified by UUID, except for categories identified by their codes
+ALTER TABLE audit_log ALTER COLUMN entity_id TYPE VARCHAR(50) USING entity_id::text;
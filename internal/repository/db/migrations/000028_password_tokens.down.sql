@@ -0,0 +1,2 @@
+ALTER TABLE employee DROP COLUMN IF EXISTS password_token_expires_at;
+ALTER TABLE employee DROP COLUMN IF EXISTS password_token_hash;
@@ -0,0 +1,3 @@
+-- One-time token lets employee set password without logging in, only its hash is stored
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS password_token_hash VARCHAR(64) UNIQUE;
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS password_token_expires_at TIMESTAMP;
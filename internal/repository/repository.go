@@ -1,229 +1,477 @@
-package repository
-
-import (
-	"context"
-	"database/sql"
-	"errors"
-	"fmt"
-	"strings"
-
-	"tenders/internal/config"
-	"tenders/internal/models"
-
-	postgres "tenders/internal/repository/db"
-)
-
-type Repository struct {
-	db  *sql.DB
-	cfg *config.PostgresConfig
-}
-
-func NewRepository(db *sql.DB, cfg *config.PostgresConfig) (*Repository, error) {
-	var err error
-
-	repo := &Repository{
-		db:  db,
-		cfg: cfg,
-	}
-
-	if repo.cfg == nil {
-		repo.cfg, err = config.NewPostgresConfig()
-		if err != nil {
-			return nil, fmt.Errorf("repository.NewRepository: could not load postgres config: %w", err)
-		}
-	}
-
-	if repo.db == nil {
-		repo.db, err = postgres.NewPostgresDB(repo.cfg)
-		if err != nil {
-			return nil, fmt.Errorf("repository.NewRepository: could not open postgres db: %w", err)
-		}
-	}
-
-	if repo.cfg.AutoMigrateUp == "true" {
-		err = repo.MigrateUp()
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return repo, nil
-}
-
-func (repo *Repository) MigrateUp() error {
-	err := postgres.MigrateUp(repo.db, repo.cfg.MigrationsURL)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.Migrate: %w", err)
-	}
-	return nil
-}
-
-func (repo *Repository) MigrateDown() error {
-	err := postgres.MigrateDown(repo.db, repo.cfg.MigrationsURL)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.Migrate: %w", err)
-	}
-	return nil
-}
-
-func (repo *Repository) UserByUsername(ctx context.Context, username string) (models.User, bool, error) {
-	var user models.User
-	query := `
-	SELECT
-		id,
-		username,
-		first_name,
-		last_name,
-		created_at,
-		updated_at
-	FROM employee
-	WHERE username = $1
-	LIMIT 1
-	`
-	row := repo.db.QueryRowContext(ctx, query, username)
-	err := row.Scan(&user.Id, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return user, false, nil
-	} else if err != nil {
-		return user, false, fmt.Errorf("repository.Repository.UserByUsername: %w", err)
-	}
-
-	return user, true, nil
-}
-
-func (repo *Repository) UserByUUID(ctx context.Context, UUID string) (models.User, bool, error) {
-	var user models.User
-	query := `
-	SELECT
-		id,
-		username,
-		first_name,
-		last_name,
-		created_at,
-		updated_at
-	FROM employee
-	WHERE id = $1
-	LIMIT 1
-	`
-	row := repo.db.QueryRowContext(ctx, query, UUID)
-	err := row.Scan(&user.Id, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return user, false, nil
-	} else if err != nil {
-		return user, false, fmt.Errorf("repository.Repository.UserByUsername: %w", err)
-	}
-
-	return user, true, nil
-}
-
-func (repo *Repository) UsersAreColleagues(ctx context.Context, userId1, userId2 string) (bool, error) {
-	query := `
-	SELECT
-		organization_id,
-		COUNT(DISTINCT user_id)
-	FROM organization_responsible
-	WHERE user_id IN ($1, $2)
-	GROUP BY organization_id
-	`
-
-	rows, err := repo.db.QueryContext(ctx, query, userId1, userId2)
-	if err != nil {
-		return false, fmt.Errorf("repository.Repository.UsersAreColleagues: %w", err)
-	}
-	defer rows.Close()
-
-	ok := false
-	var org string
-	var count int
-	for rows.Next() {
-		err = rows.Scan(&org, &count)
-		if err != nil {
-			return false, fmt.Errorf("repository.Repository.UsersAreColleagues: rows scan error: %w", err)
-		}
-		if count >= 2 {
-			ok = true
-		}
-	}
-
-	if rows.Err() != nil {
-		return false, fmt.Errorf("repository.Repository.UsersAreColleagues: %w", rows.Err())
-	}
-
-	return ok, nil
-}
-
-func (repo *Repository) UserValid(ctx context.Context, userId, organizationId string) (bool, error) {
-	// query organization_responsible for userId, orgId pair
-	row := repo.db.QueryRowContext(ctx, "SELECT id FROM organization_responsible WHERE organization_id = $1 AND user_id = $2", organizationId, userId)
-	var dummy string
-	err := row.Scan(&dummy)
-
-	switch {
-	case err == nil:
-		return true, nil
-	case errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil:
-		return false, nil
-	}
-	return false, fmt.Errorf("repository.Repository.UserValid: %w", err)
-}
-
-func (repo *Repository) UserOrganizationId(ctx context.Context, userId string) (organizationId string, err error) {
-	query := `
-	SELECT
-		organization_id
-	FROM organization_responsible
-	WHERE user_id = $1
-	LIMIT 1
-	`
-
-	row := repo.db.QueryRowContext(ctx, query, userId)
-	err = row.Scan(&organizationId)
-	return
-}
-
-func (repo *Repository) OrganizationByUUID(ctx context.Context, organizationId string) (org models.Organization, err error) {
-	query := `
-	SELECT
-		id, name, description, type, created_at, updated_at
-	FROM organization
-	WHERE id = $1
-	`
-
-	row := repo.db.QueryRowContext(ctx, query, organizationId)
-	err = row.Scan(&org.Id, &org.Name, &org.Description, &org.Type, &org.CreatedAt, &org.UpdatedAt)
-	return
-}
-
-func (repo *Repository) Close() error {
-	var migErr error
-	if repo.cfg.AutoMigrateDown == "true" {
-		migErr = repo.MigrateDown()
-	}
-
-	err := repo.db.Close()
-	return errors.Join(migErr, err)
-}
-
-//// Service
-
-func wrapRollbackErr(tx *sql.Tx, err error) error {
-	rollerr := tx.Rollback()
-	if rollerr == nil {
-		return err
-	}
-	return fmt.Errorf("failed to rollback transaction after previous error: %w, %w", rollerr, err)
-}
-
-func sliceToSQLList[T string | models.ServiceType](t []T) string {
-	parts := make([]string, 0, len(t))
-	for _, v := range t {
-		parts = append(parts, string(v))
-	}
-	return "{" + strings.Join(parts, ", ") + "}"
-}
-
-//// Test utils
-
-func (repo *Repository) TestGetDB() *sql.DB {
-	return repo.db
-}
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tenders/internal/config"
+	"tenders/internal/models"
+
+	postgres "tenders/internal/repository/db"
+
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	db  *sql.DB
+	cfg *config.PostgresConfig
+}
+
+func NewRepository(db *sql.DB, cfg *config.PostgresConfig) (*Repository, error) {
+	var err error
+
+	repo := &Repository{
+		db:  db,
+		cfg: cfg,
+	}
+
+	if repo.cfg == nil {
+		repo.cfg, err = config.NewPostgresConfig()
+		if err != nil {
+			return nil, fmt.Errorf("repository.NewRepository: could not load postgres config: %w", err)
+		}
+	}
+
+	if repo.db == nil {
+		repo.db, err = postgres.NewPostgresDB(repo.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("repository.NewRepository: could not open postgres db: %w", err)
+		}
+	}
+
+	if repo.cfg.AutoMigrateUp == "true" {
+		err = repo.MigrateUp()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+func (repo *Repository) MigrateUp() error {
+	err := postgres.MigrateUp(repo.db, repo.cfg.MigrationsURL)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.Migrate: %w", err)
+	}
+	return nil
+}
+
+func (repo *Repository) MigrateDown() error {
+	err := postgres.MigrateDown(repo.db, repo.cfg.MigrationsURL)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.Migrate: %w", err)
+	}
+	return nil
+}
+
+func (repo *Repository) UserByUsername(ctx context.Context, username string) (models.User, bool, error) {
+	var user models.User
+	query := `
+	SELECT
+		id,
+		username,
+		COALESCE(first_name, ''),
+		COALESCE(last_name, ''),
+		created_at,
+		updated_at
+	FROM employee
+	WHERE username = $1
+	LIMIT 1
+	`
+	row := repo.conn(ctx).QueryRowContext(ctx, query, username)
+	err := row.Scan(&user.Id, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return user, false, nil
+	} else if err != nil {
+		return user, false, fmt.Errorf("repository.Repository.UserByUsername: %w", err)
+	}
+
+	return user, true, nil
+}
+
+func (repo *Repository) UserByUUID(ctx context.Context, UUID string) (models.User, bool, error) {
+	var user models.User
+	query := `
+	SELECT
+		id,
+		username,
+		COALESCE(first_name, ''),
+		COALESCE(last_name, ''),
+		created_at,
+		updated_at
+	FROM employee
+	WHERE id = $1
+	LIMIT 1
+	`
+	row := repo.conn(ctx).QueryRowContext(ctx, query, UUID)
+	err := row.Scan(&user.Id, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return user, false, nil
+	} else if err != nil {
+		return user, false, fmt.Errorf("repository.Repository.UserByUsername: %w", err)
+	}
+
+	return user, true, nil
+}
+
+func (repo *Repository) UserPasswordHash(ctx context.Context, userId string) (string, error) {
+	var hash sql.NullString
+	row := repo.conn(ctx).QueryRowContext(ctx, "SELECT password_hash FROM employee WHERE id = $1", userId)
+	err := row.Scan(&hash)
+	if err != nil {
+		return "", fmt.Errorf("repository.Repository.UserPasswordHash: %w", err)
+	}
+	return hash.String, nil
+}
+
+// SetUserPasswordHash changes password of user, one-time password token issued to user is spent along the way
+func (repo *Repository) SetUserPasswordHash(ctx context.Context, userId, hash string) error {
+	query := `
+	UPDATE employee
+	SET (password_hash, password_token_hash, password_token_expires_at, updated_at) = ($1, NULL, NULL, CURRENT_TIMESTAMP)
+	WHERE id = $2
+	`
+	_, err := repo.conn(ctx).ExecContext(ctx, query, hash, userId)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.SetUserPasswordHash: %w", err)
+	}
+	return nil
+}
+
+// SetUserPasswordToken stores hash of one-time password token of user valid for ttl, replacing previously issued one.
+// Expiry time of token is returned
+func (repo *Repository) SetUserPasswordToken(ctx context.Context, userId, tokenHash string, ttl time.Duration) (time.Time, error) {
+	query := `
+	UPDATE employee
+	SET (password_token_hash, password_token_expires_at) = ($1, CURRENT_TIMESTAMP + $2::float8 * INTERVAL '1 second')
+	WHERE id = $3
+	RETURNING password_token_expires_at
+	`
+	var expiresAt time.Time
+	err := repo.conn(ctx).QueryRowContext(ctx, query, tokenHash, int64(ttl.Seconds()), userId).Scan(&expiresAt)
+	if err != nil {
+		return expiresAt, fmt.Errorf("repository.Repository.SetUserPasswordToken: %w", err)
+	}
+	return expiresAt, nil
+}
+
+// SetUserPasswordHashByToken changes password of user, unexpired one-time password token of which has hash tokenHash,
+// token is spent by it. Id of user is returned, empty if there is no such token
+func (repo *Repository) SetUserPasswordHashByToken(ctx context.Context, tokenHash, hash string) (string, error) {
+	query := `
+	UPDATE employee
+	SET (password_hash, password_token_hash, password_token_expires_at, updated_at) = ($1, NULL, NULL, CURRENT_TIMESTAMP)
+	WHERE password_token_hash = $2 AND password_token_expires_at >= CURRENT_TIMESTAMP
+	RETURNING id
+	`
+	var userId string
+	err := repo.conn(ctx).QueryRowContext(ctx, query, hash, tokenHash).Scan(&userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("repository.Repository.SetUserPasswordHashByToken: %w", err)
+	}
+	return userId, nil
+}
+
+func (repo *Repository) UsersAreColleagues(ctx context.Context, userId1, userId2 string) (bool, error) {
+	query := `
+	SELECT
+		organization_id,
+		COUNT(DISTINCT user_id)
+	FROM organization_responsible
+	WHERE user_id IN ($1, $2)
+	GROUP BY organization_id
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, userId1, userId2)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.UsersAreColleagues: %w", err)
+	}
+	defer rows.Close()
+
+	ok := false
+	var org string
+	var count int
+	for rows.Next() {
+		err = rows.Scan(&org, &count)
+		if err != nil {
+			return false, fmt.Errorf("repository.Repository.UsersAreColleagues: rows scan error: %w", err)
+		}
+		if count >= 2 {
+			ok = true
+		}
+	}
+
+	if rows.Err() != nil {
+		return false, fmt.Errorf("repository.Repository.UsersAreColleagues: %w", rows.Err())
+	}
+
+	return ok, nil
+}
+
+func (repo *Repository) UserValid(ctx context.Context, userId, organizationId string) (bool, error) {
+	// query organization_responsible for userId, orgId pair
+	row := repo.conn(ctx).QueryRowContext(ctx, "SELECT id FROM organization_responsible WHERE organization_id = $1 AND user_id = $2", organizationId, userId)
+	var dummy string
+	err := row.Scan(&dummy)
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("repository.Repository.UserValid: %w", err)
+}
+
+// UserRole returns role of user in organization, false is returned if user is not a member
+func (repo *Repository) UserRole(ctx context.Context, userId, organizationId string) (models.Role, bool, error) {
+	var role models.Role
+	row := repo.conn(ctx).QueryRowContext(ctx, "SELECT role FROM organization_responsible WHERE organization_id = $1 AND user_id = $2 LIMIT 1", organizationId, userId)
+	err := row.Scan(&role)
+
+	switch {
+	case err == nil:
+		return role, true, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return "", false, nil
+	}
+	return "", false, fmt.Errorf("repository.Repository.UserRole: %w", err)
+}
+
+func (repo *Repository) UserOrganizationId(ctx context.Context, userId string) (organizationId string, err error) {
+	query := `
+	SELECT
+		organization_id
+	FROM organization_responsible
+	WHERE user_id = $1
+	LIMIT 1
+	`
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, userId)
+	err = row.Scan(&organizationId)
+	return
+}
+
+// UserOrganizationIds returns ids of all organizations user is member of
+func (repo *Repository) UserOrganizationIds(ctx context.Context, userId string) ([]string, error) {
+	query := `
+	SELECT
+		organization_id
+	FROM organization_responsible
+	WHERE user_id = $1
+	ORDER BY organization_id
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.UserOrganizationIds: %w", err)
+	}
+	defer rows.Close()
+
+	result := []string{}
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.UserOrganizationIds: rows scan failed: %w", err)
+		}
+		result = append(result, id)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.UserOrganizationIds: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) OrganizationByUUID(ctx context.Context, organizationId string) (org models.Organization, err error) {
+	query := `
+	SELECT
+		id, name, COALESCE(description, ''), COALESCE(type::text, ''), created_at, updated_at
+	FROM organization
+	WHERE id = $1
+	`
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, organizationId)
+	err = row.Scan(&org.Id, &org.Name, &org.Description, &org.Type, &org.CreatedAt, &org.UpdatedAt)
+	return
+}
+
+func (repo *Repository) Close() error {
+	var migErr error
+	if repo.cfg.AutoMigrateDown == "true" {
+		migErr = repo.MigrateDown()
+	}
+
+	err := repo.db.Close()
+	return errors.Join(migErr, err)
+}
+
+//// Transactions
+
+type txKey struct{}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// RunInTx runs fn in single transaction: repository methods called with context passed to fn
+// join it. Transaction is rolled back if fn returns error. Nested calls join outer transaction
+func (repo *Repository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.RunInTx: failed to start transaction: %w", err)
+	}
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		return wrapRollbackErr(tx, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository.Repository.RunInTx: failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// conn returns transaction started by RunInTx, or database if there is none
+func (repo *Repository) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return repo.db
+}
+
+//// Service
+
+func wrapRollbackErr(tx *sql.Tx, err error) error {
+	rollerr := tx.Rollback()
+	if rollerr == nil {
+		return err
+	}
+	return fmt.Errorf("failed to rollback transaction after previous error: %w, %w", rollerr, err)
+}
+
+// nullableTime converts nil time into NULL, times are stored in UTC
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// nullableUUID converts empty id into NULL
+func nullableUUID(id string) interface{} {
+	if len(id) == 0 {
+		return nil
+	}
+	return id
+}
+
+// limitParam converts non-positive limit into NULL, which means no limit
+func limitParam(limit int) interface{} {
+	if limit <= 0 {
+		return nil
+	}
+	return limit
+}
+
+// checkVersionUpdated reports models.ErrVersionConflict if compare-and-set update matched no rows,
+// which means row is changed since it was read
+func checkVersionUpdated(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrVersionConflict
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is caused by unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// moneyParams converts nil money into pair of NULLs
+func moneyParams(m *models.Money) (amount, currency interface{}) {
+	if m == nil {
+		return nil, nil
+	}
+	return string(m.Amount), m.Currency
+}
+
+// readMoney converts scanned amount and currency into money, nil is returned if amount is NULL
+func readMoney(amount, currency sql.NullString) (*models.Money, error) {
+	if !amount.Valid {
+		return nil, nil
+	}
+	decimal, err := models.ParseDecimal(amount.String)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Money{Amount: decimal, Currency: currency.String}, nil
+}
+
+// orderClause builds ORDER BY list from sort fields, columns maps allowed fields to SQL expressions.
+// Unknown fields are skipped, rows are finally ordered by name and id, so pagination is stable
+func orderClause(sort []models.SortField, columns map[string]string) string {
+	parts := make([]string, 0, len(sort)+2)
+	for _, field := range sort {
+		column, ok := columns[field.Field]
+		if !ok {
+			continue
+		}
+		if field.Desc {
+			parts = append(parts, column+" DESC NULLS LAST")
+		} else {
+			parts = append(parts, column+" NULLS LAST")
+		}
+	}
+	return strings.Join(append(parts, "name", "id"), ", ")
+}
+
+// appendTimeRange adds conditions matching column against time range to query conditions and their parameters
+func appendTimeRange(conditions []string, params []interface{}, column string, r models.TimeRange) ([]string, []interface{}) {
+	if r.From != nil {
+		conditions = append(conditions, column+" >= $$")
+		params = append(params, *r.From)
+	}
+	if r.To != nil {
+		conditions = append(conditions, column+" < $$")
+		params = append(params, *r.To)
+	}
+	return conditions, params
+}
+
+func sliceToSQLList[T string | models.ServiceType | models.Role | models.TenderStatus | models.BidStatus](t []T) string {
+	parts := make([]string, 0, len(t))
+	for _, v := range t {
+		parts = append(parts, string(v))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+//// Test utils
+
+func (repo *Repository) TestGetDB() *sql.DB {
+	return repo.db
+}
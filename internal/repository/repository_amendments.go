@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const amendmentColumns = `
+		id,
+		tender_id,
+		version,
+		reason,
+		author_id,
+		created_at`
+
+func (repo *Repository) AddAmendment(ctx context.Context, amendment models.Amendment) (models.Amendment, error) {
+	query := `
+	INSERT INTO tender_amendments (tender_id, version, reason, author_id)
+	VALUES
+		($1, $2, $3, $4)
+	RETURNING` + amendmentColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, amendment.TenderId, amendment.Version, amendment.Reason, nullableUUID(amendment.AuthorId))
+	amendment, err := scanAmendment(row)
+	if err != nil {
+		return amendment, fmt.Errorf("repository.Repository.AddAmendment: %w", err)
+	}
+	return amendment, nil
+}
+
+// GetTenderAmendments lists amendments of tender, latest first
+func (repo *Repository) GetTenderAmendments(ctx context.Context, tenderId string, limit, offset int) ([]models.Amendment, error) {
+	query := `
+	SELECT` + amendmentColumns + `
+	FROM tender_amendments
+	WHERE tender_id = $3
+	ORDER BY version DESC
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderAmendments: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Amendment{}
+	for rows.Next() {
+		amendment, err := scanAmendment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenderAmendments: rows scan failed: %w", err)
+		}
+		result = append(result, amendment)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderAmendments: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// LatestAmendmentVersion returns version of tender introduced by its latest amendment, 0 if it is never amended
+func (repo *Repository) LatestAmendmentVersion(ctx context.Context, tenderId string) (int, error) {
+	var version int
+	err := repo.conn(ctx).QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM tender_amendments WHERE tender_id = $1", tenderId).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("repository.Repository.LatestAmendmentVersion: %w", err)
+	}
+	return version, nil
+}
+
+// TenderHasActiveBids reports whether tender has bids which are neither canceled nor decided on
+func (repo *Repository) TenderHasActiveBids(ctx context.Context, tenderId string) (bool, error) {
+	var exists bool
+	err := repo.conn(ctx).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM proposals WHERE tender_id = $1 AND status IN ('Created', 'Published'))", tenderId).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.TenderHasActiveBids: %w", err)
+	}
+	return exists, nil
+}
+
+// RequireBidsReconfirmation marks active bids of tender submitted against earlier versions than provided one
+// as requiring reconfirmation, bid versions are not changed
+func (repo *Repository) RequireBidsReconfirmation(ctx context.Context, tenderId string, version int) (int, error) {
+	query := `
+	UPDATE proposals
+	SET (reconfirmation_required, updated_at) = (true, CURRENT_TIMESTAMP)
+	WHERE tender_id = $1 AND tender_version < $2 AND status IN ('Created', 'Published')
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, tenderId, version)
+	if err != nil {
+		return 0, fmt.Errorf("repository.Repository.RequireBidsReconfirmation: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("repository.Repository.RequireBidsReconfirmation: %w", err)
+	}
+	return int(n), nil
+}
+
+//// Service
+
+func scanAmendment(row rowScanner) (models.Amendment, error) {
+	var amendment models.Amendment
+	var authorId interface{}
+
+	err := row.Scan(&amendment.Id, &amendment.TenderId, &amendment.Version, &amendment.Reason, &authorId, &amendment.CreatedAt)
+	if err != nil {
+		return amendment, err
+	}
+	amendment.AuthorId = readUUID(authorId)
+	return amendment, nil
+}
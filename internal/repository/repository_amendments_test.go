@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestAmendments(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	AddAllBids(t, ctx, repo, tenders, employees)
+	tender := tenders[0]
+
+	active, err := repo.TenderHasActiveBids(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active {
+		t.Fatal("Expected tender with new bids to have active ones")
+	}
+
+	latest, err := repo.LatestAmendmentVersion(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != 0 {
+		t.Errorf("Expected tender without amendments to have latest amendment version 0, got %d", latest)
+	}
+
+	bids, err := repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tender.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bid := range bids {
+		if bid.TenderVersion != 1 || bid.ReconfirmationRequired {
+			t.Fatalf("Expected bid submitted against first version of tender, got %v", bid)
+		}
+	}
+
+	// bids submitted against earlier versions are flagged, decided ones are left as is
+	bids[0].Status = models.BidApproved
+	err = repo.UpdateBid(ctx, bids[0], false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, version := range []int{2, 3} {
+		amendment, err := repo.AddAmendment(ctx, models.Amendment{TenderId: tender.Id, Version: version, Reason: "Scope changed"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if amendment.Version != version || amendment.Reason != "Scope changed" || len(amendment.AuthorId) > 0 {
+			t.Errorf("Unexpected amendment stored: %v", amendment)
+		}
+
+		flagged, err := repo.RequireBidsReconfirmation(ctx, tender.Id, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 && flagged != len(bids)-1 {
+			t.Errorf("Expected %d bids to require reconfirmation, got %d", len(bids)-1, flagged)
+		}
+	}
+
+	bids, err = repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tender.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bid := range bids {
+		if bid.ReconfirmationRequired == (bid.Status == models.BidApproved) {
+			t.Errorf("Expected only active bids to require reconfirmation, got %v", bid)
+		}
+	}
+
+	amendments, err := repo.GetTenderAmendments(ctx, tender.Id, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(amendments) != 1 || amendments[0].Version != 3 {
+		t.Errorf("Expected latest amendment first, got %v", amendments)
+	}
+	latest, err = repo.LatestAmendmentVersion(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != 3 {
+		t.Errorf("Expected latest amendment version 3, got %d", latest)
+	}
+}
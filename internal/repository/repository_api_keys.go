@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+
+	"github.com/lib/pq"
+)
+
+func (repo *Repository) AddAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	query := `
+	INSERT INTO organization_api_keys (organization_id, name, key_hash, scopes, expires_at)
+	VALUES
+		($1, $2, $3, $4, $5)
+	RETURNING
+		id, created_at
+	`
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, string(s))
+	}
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, key.OrganizationId, key.Name, key.Hash, pq.StringArray(scopes), key.ExpiresAt)
+	err := row.Scan(&key.Id, &key.CreatedAt)
+	if err != nil {
+		return key, fmt.Errorf("repository.Repository.AddAPIKey: %w", err)
+	}
+
+	return key, nil
+}
+
+func (repo *Repository) GetAPIKeys(ctx context.Context, organizationId string) ([]models.APIKey, error) {
+	query := `
+	SELECT
+		id, organization_id, name, key_hash, scopes, created_at, last_used_at, expires_at, revoked_at
+	FROM organization_api_keys
+	WHERE organization_id = $1
+	ORDER BY created_at DESC
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAPIKeys: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetAPIKeys: rows scan failed: %w", err)
+		}
+		result = append(result, key)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAPIKeys: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) APIKeyByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	query := `
+	SELECT
+		id, organization_id, name, key_hash, scopes, created_at, last_used_at, expires_at, revoked_at
+	FROM organization_api_keys
+	WHERE key_hash = $1
+	`
+
+	key, err := scanAPIKey(repo.conn(ctx).QueryRowContext(ctx, query, hash))
+	if err != nil {
+		return key, fmt.Errorf("repository.Repository.APIKeyByHash: %w", err)
+	}
+	return key, nil
+}
+
+func (repo *Repository) RevokeAPIKey(ctx context.Context, organizationId, keyId string) (bool, error) {
+	query := `
+	UPDATE organization_api_keys
+	SET revoked_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND organization_id = $2 AND revoked_at IS NULL
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, keyId, organizationId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RevokeAPIKey: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RevokeAPIKey: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (repo *Repository) TouchAPIKey(ctx context.Context, keyId string) error {
+	_, err := repo.conn(ctx).ExecContext(ctx, "UPDATE organization_api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1", keyId)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.TouchAPIKey: %w", err)
+	}
+	return nil
+}
+
+//// Service
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes pq.StringArray
+
+	err := row.Scan(&key.Id, &key.OrganizationId, &key.Name, &key.Hash, &scopes, &key.CreatedAt, &key.LastUsedAt, &key.ExpiresAt, &key.RevokedAt)
+	if err != nil {
+		return key, err
+	}
+
+	for _, s := range scopes {
+		key.Scopes = append(key.Scopes, models.APIScope(s))
+	}
+	return key, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+	"time"
+)
+
+func TestAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+
+	for org := range employees {
+		expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		key, err := repo.AddAPIKey(ctx, models.APIKey{
+			OrganizationId: org,
+			Name:           "Test key",
+			Scopes:         []models.APIScope{models.ScopeTendersWrite, models.ScopeBidsRead},
+			Hash:           "hash " + org,
+			ExpiresAt:      &expires,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := repo.APIKeyByHash(ctx, "hash "+org)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Id != key.Id || found.OrganizationId != org || len(found.Scopes) != 2 || found.ExpiresAt == nil || found.LastUsedAt != nil {
+			t.Errorf("Found key does not match added one: expected %v, got %v", key, found)
+		}
+
+		err = repo.TouchAPIKey(ctx, key.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := repo.GetAPIKeys(ctx, org)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0].LastUsedAt == nil {
+			t.Errorf("Expected to get 1 used key of organization '%s', got %v", org, keys)
+		}
+
+		ok, err := repo.RevokeAPIKey(ctx, org, key.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("Expected key '%s' to be revoked", key.Id)
+		}
+
+		ok, err = repo.RevokeAPIKey(ctx, org, key.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Errorf("Expected key '%s' not to be revoked twice", key.Id)
+		}
+	}
+
+	_, err := repo.APIKeyByHash(ctx, "none")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for missing key, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const attachmentColumns = `
+		id,
+		tender_id,
+		bid_id,
+		file_name,
+		content_type,
+		size,
+		checksum,
+		storage_key,
+		author_id,
+		created_at`
+
+func (repo *Repository) AddAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error) {
+	query := `
+	INSERT INTO attachments (tender_id, bid_id, file_name, content_type, size, checksum, storage_key, author_id)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING` + attachmentColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, nullableUUID(attachment.TenderId), nullableUUID(attachment.BidId), attachment.FileName,
+		attachment.ContentType, attachment.Size, attachment.Checksum, attachment.StorageKey, nullableUUID(attachment.AuthorId))
+	attachment, err := scanAttachment(row)
+	if err != nil {
+		return attachment, fmt.Errorf("repository.Repository.AddAttachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// GetTenderAttachments lists attachments of tender in order they are uploaded
+func (repo *Repository) GetTenderAttachments(ctx context.Context, tenderId string) ([]models.Attachment, error) {
+	attachments, err := repo.getAttachments(ctx, "tender_id", tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderAttachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// GetBidAttachments lists attachments of bid in order they are uploaded
+func (repo *Repository) GetBidAttachments(ctx context.Context, bidId string) ([]models.Attachment, error) {
+	attachments, err := repo.getAttachments(ctx, "bid_id", bidId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidAttachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (repo *Repository) GetAttachment(ctx context.Context, attachmentId string) (models.Attachment, error) {
+	query := `
+	SELECT` + attachmentColumns + `
+	FROM attachments
+	WHERE id = $1
+	`
+
+	attachment, err := scanAttachment(repo.conn(ctx).QueryRowContext(ctx, query, attachmentId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return attachment, fmt.Errorf("repository.Repository.GetAttachment: %w", models.ErrNoAttachment)
+		}
+		return attachment, fmt.Errorf("repository.Repository.GetAttachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// DeleteAttachment removes record of attachment, its content is removed from storage by caller
+func (repo *Repository) DeleteAttachment(ctx context.Context, attachmentId string) (bool, error) {
+	query := `
+	DELETE FROM attachments
+	WHERE id = $1
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, attachmentId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteAttachment: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteAttachment: %w", err)
+	}
+	return n > 0, nil
+}
+
+//// Service
+
+// getAttachments lists attachments by owner column, which is either tender_id or bid_id
+func (repo *Repository) getAttachments(ctx context.Context, column, ownerId string) ([]models.Attachment, error) {
+	query := `
+	SELECT` + attachmentColumns + `
+	FROM attachments
+	WHERE ` + column + ` = $1
+	ORDER BY created_at, id
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("rows scan failed: %w", err)
+		}
+		result = append(result, attachment)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return result, nil
+}
+
+func scanAttachment(row rowScanner) (models.Attachment, error) {
+	var attachment models.Attachment
+	var tenderId, bidId, authorId interface{}
+
+	err := row.Scan(&attachment.Id, &tenderId, &bidId, &attachment.FileName, &attachment.ContentType, &attachment.Size,
+		&attachment.Checksum, &attachment.StorageKey, &authorId, &attachment.CreatedAt)
+	if err != nil {
+		return attachment, err
+	}
+	attachment.TenderId = readUUID(tenderId)
+	attachment.BidId = readUUID(bidId)
+	attachment.AuthorId = readUUID(authorId)
+	return attachment, nil
+}
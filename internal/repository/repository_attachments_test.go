@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	bids := AddAllBids(t, ctx, repo, tenders, employees)
+	if len(tenders) == 0 || len(bids) == 0 {
+		t.Fatalf("Expected tenders and bids to be created using AddAllTenders and AddAllBids")
+	}
+
+	spec, err := repo.AddAttachment(ctx, models.Attachment{
+		TenderId:    tenders[0].Id,
+		FileName:    "spec.pdf",
+		ContentType: "application/pdf",
+		Size:        100,
+		Checksum:    "0000000000000000000000000000000000000000000000000000000000000000",
+		StorageKey:  "tenders/spec",
+		AuthorId:    tenders[0].Author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal, err := repo.AddAttachment(ctx, models.Attachment{
+		BidId:       bids[0].Id,
+		FileName:    "proposal.pdf",
+		ContentType: "application/pdf",
+		Size:        200,
+		Checksum:    "1111111111111111111111111111111111111111111111111111111111111111",
+		StorageKey:  "bids/proposal",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// attachment belongs to exactly one of tender and bid
+	_, err = repo.AddAttachment(ctx, models.Attachment{FileName: "orphan", ContentType: "text/plain", Checksum: spec.Checksum, StorageKey: "orphan"})
+	if err == nil {
+		t.Errorf("Expected attachment without owner to be rejected")
+	}
+	_, err = repo.AddAttachment(ctx, models.Attachment{TenderId: tenders[0].Id, FileName: "copy", ContentType: "text/plain", Checksum: spec.Checksum, StorageKey: spec.StorageKey})
+	if err == nil {
+		t.Errorf("Expected storage key to be unique")
+	}
+
+	attachments, err := repo.GetTenderAttachments(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 || attachments[0].Id != spec.Id || attachments[0].AuthorId != tenders[0].Author {
+		t.Fatalf("Expected single attachment of tender, got %v", attachments)
+	}
+	attachments, err = repo.GetBidAttachments(ctx, bids[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 || attachments[0].Id != proposal.Id || len(attachments[0].TenderId) > 0 {
+		t.Fatalf("Expected single attachment of bid, got %v", attachments)
+	}
+
+	ok, err := repo.DeleteAttachment(ctx, spec.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("Expected attachment to be deleted")
+	}
+	_, err = repo.GetAttachment(ctx, spec.Id)
+	if !errors.Is(err, models.ErrNoAttachment) {
+		t.Fatalf("Expected deleted attachment to be missing, got %v", err)
+	}
+	stored, err := repo.GetAttachment(ctx, proposal.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Checksum != proposal.Checksum || stored.StorageKey != "bids/proposal" {
+		t.Fatalf("Expected attachment of bid to be kept, got %v", stored)
+	}
+}
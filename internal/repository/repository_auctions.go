@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"tenders/internal/models"
+	"time"
+)
+
+const auctionColumns = `
+		tender_id,
+		status,
+		start_at,
+		end_at,
+		duration_minutes,
+		extension_minutes,
+		extensions,
+		min_decrement_amount,
+		currency,
+		created_by,
+		created_at,
+		closed_at`
+
+const auctionPriceColumns = `
+		id,
+		tender_id,
+		bid_id,
+		amount,
+		currency,
+		previous_amount,
+		user_id,
+		created_at`
+
+// SetAuction creates auction of tender or replaces terms of its scheduled one, models.ErrAuctionStarted
+// is returned if auction of tender has already started
+func (repo *Repository) SetAuction(ctx context.Context, a models.Auction) (models.Auction, error) {
+	query := `
+	INSERT INTO tender_auctions (tender_id, status, start_at, end_at, duration_minutes, extension_minutes, min_decrement_amount, currency, created_by)
+	VALUES
+		($1, 'Scheduled', $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (tender_id) DO UPDATE
+	SET (start_at, end_at, duration_minutes, extension_minutes, min_decrement_amount, currency, created_by, updated_at) =
+		(EXCLUDED.start_at, EXCLUDED.end_at, EXCLUDED.duration_minutes, EXCLUDED.extension_minutes, EXCLUDED.min_decrement_amount,
+		EXCLUDED.currency, EXCLUDED.created_by, CURRENT_TIMESTAMP)
+	WHERE tender_auctions.status = 'Scheduled'
+	RETURNING` + auctionColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, a.TenderId, a.StartAt.UTC(), a.EndAt.UTC(), a.DurationMinutes, a.ExtensionMinutes,
+		string(a.MinDecrement.Amount), a.MinDecrement.Currency, nullableUUID(a.CreatedBy))
+	a, err := scanAuction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, fmt.Errorf("repository.Repository.SetAuction: %w", models.ErrAuctionStarted)
+	} else if err != nil {
+		return a, fmt.Errorf("repository.Repository.SetAuction: %w", err)
+	}
+	return a, nil
+}
+
+// GetAuction returns auction of tender, models.ErrNoAuction is returned if tender has none
+func (repo *Repository) GetAuction(ctx context.Context, tenderId string) (models.Auction, error) {
+	query := `
+	SELECT` + auctionColumns + `
+	FROM tender_auctions
+	WHERE tender_id = $1
+	`
+
+	a, err := scanAuction(repo.conn(ctx).QueryRowContext(ctx, query, tenderId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, fmt.Errorf("repository.Repository.GetAuction: %w", models.ErrNoAuction)
+	} else if err != nil {
+		return a, fmt.Errorf("repository.Repository.GetAuction: %w", err)
+	}
+	return a, nil
+}
+
+// LockAuction returns auction of tender locking it until the end of transaction started by RunInTx,
+// so that prices of its bids are submitted one at a time
+func (repo *Repository) LockAuction(ctx context.Context, tenderId string) (models.Auction, error) {
+	query := `
+	SELECT` + auctionColumns + `
+	FROM tender_auctions
+	WHERE tender_id = $1
+	FOR UPDATE
+	`
+
+	a, err := scanAuction(repo.conn(ctx).QueryRowContext(ctx, query, tenderId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, fmt.Errorf("repository.Repository.LockAuction: %w", models.ErrNoAuction)
+	} else if err != nil {
+		return a, fmt.Errorf("repository.Repository.LockAuction: %w", err)
+	}
+	return a, nil
+}
+
+// UpdateAuctionProgress saves status, end time and extensions of auction, its terms are left as is
+func (repo *Repository) UpdateAuctionProgress(ctx context.Context, a models.Auction) error {
+	query := `
+	UPDATE tender_auctions
+	SET (status, end_at, extensions, closed_at, updated_at) = ($2, $3, $4, $5, CURRENT_TIMESTAMP)
+	WHERE tender_id = $1
+	`
+
+	_, err := repo.conn(ctx).ExecContext(ctx, query, a.TenderId, a.Status, a.EndAt.UTC(), a.Extensions, nullableTime(a.ClosedAt))
+	if err != nil {
+		return fmt.Errorf("repository.Repository.UpdateAuctionProgress: %w", err)
+	}
+	return nil
+}
+
+//// Ledger
+
+func (repo *Repository) AddAuctionPrice(ctx context.Context, p models.AuctionPrice) (models.AuctionPrice, error) {
+	query := `
+	INSERT INTO auction_prices (tender_id, bid_id, amount, currency, previous_amount, user_id)
+	VALUES
+		($1, $2, $3, $4, $5, $6)
+	RETURNING` + auctionPriceColumns
+
+	var previous interface{}
+	if p.PreviousPrice != nil {
+		previous = string(p.PreviousPrice.Amount)
+	}
+	row := repo.conn(ctx).QueryRowContext(ctx, query, p.TenderId, p.BidId, string(p.Price.Amount), p.Price.Currency, previous, nullableUUID(p.UserId))
+	p, err := scanAuctionPrice(row)
+	if err != nil {
+		return p, fmt.Errorf("repository.Repository.AddAuctionPrice: %w", err)
+	}
+	return p, nil
+}
+
+// GetAuctionPrices lists ledger of auction in order prices are submitted, optionally of single bid only
+func (repo *Repository) GetAuctionPrices(ctx context.Context, tenderId, bidId string, limit, offset int) ([]models.AuctionPrice, error) {
+	query := `
+	SELECT` + auctionPriceColumns + `
+	FROM auction_prices
+	WHERE tender_id = $3 AND ($4 = '' OR bid_id::text = $4)
+	ORDER BY created_at, id
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, tenderId, bidId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAuctionPrices: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.AuctionPrice{}
+	for rows.Next() {
+		p, err := scanAuctionPrice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetAuctionPrices: rows scan failed: %w", err)
+		}
+		result = append(result, p)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAuctionPrices: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// GetAuctionStandings ranks bids of tender qualified for auction in provided currency by their current price,
+// bids with the same price are ordered by the moment it was submitted, initial prices by bid creation
+func (repo *Repository) GetAuctionStandings(ctx context.Context, tenderId, currency string) ([]models.AuctionRank, error) {
+	query := `
+	SELECT p.id, p.price_amount, p.price_currency, COALESCE(MAX(l.created_at), p.created_at) AS priced_at
+	FROM proposals AS p
+		LEFT JOIN auction_prices AS l ON (l.bid_id = p.id)
+	WHERE p.tender_id = $1 AND p.status = 'Published' AND NOT p.reconfirmation_required
+		AND p.price_amount IS NOT NULL AND p.price_currency = $2
+	GROUP BY p.id
+	ORDER BY p.price_amount, priced_at, p.id
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, tenderId, currency)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAuctionStandings: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.AuctionRank{}
+	for rows.Next() {
+		var rank models.AuctionRank
+		var amount, currency sql.NullString
+		err := rows.Scan(&rank.BidId, &amount, &currency, &rank.PricedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetAuctionStandings: rows scan failed: %w", err)
+		}
+		rank.Price, err = readMoney(amount, currency)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetAuctionStandings: %w", err)
+		}
+		rank.Rank = len(result) + 1
+		result = append(result, rank)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAuctionStandings: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+//// Phases
+
+// GetDueAuctions returns scheduled auctions, start time of which is not after now
+func (repo *Repository) GetDueAuctions(ctx context.Context, now time.Time, limit int) ([]models.Auction, error) {
+	return repo.getAuctions(ctx, "status = 'Scheduled' AND start_at <= $1 ORDER BY start_at", now, limit)
+}
+
+// GetExpiredAuctions returns auctions not closed yet, end time of which is not after now
+func (repo *Repository) GetExpiredAuctions(ctx context.Context, now time.Time, limit int) ([]models.Auction, error) {
+	return repo.getAuctions(ctx, "status <> 'Closed' AND end_at <= $1 ORDER BY end_at", now, limit)
+}
+
+// StartDueAuction marks auction as running, if it is still scheduled and its start time is not after now.
+// Returned flag is false, when auction was changed concurrently and has not been started
+func (repo *Repository) StartDueAuction(ctx context.Context, tenderId string, now time.Time) (bool, error) {
+	query := `
+	UPDATE tender_auctions
+	SET (status, updated_at) = ('Running', CURRENT_TIMESTAMP)
+	WHERE tender_id = $1 AND status = 'Scheduled' AND start_at <= $2
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, tenderId, now.UTC())
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.StartDueAuction: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.StartDueAuction: %w", err)
+	}
+	return n > 0, nil
+}
+
+// CloseExpiredAuction closes auction, if it is not closed yet and its end time is not after now.
+// Returned flag is false, when auction was extended or closed concurrently and has not been closed
+func (repo *Repository) CloseExpiredAuction(ctx context.Context, tenderId string, now time.Time) (bool, error) {
+	query := `
+	UPDATE tender_auctions
+	SET (status, closed_at, updated_at) = ('Closed', $2, CURRENT_TIMESTAMP)
+	WHERE tender_id = $1 AND status <> 'Closed' AND end_at <= $2
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, tenderId, now.UTC())
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.CloseExpiredAuction: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.CloseExpiredAuction: %w", err)
+	}
+	return n > 0, nil
+}
+
+//// Service
+
+func (repo *Repository) getAuctions(ctx context.Context, condition string, now time.Time, limit int) ([]models.Auction, error) {
+	query := `
+	SELECT` + auctionColumns + `
+	FROM tender_auctions
+	WHERE ` + condition + `
+	LIMIT $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, now.UTC(), limitParam(limit))
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.getAuctions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Auction
+	for rows.Next() {
+		a, err := scanAuction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.getAuctions: row scan failed: %w", err)
+		}
+		result = append(result, a)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.getAuctions: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func scanAuction(row rowScanner) (models.Auction, error) {
+	var a models.Auction
+	var createdBy interface{}
+	var decrement string
+	var closedAt sql.NullTime
+
+	err := row.Scan(&a.TenderId, &a.Status, &a.StartAt, &a.EndAt, &a.DurationMinutes, &a.ExtensionMinutes, &a.Extensions,
+		&decrement, &a.MinDecrement.Currency, &createdBy, &a.CreatedAt, &closedAt)
+	if err != nil {
+		return a, err
+	}
+	a.MinDecrement.Amount, err = models.ParseDecimal(decrement)
+	if err != nil {
+		return a, err
+	}
+	a.CreatedBy = readUUID(createdBy)
+	if closedAt.Valid {
+		a.ClosedAt = &closedAt.Time
+	}
+	return a, nil
+}
+
+func scanAuctionPrice(row rowScanner) (models.AuctionPrice, error) {
+	var p models.AuctionPrice
+	var amount string
+	var previous sql.NullString
+	var userId interface{}
+
+	err := row.Scan(&p.Id, &p.TenderId, &p.BidId, &amount, &p.Price.Currency, &previous, &userId, &p.CreatedAt)
+	if err != nil {
+		return p, err
+	}
+	p.Price.Amount, err = models.ParseDecimal(amount)
+	if err != nil {
+		return p, err
+	}
+	p.PreviousPrice, err = readMoney(previous, sql.NullString{String: p.Price.Currency, Valid: true})
+	if err != nil {
+		return p, err
+	}
+	p.UserId = readUUID(userId)
+	return p, nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+	"time"
+)
+
+func TestAuctions(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	var org, user string
+	for o, users := range employees {
+		org, user = o, users[0]
+		break
+	}
+
+	tender, err := repo.AddTender(ctx, models.Tender{
+		Name:           "Auction tender",
+		Status:         models.TenderPublished,
+		ServiceType:    models.STDelivery,
+		OrganizationId: org,
+		Author:         user,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.GetAuction(ctx, tender.Id)
+	if !errors.Is(err, models.ErrNoAuction) {
+		t.Errorf("Expected missing auction to be reported, got %v", err)
+	}
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	auction, err := repo.SetAuction(ctx, models.Auction{
+		TenderId:        tender.Id,
+		StartAt:         start,
+		EndAt:           start.Add(30 * time.Minute),
+		DurationMinutes: 30,
+		MinDecrement:    models.Money{Amount: "10", Currency: "USD"},
+		CreatedBy:       user,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auction.Status != models.AuctionScheduled || !auction.StartAt.Equal(start) || auction.CreatedBy != user {
+		t.Errorf("Expected scheduled auction to be created, got %v", auction)
+	}
+
+	auction.ExtensionMinutes = 5
+	auction, err = repo.SetAuction(ctx, auction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auction.ExtensionMinutes != 5 {
+		t.Errorf("Expected terms of scheduled auction to be replaced, got %v", auction)
+	}
+
+	bids := make([]models.Bid, 2)
+	for i, amount := range []models.Decimal{"100", "90"} {
+		bids[i], err = repo.AddBid(ctx, models.Bid{
+			TenderId:       tender.Id,
+			AuthorType:     models.AuthorUser,
+			AuthorId:       user,
+			OrganizationId: org,
+			UserId:         user,
+			Name:           "Bid",
+			Price:          &models.Money{Amount: amount, Currency: "USD"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bids[i].Status = models.BidPublished
+		err = repo.UpdateBid(ctx, bids[i], true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	standings, err := repo.GetAuctionStandings(ctx, tender.Id, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 2 || standings[0].BidId != bids[1].Id || standings[1].Rank != 2 {
+		t.Fatalf("Expected cheaper bid to rank first, got %v", standings)
+	}
+	standings, err = repo.GetAuctionStandings(ctx, tender.Id, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 0 {
+		t.Errorf("Expected bids in other currency not to be ranked, got %v", standings)
+	}
+
+	ok, err := repo.StartDueAuction(ctx, tender.Id, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("Expected auction not to start before its start time")
+	}
+	due, err := repo.GetDueAuctions(ctx, start, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsAuction(due, tender.Id) {
+		t.Errorf("Expected auction to be due at its start time, got %v", due)
+	}
+	ok, err = repo.StartDueAuction(ctx, tender.Id, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("Expected due auction to start")
+	}
+
+	_, err = repo.SetAuction(ctx, auction)
+	if !errors.Is(err, models.ErrAuctionStarted) {
+		t.Errorf("Expected terms of started auction not to be replaced, got %v", err)
+	}
+
+	err = repo.RunInTx(ctx, func(ctx context.Context) error {
+		auction, err := repo.LockAuction(ctx, tender.Id)
+		if err != nil {
+			return err
+		}
+		price := models.Money{Amount: "80", Currency: "USD"}
+		_, err = repo.AddAuctionPrice(ctx, models.AuctionPrice{TenderId: tender.Id, BidId: bids[0].Id, Price: price, PreviousPrice: bids[0].Price, UserId: user})
+		if err != nil {
+			return err
+		}
+		bids[0].Price = &price
+		err = repo.UpdateBid(ctx, bids[0], true)
+		if err != nil {
+			return err
+		}
+		auction.EndAt = auction.EndAt.Add(5 * time.Minute)
+		auction.Extensions++
+		return repo.UpdateAuctionProgress(ctx, auction)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices, err := repo.GetAuctionPrices(ctx, tender.Id, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || prices[0].BidId != bids[0].Id || prices[0].PreviousPrice == nil || prices[0].PreviousPrice.Amount != "100" {
+		t.Errorf("Expected submitted price to be recorded in ledger, got %v", prices)
+	}
+	prices, err = repo.GetAuctionPrices(ctx, tender.Id, bids[1].Id, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 0 {
+		t.Errorf("Expected ledger of other bid to be empty, got %v", prices)
+	}
+
+	standings, err = repo.GetAuctionStandings(ctx, tender.Id, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 2 || standings[0].BidId != bids[0].Id {
+		t.Errorf("Expected lowered bid to rank first, got %v", standings)
+	}
+
+	auction, err = repo.GetAuction(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auction.Status != models.AuctionRunning || auction.Extensions != 1 || !auction.EndAt.Equal(start.Add(35*time.Minute)) {
+		t.Errorf("Expected auction progress to be saved, got %v", auction)
+	}
+
+	ok, err = repo.CloseExpiredAuction(ctx, tender.Id, start.Add(30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("Expected extended auction not to close before its end time")
+	}
+	expired, err := repo.GetExpiredAuctions(ctx, auction.EndAt, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsAuction(expired, tender.Id) {
+		t.Errorf("Expected auction to expire at its end time, got %v", expired)
+	}
+	ok, err = repo.CloseExpiredAuction(ctx, tender.Id, auction.EndAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("Expected expired auction to close")
+	}
+	auction, err = repo.GetAuction(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auction.Status != models.AuctionClosed || auction.ClosedAt == nil {
+		t.Errorf("Expected auction to be closed, got %v", auction)
+	}
+
+	err = repo.DeleteTender(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func containsAuction(auctions []models.Auction, tenderId string) bool {
+	for _, a := range auctions {
+		if a.TenderId == tenderId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"tenders/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// AddAuditEntry inserts audit log entry, it joins transaction of context if there is one
+func (repo *Repository) AddAuditEntry(ctx context.Context, e models.AuditEntry) (models.AuditEntry, error) {
+	query := `
+	INSERT INTO audit_log (actor_type, actor_id, actor_name, action, entity_type, entity_id, organization_ids, before, after, request_id)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING
+		id, created_at
+	`
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, e.ActorType, nullableUUID(e.ActorId), e.ActorName, e.Action, e.EntityType,
+		nullableUUID(e.EntityId), pq.StringArray(e.OrganizationIds), nullableJSON(e.Before), nullableJSON(e.After), e.RequestId)
+	err := row.Scan(&e.Id, &e.CreatedAt)
+	if err != nil {
+		return e, fmt.Errorf("repository.Repository.AddAuditEntry: %w", err)
+	}
+	return e, nil
+}
+
+func (repo *Repository) GetAuditEntries(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]models.AuditEntry, error) {
+	query := `
+	SELECT
+		id, actor_type, actor_id, COALESCE(actor_name, ''), action, entity_type, entity_id, organization_ids,
+		before, after, COALESCE(request_id, ''), created_at
+	FROM audit_log
+	$conditions$
+	ORDER BY created_at DESC, id
+	LIMIT $1
+	OFFSET $2
+	`
+
+	params := []interface{}{limitParam(limit), offset}
+	conditions := make([]string, 0, 7)
+
+	if len(filter.OrganizationId) > 0 {
+		conditions = append(conditions, "$$ = any(organization_ids)")
+		params = append(params, filter.OrganizationId)
+	}
+	if len(filter.ActorId) > 0 {
+		conditions = append(conditions, "actor_id = $$")
+		params = append(params, filter.ActorId)
+	}
+	if len(filter.Action) > 0 {
+		conditions = append(conditions, "action = $$")
+		params = append(params, filter.Action)
+	}
+	if len(filter.EntityType) > 0 {
+		conditions = append(conditions, "entity_type = $$")
+		params = append(params, filter.EntityType)
+	}
+	if len(filter.EntityId) > 0 {
+		conditions = append(conditions, "entity_id = $$")
+		params = append(params, filter.EntityId)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= $$")
+		params = append(params, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < $$")
+		params = append(params, *filter.To)
+	}
+
+	condStr := ""
+	if len(conditions) > 0 {
+		for i := 0; i < len(conditions); i++ {
+			conditions[i] = strings.Replace(conditions[i], "$$", "$"+strconv.Itoa(i+3), -1)
+		}
+		condStr = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query = strings.Replace(query, "$conditions$", condStr, -1)
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAuditEntries: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.AuditEntry
+	for rows.Next() {
+		var e models.AuditEntry
+		var actorId, entityId interface{}
+		var orgs pq.StringArray
+		var before, after []byte
+
+		err = rows.Scan(&e.Id, &e.ActorType, &actorId, &e.ActorName, &e.Action, &e.EntityType, &entityId, &orgs, &before, &after, &e.RequestId, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetAuditEntries: rows scan failed: %w", err)
+		}
+		e.ActorId = readUUID(actorId)
+		e.EntityId = readUUID(entityId)
+		e.OrganizationIds = orgs
+		e.Before = before
+		e.After = after
+		result = append(result, e)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetAuditEntries: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// nullableJSON converts empty json into NULL
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
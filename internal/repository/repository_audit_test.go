@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+
+	var orgs []string
+	for org := range employees {
+		orgs = append(orgs, org)
+	}
+
+	entry := models.AuditEntry{
+		ActorType:       models.ActorUser,
+		ActorId:         employees[orgs[0]][0],
+		ActorName:       "Test1",
+		Action:          models.ActionOrganizationEdit,
+		EntityType:      models.EntityOrganization,
+		EntityId:        orgs[0],
+		OrganizationIds: []string{orgs[0], orgs[1]},
+		Before:          []byte(`{"name": "before"}`),
+		After:           []byte(`{"name": "after"}`),
+		RequestId:       "request",
+	}
+	added, err := repo.AddAuditEntry(ctx, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added.Id) == 0 || added.CreatedAt.IsZero() {
+		t.Errorf("Expected added entry to have id and creation time, got: %v", added)
+	}
+
+	// entries are visible to every organization they concern
+	for i, org := range orgs {
+		entries, err := repo.GetAuditEntries(ctx, models.AuditFilter{OrganizationId: org}, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i < 2 && (len(entries) != 1 || entries[0].Id != added.Id || entries[0].RequestId != "request") {
+			t.Errorf("Expected organization '%s' to see added entry, got: %v", org, entries)
+		}
+		if i >= 2 && len(entries) != 0 {
+			t.Errorf("Expected organization '%s' to see no entries, got: %v", org, entries)
+		}
+	}
+
+	from := added.CreatedAt.Add(time.Hour)
+	filters := map[string]models.AuditFilter{
+		"action": {OrganizationId: orgs[0], Action: models.ActionTenderCreate},
+		"entity": {OrganizationId: orgs[0], EntityType: models.EntityTender},
+		"actor":  {OrganizationId: orgs[0], ActorId: employees[orgs[1]][0]},
+		"from":   {OrganizationId: orgs[0], From: &from},
+	}
+	for name, filter := range filters {
+		entries, err := repo.GetAuditEntries(ctx, filter, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("Expected filter by %s to exclude entry, got: %v", name, entries)
+		}
+	}
+
+	// entry written in failed transaction is rolled back along with it
+	failure := errors.New("failure")
+	err = repo.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := repo.AddAuditEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected transaction to fail with '%s', got: %s", failure, err)
+	}
+
+	entries, err := repo.GetAuditEntries(ctx, models.AuditFilter{EntityId: orgs[0]}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected rolled back entry not to be saved, got %d entries", len(entries))
+	}
+
+	// categories are identified by codes rather than UUIDs
+	category, err := repo.AddAuditEntry(ctx, models.AuditEntry{
+		ActorType:  models.ActorUser,
+		ActorId:    employees[orgs[0]][0],
+		Action:     models.ActionCategoryCreate,
+		EntityType: models.EntityCategory,
+		EntityId:   string(models.STConstruction),
+	})
+	if err != nil {
+		t.Fatalf("Could not audit category: %s", err)
+	}
+	entries, err = repo.GetAuditEntries(ctx, models.AuditFilter{EntityId: string(models.STConstruction)}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Id != category.Id || entries[0].EntityId != string(models.STConstruction) {
+		t.Errorf("Expected category entry to be saved with its code, got %v", entries)
+	}
+}
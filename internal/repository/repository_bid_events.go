@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const bidEventColumns = `
+		id,
+		bid_id,
+		tender_id,
+		type,
+		reason,
+		previous_status,
+		user_id,
+		created_at`
+
+func (repo *Repository) AddBidEvent(ctx context.Context, event models.BidEvent) (models.BidEvent, error) {
+	query := `
+	INSERT INTO bid_events (bid_id, tender_id, type, reason, previous_status, user_id)
+	VALUES
+		($1, $2, $3, $4, $5, $6)
+	RETURNING` + bidEventColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, event.BidId, event.TenderId, event.Type, event.Reason, event.PreviousStatus, nullableUUID(event.UserId))
+	event, err := scanBidEvent(row)
+	if err != nil {
+		return event, fmt.Errorf("repository.Repository.AddBidEvent: %w", err)
+	}
+	return event, nil
+}
+
+// GetBidEvents lists withdrawals and resubmissions of bids of tender in order they happened, optionally of single bid only
+func (repo *Repository) GetBidEvents(ctx context.Context, tenderId, bidId string, limit, offset int) ([]models.BidEvent, error) {
+	query := `
+	SELECT` + bidEventColumns + `
+	FROM bid_events
+	WHERE tender_id = $3 AND ($4 = '' OR bid_id::text = $4)
+	ORDER BY created_at, id
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, tenderId, bidId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidEvents: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.BidEvent{}
+	for rows.Next() {
+		event, err := scanBidEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetBidEvents: rows scan failed: %w", err)
+		}
+		result = append(result, event)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidEvents: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+//// Service
+
+func scanBidEvent(row rowScanner) (models.BidEvent, error) {
+	var event models.BidEvent
+	var userId interface{}
+
+	err := row.Scan(&event.Id, &event.BidId, &event.TenderId, &event.Type, &event.Reason, &event.PreviousStatus, &userId, &event.CreatedAt)
+	if err != nil {
+		return event, err
+	}
+	event.UserId = readUUID(userId)
+	return event, nil
+}
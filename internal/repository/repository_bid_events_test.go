@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestBidEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	var org, user string
+	for o, users := range employees {
+		org, user = o, users[0]
+		break
+	}
+
+	tender, err := repo.AddTender(ctx, models.Tender{
+		Name:           "Withdrawals tender",
+		Status:         models.TenderPublished,
+		ServiceType:    models.STConstruction,
+		OrganizationId: org,
+		Author:         user,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bids := make([]models.Bid, 2)
+	for i := range bids {
+		bids[i], err = repo.AddBid(ctx, models.Bid{
+			TenderId:       tender.Id,
+			AuthorType:     models.AuthorUser,
+			AuthorId:       user,
+			OrganizationId: org,
+			UserId:         user,
+			Name:           "Bid",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	withdrawal, err := repo.AddBidEvent(ctx, models.BidEvent{
+		BidId:          bids[0].Id,
+		TenderId:       tender.Id,
+		Type:           models.BidWithdrawn,
+		Reason:         "Prices changed",
+		PreviousStatus: models.BidPublished,
+		UserId:         user,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withdrawal.Id) == 0 || withdrawal.Reason != "Prices changed" || withdrawal.UserId != user || withdrawal.CreatedAt.IsZero() {
+		t.Errorf("Expected withdrawal to be stored, got %v", withdrawal)
+	}
+	_, err = repo.AddBidEvent(ctx, models.BidEvent{
+		BidId:          bids[0].Id,
+		TenderId:       tender.Id,
+		Type:           models.BidResubmitted,
+		PreviousStatus: models.BidCanceled,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.AddBidEvent(ctx, models.BidEvent{
+		BidId:          bids[1].Id,
+		TenderId:       tender.Id,
+		Type:           models.BidWithdrawn,
+		Reason:         "Not interested",
+		PreviousStatus: models.BidCreated,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := repo.GetBidEvents(ctx, tender.Id, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[0].Id != withdrawal.Id || events[1].Type != models.BidResubmitted || len(events[1].UserId) > 0 {
+		t.Errorf("Expected events of tender in order, got %v", events)
+	}
+
+	events, err = repo.GetBidEvents(ctx, tender.Id, bids[1].Id, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].BidId != bids[1].Id {
+		t.Errorf("Expected events of single bid, got %v", events)
+	}
+
+	events, err = repo.GetBidEvents(ctx, tender.Id, "", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != models.BidResubmitted {
+		t.Errorf("Expected events to be paginated, got %v", events)
+	}
+
+	err = repo.DeleteTender(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
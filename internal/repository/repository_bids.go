@@ -1,281 +1,375 @@
-package repository
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"strconv"
-	"strings"
-	"tenders/internal/models"
-)
-
-func (repo *Repository) AddBid(ctx context.Context, bid models.Bid) (models.Bid, error) {
-	query := `
-	INSERT INTO proposals (version, tender_id, author_user_id, author_organization_id, status, name, description, created_at, updated_at)
-	VALUES
-		(1, $1, $2, $3, 'Created', $4, $5, DEFAULT, DEFAULT)
-	RETURNING
-		id, version, status, created_at, updated_at
-	`
-
-	tx, err := repo.db.BeginTx(ctx, nil)
-	if err != nil {
-		return bid, fmt.Errorf("repository.Repository.AddBid: failed to start transaction: %w", err)
-	}
-
-	var userId, orgId interface{}
-	if bid.AuthorType == models.AuthorOrganization {
-		orgId = bid.AuthorId
-		userId = nil
-	} else {
-		if len(bid.OrganizationId) == 0 {
-			bid.OrganizationId, err = repo.UserOrganizationId(ctx, bid.AuthorId)
-			if err != nil {
-				return bid, fmt.Errorf("repository.Repository.AddBid: %w", err)
-			}
-		}
-		userId = bid.AuthorId
-		orgId = bid.OrganizationId
-	}
-
-	row := tx.QueryRowContext(ctx, query, bid.TenderId, userId, orgId, bid.Name, bid.Description)
-	err = row.Scan(&bid.Id, &bid.Version, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
-	if err != nil {
-		return bid, fmt.Errorf("repository.Repository.AddBid: scan failed: %w", wrapRollbackErr(tx, err))
-	}
-
-	err = repo.AddBidVersion(ctx, bid, tx)
-	if err != nil {
-		return bid, fmt.Errorf("repository.Repository.AddBid: %w", wrapRollbackErr(tx, err))
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return bid, fmt.Errorf("repository.Repository.AddBid: failed to commit transaction: %w", err)
-	}
-
-	return bid, nil
-}
-
-func (repo *Repository) prepBidsQuery(limit, offset int, userId, tenderId, UUID string) (query string, queryParams []interface{}) {
-	query = `
-	SELECT
-		id, version, tender_id, author_user_id, author_organization_id, status, name, description, created_at, updated_at
-	FROM proposals
-	$conditions$
-	ORDER BY name
-	LIMIT $1
-	OFFSET $2
-	`
-
-	queryParams = make([]interface{}, 0, 5)
-	conditions := make([]string, 0, 3)
-
-	if limit <= 0 {
-		queryParams = append(queryParams, nil)
-	} else {
-		queryParams = append(queryParams, limit)
-	}
-	queryParams = append(queryParams, offset)
-
-	if len(userId) > 0 {
-		queryParams = append(queryParams, userId)
-		conditions = append(conditions, "author_user_id = $$")
-	}
-	if len(tenderId) > 0 {
-		queryParams = append(queryParams, tenderId)
-		conditions = append(conditions, "tender_id = $$")
-	}
-	if len(UUID) > 0 {
-		queryParams = append(queryParams, UUID)
-		conditions = append(conditions, "id = $$")
-	}
-
-	condStr := ""
-	if len(conditions) > 0 {
-		for i := 0; i < len(conditions); i++ {
-			conditions[i] = strings.Replace(conditions[i], "$$", "$"+strconv.Itoa(i+3), -1)
-		}
-		condStr = "WHERE " + strings.Join(conditions, " AND ")
-	}
-	query = strings.Replace(query, "$conditions$", condStr, -1)
-
-	return query, queryParams
-}
-
-func (repo *Repository) GetBids(ctx context.Context, limit, offset int, userId, tenderId string) ([]models.Bid, error) {
-	query, params := repo.prepBidsQuery(limit, offset, userId, tenderId, "")
-
-	rows, err := repo.db.QueryContext(ctx, query, params...)
-	if err != nil {
-		return nil, fmt.Errorf("repository.Repository.GetBids: %w", err)
-	}
-	defer rows.Close()
-
-	var result []models.Bid
-	var bid models.Bid
-	var suserId, sorganizationId interface{}
-	for rows.Next() {
-		err = rows.Scan(&bid.Id, &bid.Version, &bid.TenderId, &suserId, &sorganizationId, &bid.Status, &bid.Name, &bid.Description, &bid.CreatedAt, &bid.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("repository.Repository.GetBids: rows scan error: %w", err)
-		}
-		bid.UserId = readUUID(suserId)
-		bid.OrganizationId = readUUID(sorganizationId)
-
-		if len(bid.UserId) == 0 {
-			bid.AuthorType = models.AuthorOrganization
-			bid.AuthorId = bid.OrganizationId
-		} else {
-			bid.AuthorType = models.AuthorUser
-			bid.AuthorId = bid.UserId
-		}
-		result = append(result, bid)
-	}
-
-	if rows.Err() != nil {
-		return nil, fmt.Errorf("repository.Repository.GetBids: %w", err)
-	}
-
-	return result, nil
-}
-
-func (repo *Repository) GetBidByUUID(ctx context.Context, UUID string) (models.Bid, error) {
-	var bid models.Bid
-	var suserId, sorganizationId interface{}
-	query, params := repo.prepBidsQuery(1, 0, "", "", UUID)
-	row := repo.db.QueryRowContext(ctx, query, params...)
-	err := row.Scan(&bid.Id, &bid.Version, &bid.TenderId, &suserId, &sorganizationId, &bid.Status, &bid.Name, &bid.Description, &bid.CreatedAt, &bid.UpdatedAt)
-	if err != nil {
-		return bid, fmt.Errorf("repository.Repository.GetBidByUUID: %w", err)
-	}
-	bid.UserId = readUUID(suserId)
-	bid.OrganizationId = readUUID(sorganizationId)
-
-	if len(bid.UserId) == 0 {
-		bid.AuthorType = models.AuthorOrganization
-		bid.AuthorId = bid.OrganizationId
-	} else {
-		bid.AuthorType = models.AuthorUser
-		bid.AuthorId = bid.UserId
-	}
-	return bid, nil
-}
-
-func (repo *Repository) UpdateBid(ctx context.Context, bid models.Bid, incrementVersion bool) error {
-	query := `
-	UPDATE proposals
-	SET (version, status, name, description, updated_at) = ($1, $2, $3, $4, CURRENT_TIMESTAMP)
-	WHERE id = $5
-	`
-
-	tx, err := repo.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.UpdateBid: failed to start transaction: %w", err)
-	}
-
-	if incrementVersion {
-		bid.Version++
-	}
-	_, err = tx.ExecContext(ctx, query, bid.Version, bid.Status, bid.Name, bid.Description, bid.Id)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.UpdateBid: %w", wrapRollbackErr(tx, err))
-	}
-
-	if incrementVersion {
-		err = repo.AddBidVersion(ctx, bid, tx)
-		if err != nil {
-			return fmt.Errorf("repository.Repository.UpdateBid: %w", wrapRollbackErr(tx, err))
-		}
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("repository.Repository.UpdateBid: failed to commit transaction: %w", err)
-	}
-
-	return nil
-}
-
-func (repo *Repository) DeleteBid(ctx context.Context, UUID string) error {
-	_, err := repo.db.Exec("DELETE FROM proposals WHERE id = $1", UUID)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.DeleteBid: %w", err)
-	}
-	return nil
-}
-
-//// Versions
-
-func (repo *Repository) AddBidVersion(ctx context.Context, bid models.Bid, tx *sql.Tx) error {
-	query := `
-	INSERT INTO proposals_versions (id, version, tender_id, author_user_id, author_organization_id, status, name, description, created_at, updated_at)
-	VALUES
-		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-	`
-
-	var err error
-	var userId, orgId interface{}
-	if bid.AuthorType == models.AuthorOrganization {
-		orgId = bid.AuthorId
-		userId = nil
-	} else {
-		orgId = bid.OrganizationId
-		userId = bid.UserId
-	}
-
-	if tx == nil {
-		_, err = repo.db.ExecContext(ctx, query, bid.Id, bid.Version, bid.TenderId, userId, orgId, bid.Status, bid.Name, bid.Description, bid.CreatedAt, bid.UpdatedAt)
-	} else {
-		_, err = tx.ExecContext(ctx, query, bid.Id, bid.Version, bid.TenderId, userId, orgId, bid.Status, bid.Name, bid.Description, bid.CreatedAt, bid.UpdatedAt)
-	}
-	if err != nil {
-		return fmt.Errorf("repository.Repository.AddBidVersion: scan failed: %w", err)
-	}
-
-	return nil
-}
-
-func (repo *Repository) GetBidVersions(ctx context.Context, UUID string, version int) ([]models.Bid, error) {
-	query := `
-	SELECT 	id, version, tender_id, author_user_id, author_organization_id, status, name, description, created_at, updated_at
-	FROM proposals_versions
-	WHERE id = $1 AND ($2 <= 0 OR version = $2)
-	ORDER BY updated_at DESC
-	`
-
-	rows, err := repo.db.Query(query, UUID, version)
-	if err != nil {
-		return nil, fmt.Errorf("repository.Repository.GetBidVersions: %w", err)
-	}
-	defer rows.Close()
-
-	var result []models.Bid
-	var bid models.Bid
-	for rows.Next() {
-		err = rows.Scan(&bid.Id, &bid.Version, &bid.TenderId, &bid.UserId, &bid.OrganizationId, &bid.Status, &bid.Name, &bid.Description, &bid.CreatedAt, &bid.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("repository.Repository.GetBidVersions: rows scan error: %w", err)
-		}
-		result = append(result, bid)
-	}
-
-	if rows.Err() != nil {
-		return nil, fmt.Errorf("repository.Repository.GetBidVersions: %w", rows.Err())
-	}
-
-	return result, nil
-}
-
-//// Service
-
-func readUUID(val interface{}) string {
-	switch v := val.(type) {
-	case string:
-		return v
-	case []byte:
-		return string(v)
-	default:
-		return ""
-	}
-}
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"tenders/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const bidColumns = `
+		id,
+		version,
+		tender_id,
+		author_user_id,
+		author_organization_id,
+		status,
+		name,
+		description,
+		price_amount,
+		price_currency,
+		lot_ids,
+		tender_version,
+		reconfirmation_required,
+		sealed_content,
+		created_at,
+		updated_at`
+
+func (repo *Repository) AddBid(ctx context.Context, bid models.Bid) (models.Bid, error) {
+	query := `
+	INSERT INTO proposals (version, tender_id, author_user_id, author_organization_id, status, name, description, price_amount, price_currency, lot_ids, tender_version,
+		sealed_content, created_at, updated_at)
+	VALUES
+		(1, $1, $2, $3, 'Created', $4, $5, $6, $7, COALESCE($8::uuid[], '{}'), $9, $10, DEFAULT, DEFAULT)
+	RETURNING
+		id, version, status, created_at, updated_at
+	`
+
+	var err error
+	var userId, orgId interface{}
+	if bid.AuthorType == models.AuthorOrganization {
+		orgId = bid.AuthorId
+		userId = nil
+	} else {
+		if len(bid.OrganizationId) == 0 {
+			bid.OrganizationId, err = repo.UserOrganizationId(ctx, bid.AuthorId)
+			if err != nil {
+				return bid, fmt.Errorf("repository.Repository.AddBid: %w", err)
+			}
+		}
+		userId = bid.AuthorId
+		orgId = bid.OrganizationId
+	}
+
+	// bids are submitted against first version of tender unless told otherwise
+	if bid.TenderVersion < 1 {
+		bid.TenderVersion = 1
+	}
+
+	err = repo.RunInTx(ctx, func(ctx context.Context) error {
+		amount, currency := moneyParams(bid.Price)
+		row := repo.conn(ctx).QueryRowContext(ctx, query, bid.TenderId, userId, orgId, bid.Name, bid.Description, amount, currency, pq.Array(bid.LotIds), bid.TenderVersion,
+			bid.SealedContent)
+		err := row.Scan(&bid.Id, &bid.Version, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+
+		return repo.AddBidVersion(ctx, bid, nil)
+	})
+	if err != nil {
+		return bid, fmt.Errorf("repository.Repository.AddBid: %w", err)
+	}
+
+	return bid, nil
+}
+
+func (repo *Repository) prepBidsQuery(limit, offset int, filter models.BidFilter) (query string, queryParams []interface{}) {
+	query = `
+	SELECT` + bidColumns + `
+	FROM proposals
+	$conditions$
+	ORDER BY $order$
+	LIMIT $1
+	OFFSET $2
+	`
+
+	queryParams = make([]interface{}, 0, 8)
+	conditions := make([]string, 0, 6)
+
+	if limit <= 0 {
+		queryParams = append(queryParams, nil)
+	} else {
+		queryParams = append(queryParams, limit)
+	}
+	queryParams = append(queryParams, offset)
+
+	if len(filter.UserId) > 0 {
+		queryParams = append(queryParams, filter.UserId)
+		conditions = append(conditions, "author_user_id = $$")
+	}
+	if len(filter.TenderId) > 0 {
+		queryParams = append(queryParams, filter.TenderId)
+		conditions = append(conditions, "tender_id = $$")
+	}
+	if len(filter.BidId) > 0 {
+		queryParams = append(queryParams, filter.BidId)
+		conditions = append(conditions, "id = $$")
+	}
+	if len(filter.LotId) > 0 {
+		queryParams = append(queryParams, filter.LotId)
+		conditions = append(conditions, "$$::uuid = any(lot_ids)")
+	}
+	if len(filter.OrganizationId) > 0 {
+		queryParams = append(queryParams, filter.OrganizationId)
+		conditions = append(conditions, "author_organization_id = $$")
+	}
+	if len(filter.Statuses) > 0 {
+		queryParams = append(queryParams, sliceToSQLList(filter.Statuses))
+		conditions = append(conditions, "status = any($$::proposal_status[])")
+	}
+	conditions, queryParams = appendTimeRange(conditions, queryParams, "created_at", filter.Created)
+	conditions, queryParams = appendTimeRange(conditions, queryParams, "updated_at", filter.Updated)
+	if len(filter.Price.Currency) > 0 {
+		queryParams = append(queryParams, filter.Price.Currency)
+		conditions = append(conditions, "price_currency = $$")
+	}
+	if len(filter.Price.Min) > 0 {
+		queryParams = append(queryParams, string(filter.Price.Min))
+		conditions = append(conditions, "price_amount >= $$")
+	}
+	if len(filter.Price.Max) > 0 {
+		queryParams = append(queryParams, string(filter.Price.Max))
+		conditions = append(conditions, "price_amount <= $$")
+	}
+
+	condStr := ""
+	if len(conditions) > 0 {
+		for i := 0; i < len(conditions); i++ {
+			conditions[i] = strings.Replace(conditions[i], "$$", "$"+strconv.Itoa(i+3), -1)
+		}
+		condStr = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query = strings.Replace(query, "$conditions$", condStr, -1)
+	query = strings.Replace(query, "$order$", orderClause(filter.Sort, map[string]string{
+		"name":      "name",
+		"price":     "price_amount",
+		"status":    "status",
+		"createdAt": "created_at",
+		"updatedAt": "updated_at",
+	}), -1)
+
+	return query, queryParams
+}
+
+func (repo *Repository) GetBids(ctx context.Context, limit, offset int, filter models.BidFilter) ([]models.Bid, error) {
+	query, params := repo.prepBidsQuery(limit, offset, filter)
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBids: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Bid
+	for rows.Next() {
+		bid, err := scanBid(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetBids: rows scan error: %w", err)
+		}
+		result = append(result, bid)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBids: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetBidByUUID(ctx context.Context, UUID string) (models.Bid, error) {
+	query, params := repo.prepBidsQuery(1, 0, models.BidFilter{BidId: UUID})
+	bid, err := scanBid(repo.conn(ctx).QueryRowContext(ctx, query, params...))
+	if err != nil {
+		return bid, fmt.Errorf("repository.Repository.GetBidByUUID: %w", err)
+	}
+	return bid, nil
+}
+
+// UpdateBid overwrites bid provided it is still of version bid.Version, otherwise models.ErrVersionConflict is returned
+func (repo *Repository) UpdateBid(ctx context.Context, bid models.Bid, incrementVersion bool) error {
+	query := `
+	UPDATE proposals
+	SET (version, status, name, description, price_amount, price_currency, lot_ids, tender_version, reconfirmation_required, sealed_content, updated_at) =
+	($1, $2, $3, $4, $5, $6, COALESCE($7::uuid[], '{}'), $8, $9, $10, CURRENT_TIMESTAMP)
+	WHERE id = $11 AND version = $12
+	`
+
+	current := bid.Version
+	if incrementVersion {
+		bid.Version++
+	}
+
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		amount, currency := moneyParams(bid.Price)
+		res, err := repo.conn(ctx).ExecContext(ctx, query, bid.Version, bid.Status, bid.Name, bid.Description, amount, currency, pq.Array(bid.LotIds),
+			bid.TenderVersion, bid.ReconfirmationRequired, bid.SealedContent, bid.Id, current)
+		if err != nil {
+			return err
+		}
+		err = checkVersionUpdated(res)
+		if err != nil || !incrementVersion {
+			return err
+		}
+		return repo.AddBidVersion(ctx, bid, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("repository.Repository.UpdateBid: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *Repository) DeleteBid(ctx context.Context, UUID string) error {
+	_, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM proposals WHERE id = $1", UUID)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.DeleteBid: %w", err)
+	}
+	return nil
+}
+
+//// Versions
+
+func (repo *Repository) AddBidVersion(ctx context.Context, bid models.Bid, tx *sql.Tx) error {
+	query := `
+	INSERT INTO proposals_versions (id, version, tender_id, author_user_id, author_organization_id, status, name, description, price_amount, price_currency, lot_ids, tender_version, reconfirmation_required,
+		sealed_content, created_at, updated_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11::uuid[], '{}'), $12, $13, $14, $15, $16)
+	`
+
+	var err error
+	var userId, orgId interface{}
+	if bid.AuthorType == models.AuthorOrganization {
+		orgId = bid.AuthorId
+		userId = nil
+	} else {
+		orgId = bid.OrganizationId
+		userId = bid.UserId
+	}
+
+	amount, currency := moneyParams(bid.Price)
+	lotIds := pq.Array(bid.LotIds)
+	if tx == nil {
+		_, err = repo.conn(ctx).ExecContext(ctx, query, bid.Id, bid.Version, bid.TenderId, userId, orgId, bid.Status, bid.Name, bid.Description, amount, currency, lotIds, bid.TenderVersion, bid.ReconfirmationRequired, bid.SealedContent, bid.CreatedAt, bid.UpdatedAt)
+	} else {
+		_, err = tx.ExecContext(ctx, query, bid.Id, bid.Version, bid.TenderId, userId, orgId, bid.Status, bid.Name, bid.Description, amount, currency, lotIds, bid.TenderVersion, bid.ReconfirmationRequired, bid.SealedContent, bid.CreatedAt, bid.UpdatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("repository.Repository.AddBidVersion: scan failed: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *Repository) GetBidVersions(ctx context.Context, UUID string, version int) ([]models.Bid, error) {
+	query := `
+	SELECT` + bidColumns + `
+	FROM proposals_versions
+	WHERE id = $1 AND ($2 <= 0 OR version = $2)
+	ORDER BY updated_at DESC
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, UUID, version)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidVersions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Bid
+	for rows.Next() {
+		bid, err := scanBid(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetBidVersions: rows scan error: %w", err)
+		}
+		result = append(result, bid)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidVersions: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// GetBidVersionHistory lists stored versions of bid in ascending order
+func (repo *Repository) GetBidVersionHistory(ctx context.Context, UUID string, limit, offset int) ([]models.Bid, error) {
+	query := `
+	SELECT` + bidColumns + `
+	FROM proposals_versions
+	WHERE id = $3
+	ORDER BY version, updated_at
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, UUID)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidVersionHistory: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Bid{}
+	for rows.Next() {
+		bid, err := scanBid(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetBidVersionHistory: rows scan error: %w", err)
+		}
+		result = append(result, bid)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidVersionHistory: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+//// Service
+
+func scanBid(row rowScanner) (models.Bid, error) {
+	var bid models.Bid
+	var userId, organizationId interface{}
+	var amount, currency sql.NullString
+	var lotIds pq.StringArray
+
+	err := row.Scan(&bid.Id, &bid.Version, &bid.TenderId, &userId, &organizationId, &bid.Status, &bid.Name, &bid.Description,
+		&amount, &currency, &lotIds, &bid.TenderVersion, &bid.ReconfirmationRequired, &bid.SealedContent, &bid.CreatedAt, &bid.UpdatedAt)
+	if err != nil {
+		return bid, err
+	}
+	if len(lotIds) > 0 {
+		bid.LotIds = lotIds
+	}
+	bid.Sealed = len(bid.SealedContent) > 0
+	bid.UserId = readUUID(userId)
+	bid.OrganizationId = readUUID(organizationId)
+	bid.Price, err = readMoney(amount, currency)
+	if err != nil {
+		return bid, err
+	}
+
+	if len(bid.UserId) == 0 {
+		bid.AuthorType = models.AuthorOrganization
+		bid.AuthorId = bid.OrganizationId
+	} else {
+		bid.AuthorType = models.AuthorUser
+		bid.AuthorId = bid.UserId
+	}
+	return bid, nil
+}
+
+func readUUID(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
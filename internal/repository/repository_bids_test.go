@@ -1,82 +1,138 @@
-package repository
-
-import (
-	"context"
-	"tenders/internal/models"
-	"testing"
-)
-
-func TestBids(t *testing.T) {
-	var err error
-	ctx := context.Background()
-	repo := OpenTestRepo(t)
-	defer repo.Close()
-
-	// Insert organizations and employees
-	employees := InsertTestInitData(t, repo.db)
-
-	// Insert tenders
-	tenders := AddAllTenders(t, repo, employees)
-
-	// Insert bids
-	bids := AddAllBids(t, ctx, repo, tenders, employees)
-
-	// Update
-	bids[0].Description = "Changed description"
-	repo.UpdateBid(ctx, bids[0], true)
-
-	ubids, err := repo.GetBids(ctx, 1, 0, bids[0].AuthorId, bids[0].TenderId)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(ubids) == 0 {
-		t.Error("Could not get updated bid")
-	}
-
-	bids[0].Status = models.BidApproved
-	repo.UpdateBid(ctx, bids[0], false)
-
-	// Delete
-	for _, bid := range bids {
-		versions, err := repo.GetBidVersions(ctx, bid.Id, 0)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if len(versions) == 0 {
-			t.Errorf("No versions created for bid '%s' during test", bid.Id)
-		}
-		repo.DeleteBid(ctx, bid.Id)
-	}
-
-	bids, err = repo.GetBids(ctx, 0, 0, "", "")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(bids) > 0 {
-		t.Errorf("Expected to have %d bid entries, got %d", 0, len(bids))
-	}
-}
-
-func AddAllBids(t *testing.T, ctx context.Context, repo *Repository, tenders []models.Tender, employees map[string][]string) []models.Bid {
-	var err error
-	var bids []models.Bid
-	var bid models.Bid
-	for _, tender := range tenders {
-		for org, empl := range employees {
-			bid, err = repo.AddBid(ctx, models.Bid{
-				TenderId:       tender.Id,
-				AuthorType:     models.AuthorUser,
-				AuthorId:       empl[0],
-				OrganizationId: org,
-				UserId:         empl[0],
-				Name:           "Test",
-				Description:    "Test bid",
-			})
-			if err != nil {
-				t.Fatal(err)
-			}
-			bids = append(bids, bid)
-		}
-	}
-	return bids
-}
+package repository
+
+import (
+	"context"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestBids(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	// Insert organizations and employees
+	employees := InsertTestInitData(t, repo.db)
+
+	// Insert tenders
+	tenders := AddAllTenders(t, repo, employees)
+
+	// Insert bids
+	bids := AddAllBids(t, ctx, repo, tenders, employees)
+
+	// Update
+	bids[0].Description = "Changed description"
+	repo.UpdateBid(ctx, bids[0], true)
+	bids[0].Version++
+
+	ubids, err := repo.GetBids(ctx, 1, 0, models.BidFilter{UserId: bids[0].AuthorId, TenderId: bids[0].TenderId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ubids) == 0 {
+		t.Error("Could not get updated bid")
+	}
+
+	history, err := repo.GetBidVersionHistory(ctx, bids[0].Id, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Version != 1 || history[1].Description != "Changed description" {
+		t.Errorf("Unexpected bid history: %v", history)
+	}
+
+	bids[0].Status = models.BidApproved
+	repo.UpdateBid(ctx, bids[0], false)
+
+	// Delete
+	for _, bid := range bids {
+		versions, err := repo.GetBidVersions(ctx, bid.Id, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) == 0 {
+			t.Errorf("No versions created for bid '%s' during test", bid.Id)
+		}
+		repo.DeleteBid(ctx, bid.Id)
+	}
+
+	bids, err = repo.GetBids(ctx, 0, 0, models.BidFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bids) > 0 {
+		t.Errorf("Expected to have %d bid entries, got %d", 0, len(bids))
+	}
+}
+
+func TestBidFilters(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	bids := AddAllBids(t, ctx, repo, tenders[:1], employees)
+	if len(bids) < 2 {
+		t.Fatalf("Expected bids of several organizations to be created, got %v", bids)
+	}
+
+	bids[0].Status = models.BidPublished
+	err := repo.UpdateBid(ctx, bids[0], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tenders[0].Id, Statuses: []models.BidStatus{models.BidPublished}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Id != bids[0].Id {
+		t.Fatalf("Expected single published bid, got %v", found)
+	}
+
+	found, err = repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tenders[0].Id, OrganizationId: bids[1].OrganizationId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Id != bids[1].Id {
+		t.Fatalf("Expected single bid of organization %s, got %v", bids[1].OrganizationId, found)
+	}
+
+	found, err = repo.GetBids(ctx, 0, 0, models.BidFilter{
+		TenderId: tenders[0].Id,
+		Updated:  models.TimeRange{From: &bids[0].UpdatedAt},
+		Sort:     []models.SortField{{Field: "createdAt", Desc: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) == 0 || found[len(found)-1].CreatedAt.After(found[0].CreatedAt) {
+		t.Fatalf("Expected recently updated bids in descending order of creation, got %v", found)
+	}
+}
+
+func AddAllBids(t *testing.T, ctx context.Context, repo *Repository, tenders []models.Tender, employees map[string][]string) []models.Bid {
+	var err error
+	var bids []models.Bid
+	var bid models.Bid
+	for _, tender := range tenders {
+		for org, empl := range employees {
+			bid, err = repo.AddBid(ctx, models.Bid{
+				TenderId:       tender.Id,
+				AuthorType:     models.AuthorUser,
+				AuthorId:       empl[0],
+				OrganizationId: org,
+				UserId:         empl[0],
+				Name:           "Test",
+				Description:    "Test bid",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			bids = append(bids, bid)
+		}
+	}
+	return bids
+}
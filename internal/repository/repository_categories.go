@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const categoryColumns = `
+		code,
+		parent_code,
+		names,
+		active,
+		created_at,
+		updated_at`
+
+func (repo *Repository) AddCategory(ctx context.Context, category models.Category) (models.Category, error) {
+	query := `
+	INSERT INTO service_categories (code, parent_code, names, active)
+	VALUES
+		($1, $2, $3, $4)
+	RETURNING` + categoryColumns
+
+	names, err := json.Marshal(category.Names)
+	if err != nil {
+		return category, fmt.Errorf("repository.Repository.AddCategory: %w", err)
+	}
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, category.Code, nullableCode(category.ParentCode), names, category.Active)
+	category, err = scanCategory(row)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return category, fmt.Errorf("repository.Repository.AddCategory: %w", models.ErrCategoryExists)
+		}
+		return category, fmt.Errorf("repository.Repository.AddCategory: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetCategories returns categories ordered by code, inactive ones are only included on request
+func (repo *Repository) GetCategories(ctx context.Context, includeInactive bool) ([]models.Category, error) {
+	query := `
+	SELECT` + categoryColumns + `
+	FROM service_categories
+	WHERE active OR $1
+	ORDER BY code
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetCategories: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetCategories: rows scan failed: %w", err)
+		}
+		result = append(result, category)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetCategories: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetCategory(ctx context.Context, code models.ServiceType) (models.Category, error) {
+	query := `
+	SELECT` + categoryColumns + `
+	FROM service_categories
+	WHERE code = $1
+	`
+
+	category, err := scanCategory(repo.conn(ctx).QueryRowContext(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return category, fmt.Errorf("repository.Repository.GetCategory: %w: %s", models.ErrNoCategory, code)
+		}
+		return category, fmt.Errorf("repository.Repository.GetCategory: %w", err)
+	}
+	return category, nil
+}
+
+func (repo *Repository) UpdateCategory(ctx context.Context, category models.Category) (models.Category, error) {
+	query := `
+	UPDATE service_categories
+	SET (parent_code, names, active, updated_at) = ($1, $2, $3, CURRENT_TIMESTAMP)
+	WHERE code = $4
+	RETURNING` + categoryColumns
+
+	names, err := json.Marshal(category.Names)
+	if err != nil {
+		return category, fmt.Errorf("repository.Repository.UpdateCategory: %w", err)
+	}
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, nullableCode(category.ParentCode), names, category.Active, category.Code)
+	updated, err := scanCategory(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return category, fmt.Errorf("repository.Repository.UpdateCategory: %w: %s", models.ErrNoCategory, category.Code)
+		}
+		return category, fmt.Errorf("repository.Repository.UpdateCategory: %w", err)
+	}
+	return updated, nil
+}
+
+//// Service
+
+func scanCategory(row rowScanner) (models.Category, error) {
+	var category models.Category
+	var parentCode sql.NullString
+	var names []byte
+
+	err := row.Scan(&category.Code, &parentCode, &names, &category.Active, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return category, err
+	}
+	category.ParentCode = models.ServiceType(parentCode.String)
+	err = json.Unmarshal(names, &category.Names)
+	return category, err
+}
+
+// nullableCode converts empty category code into NULL
+func nullableCode(code models.ServiceType) interface{} {
+	if len(code) == 0 {
+		return nil
+	}
+	return string(code)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestCategories(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	// predefined service types are seeded as top level categories
+	categories, err := repo.GetCategories(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != len(AllServiceTypes()) {
+		t.Fatalf("Expected predefined categories only, got %v", categories)
+	}
+	for _, category := range categories {
+		if len(category.ParentCode) > 0 || !category.Active || len(category.Names["en"]) == 0 {
+			t.Errorf("Expected active top level category named in english, got %v", category)
+		}
+	}
+
+	freight, err := repo.AddCategory(ctx, models.Category{Code: "Freight", ParentCode: models.STDelivery, Names: map[string]string{"en": "Freight"}, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rail, err := repo.AddCategory(ctx, models.Category{Code: "Rail", ParentCode: freight.Code, Names: map[string]string{"en": "Rail", "ru": "Ж/д"}, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rail.ParentCode != freight.Code || rail.Names["ru"] != "Ж/д" {
+		t.Errorf("Unexpected category stored: %v", rail)
+	}
+
+	_, err = repo.AddCategory(ctx, models.Category{Code: "Rail", Names: map[string]string{"en": "Rail"}})
+	if !errors.Is(err, models.ErrCategoryExists) {
+		t.Errorf("Expected ErrCategoryExists on duplicate code, got %v", err)
+	}
+	_, err = repo.GetCategory(ctx, "Unknown")
+	if !errors.Is(err, models.ErrNoCategory) {
+		t.Errorf("Expected ErrNoCategory on unknown code, got %v", err)
+	}
+
+	// tenders of descendant categories are matched by ancestor
+	var tender models.Tender
+	for _, tender = range tenders {
+		if tender.ServiceType == models.STConstruction {
+			break
+		}
+	}
+	tender.ServiceType = rail.Code
+	err = repo.UpdateTender(ctx, tender, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []models.ServiceType{models.STDelivery, freight.Code, rail.Code} {
+		found, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{ServiceTypes: []models.ServiceType{code}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		matched := false
+		for _, f := range found {
+			matched = matched || f.Id == tender.Id
+			if f.ServiceType != models.STDelivery && f.ServiceType != freight.Code && f.ServiceType != rail.Code {
+				t.Errorf("Tender of category %s is matched by %s", f.ServiceType, code)
+			}
+		}
+		if !matched {
+			t.Errorf("Tender of category %s is not matched by %s", rail.Code, code)
+		}
+	}
+
+	// inactive categories are only listed on request
+	rail.Active = false
+	rail.Names = map[string]string{"en": "Railway"}
+	rail, err = repo.UpdateCategory(ctx, rail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rail.Active || rail.Names["en"] != "Railway" || len(rail.Names) != 1 {
+		t.Errorf("Unexpected category after update: %v", rail)
+	}
+	categories, err = repo.GetCategories(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != len(AllServiceTypes())+1 {
+		t.Errorf("Expected inactive category not to be listed, got %v", categories)
+	}
+	categories, err = repo.GetCategories(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != len(AllServiceTypes())+2 {
+		t.Errorf("Expected inactive category to be listed on request, got %v", categories)
+	}
+
+	_, err = repo.UpdateCategory(ctx, models.Category{Code: "Unknown", Names: map[string]string{"en": "Unknown"}})
+	if !errors.Is(err, models.ErrNoCategory) {
+		t.Errorf("Expected ErrNoCategory on update of unknown code, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+// AddUser inserts new employee, passwordHash may be empty for employees without password
+func (repo *Repository) AddUser(ctx context.Context, user models.User, passwordHash string) (models.User, error) {
+	query := `
+	INSERT INTO employee (username, first_name, last_name, password_hash)
+	VALUES
+		($1, $2, $3, $4)
+	RETURNING
+		id, created_at, updated_at
+	`
+
+	var hash interface{}
+	if len(passwordHash) > 0 {
+		hash = passwordHash
+	}
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, user.Username, user.FirstName, user.LastName, hash)
+	err := row.Scan(&user.Id, &user.CreatedAt, &user.UpdatedAt)
+	if isUniqueViolation(err) {
+		return user, fmt.Errorf("repository.Repository.AddUser: %w: %s", models.ErrUsernameTaken, user.Username)
+	} else if err != nil {
+		return user, fmt.Errorf("repository.Repository.AddUser: %w", err)
+	}
+
+	return user, nil
+}
+
+func (repo *Repository) GetUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
+	query := `
+	SELECT
+		id,
+		username,
+		COALESCE(first_name, ''),
+		COALESCE(last_name, ''),
+		created_at,
+		updated_at
+	FROM employee
+	ORDER BY username
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetUsers: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.User
+	var user models.User
+	for rows.Next() {
+		err = rows.Scan(&user.Id, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetUsers: row scan failed: %w", err)
+		}
+		result = append(result, user)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetUsers: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// UpdateUser updates username and names of employee, false is returned if employee does not exist
+func (repo *Repository) UpdateUser(ctx context.Context, user models.User) (bool, error) {
+	query := `
+	UPDATE employee
+	SET (username, first_name, last_name, updated_at) =
+	($1, $2, $3, CURRENT_TIMESTAMP)
+	WHERE id = $4
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, user.Username, user.FirstName, user.LastName, user.Id)
+	if isUniqueViolation(err) {
+		return false, fmt.Errorf("repository.Repository.UpdateUser: %w: %s", models.ErrUsernameTaken, user.Username)
+	} else if err != nil {
+		return false, fmt.Errorf("repository.Repository.UpdateUser: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.UpdateUser: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DeleteUser deletes employee along with their memberships, tenders and bids they authored are kept without author
+func (repo *Repository) DeleteUser(ctx context.Context, userId string) (bool, error) {
+	res, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM employee WHERE id = $1", userId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteUser: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteUser: %w", err)
+	}
+	return n > 0, nil
+}
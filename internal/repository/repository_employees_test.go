@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestEmployees(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	InsertTestInitData(t, repo.db)
+
+	user, err := repo.AddUser(ctx, models.User{Username: "Employee", FirstName: "First", LastName: "Last"}, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, ok, err := repo.UserByUUID(ctx, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || found.Username != user.Username || found.FirstName != user.FirstName || found.LastName != user.LastName {
+		t.Errorf("Found user does not match added one: expected %v, got %v", user, found)
+	}
+
+	hash, err := repo.UserPasswordHash(ctx, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "hash" {
+		t.Errorf("Expected password hash 'hash', got '%s'", hash)
+	}
+
+	_, err = repo.AddUser(ctx, models.User{Username: "Employee"}, "")
+	if !errors.Is(err, models.ErrUsernameTaken) {
+		t.Errorf("Expected models.ErrUsernameTaken for duplicate username, got %v", err)
+	}
+
+	users, err := repo.GetUsers(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 4 {
+		t.Errorf("Expected to get 4 users, got %d", len(users))
+	}
+
+	users, err = repo.GetUsers(ctx, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Errorf("Expected to get 1 user with offset 3, got %d", len(users))
+	}
+
+	// Update
+	user.Username = "Renamed"
+	ok, err = repo.UpdateUser(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Expected user '%s' to be updated", user.Id)
+	}
+
+	_, ok, err = repo.UserByUsername(ctx, "Renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Expected to find renamed user")
+	}
+
+	user.Username = "Test1"
+	_, err = repo.UpdateUser(ctx, user)
+	if !errors.Is(err, models.ErrUsernameTaken) {
+		t.Errorf("Expected models.ErrUsernameTaken for duplicate username, got %v", err)
+	}
+
+	// Delete
+	ok, err = repo.DeleteUser(ctx, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Expected user '%s' to be deleted", user.Id)
+	}
+
+	ok, err = repo.DeleteUser(ctx, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("Expected user '%s' not to be deleted twice", user.Id)
+	}
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	var org, user string
+	for o, users := range employees {
+		org, user = o, users[0]
+		break
+	}
+
+	tender, err := repo.AddTender(ctx, models.Tender{
+		Name:           "Authored tender",
+		Status:         models.TenderPublished,
+		ServiceType:    models.STConstruction,
+		OrganizationId: org,
+		Author:         user,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bid, err := repo.AddBid(ctx, models.Bid{
+		TenderId:       tender.Id,
+		AuthorType:     models.AuthorUser,
+		AuthorId:       user,
+		OrganizationId: org,
+		UserId:         user,
+		Name:           "Authored bid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := repo.DeleteUser(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("Expected user '%s' to be deleted", user)
+	}
+
+	// tender, bid and their history belong to organization and survive their author
+	tender, err = repo.GetTenderByUUID(ctx, tender.Id, nil)
+	if err != nil {
+		t.Fatalf("Expected tender to survive deletion of its author, got %v", err)
+	}
+	if len(tender.Author) > 0 || tender.OrganizationId != org {
+		t.Errorf("Expected tender to be kept without author, got %v", tender)
+	}
+	versions, err := repo.GetTenderVersions(ctx, tender.Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) == 0 {
+		t.Error("Expected versions of tender to survive deletion of its author")
+	}
+
+	bid, err = repo.GetBidByUUID(ctx, bid.Id)
+	if err != nil {
+		t.Fatalf("Expected bid to survive deletion of its author, got %v", err)
+	}
+	if len(bid.UserId) > 0 || bid.AuthorType != models.AuthorOrganization || bid.AuthorId != org {
+		t.Errorf("Expected bid to be kept on behalf of organization, got %v", bid)
+	}
+	bidVersions, err := repo.GetBidVersions(ctx, bid.Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bidVersions) == 0 {
+		t.Error("Expected versions of bid to survive deletion of its author")
+	}
+
+	err = repo.DeleteTender(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
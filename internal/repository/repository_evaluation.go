@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+//// Criteria
+
+// SetTenderCriteria replaces evaluation criteria of tender, criteria are kept in provided order
+func (repo *Repository) SetTenderCriteria(ctx context.Context, tenderId string, criteria []models.Criterion) ([]models.Criterion, error) {
+	query := `
+	INSERT INTO tender_criteria (tender_id, position, name, description, weight)
+	VALUES
+		($1, $2, $3, $4, $5)
+	RETURNING
+		id, created_at
+	`
+
+	result := make([]models.Criterion, 0, len(criteria))
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM tender_criteria WHERE tender_id = $1", tenderId)
+		if err != nil {
+			return err
+		}
+
+		for i, c := range criteria {
+			c.TenderId = tenderId
+			row := repo.conn(ctx).QueryRowContext(ctx, query, tenderId, i, c.Name, c.Description, c.Weight)
+			err = row.Scan(&c.Id, &c.CreatedAt)
+			if err != nil {
+				return err
+			}
+			result = append(result, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.SetTenderCriteria: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetTenderCriteria(ctx context.Context, tenderId string) ([]models.Criterion, error) {
+	query := `
+	SELECT
+		id, tender_id, name, description, weight, created_at
+	FROM tender_criteria
+	WHERE tender_id = $1
+	ORDER BY position
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderCriteria: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Criterion{}
+	for rows.Next() {
+		var c models.Criterion
+		err = rows.Scan(&c.Id, &c.TenderId, &c.Name, &c.Description, &c.Weight, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenderCriteria: rows scan failed: %w", err)
+		}
+		result = append(result, c)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderCriteria: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// TenderScored reports whether any bid of tender is scored by any criterion
+func (repo *Repository) TenderScored(ctx context.Context, tenderId string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM proposal_scores
+			JOIN tender_criteria ON (tender_criteria.id = proposal_scores.criterion_id)
+		WHERE tender_criteria.tender_id = $1
+	)
+	`
+
+	var scored bool
+	err := repo.conn(ctx).QueryRowContext(ctx, query, tenderId).Scan(&scored)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.TenderScored: %w", err)
+	}
+	return scored, nil
+}
+
+//// Scores
+
+// SetBidScores saves scores of bid given by evaluator, previous scores by same criteria are replaced
+func (repo *Repository) SetBidScores(ctx context.Context, bidId, userId string, scores []models.BidScore) ([]models.BidScore, error) {
+	query := `
+	INSERT INTO proposal_scores
+		(proposal_id, criterion_id, user_id, score, comment, updated_at)
+	VALUES
+		($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+	ON CONFLICT (proposal_id, criterion_id, user_id) DO UPDATE SET (score, comment, updated_at) = ($4, $5, CURRENT_TIMESTAMP)
+	RETURNING
+		created_at, updated_at
+	`
+
+	result := make([]models.BidScore, 0, len(scores))
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		for _, score := range scores {
+			score.BidId, score.UserId = bidId, userId
+			row := repo.conn(ctx).QueryRowContext(ctx, query, bidId, score.CriterionId, userId, score.Score, score.Comment)
+			err := row.Scan(&score.CreatedAt, &score.UpdatedAt)
+			if err != nil {
+				return err
+			}
+			result = append(result, score)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.SetBidScores: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetBidScores returns scores of bid, only ones given by evaluator if userId is not empty
+func (repo *Repository) GetBidScores(ctx context.Context, bidId, userId string) ([]models.BidScore, error) {
+	query := `
+	SELECT
+		proposal_scores.proposal_id,
+		proposal_scores.criterion_id,
+		proposal_scores.user_id,
+		proposal_scores.score,
+		proposal_scores.comment,
+		proposal_scores.created_at,
+		proposal_scores.updated_at
+	FROM proposal_scores
+		JOIN tender_criteria ON (tender_criteria.id = proposal_scores.criterion_id)
+	WHERE proposal_scores.proposal_id = $1 AND ($2 = '' OR proposal_scores.user_id::text = $2)
+	ORDER BY tender_criteria.position, proposal_scores.created_at
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, bidId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidScores: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.BidScore{}
+	for rows.Next() {
+		var s models.BidScore
+		err = rows.Scan(&s.BidId, &s.CriterionId, &s.UserId, &s.Score, &s.Comment, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetBidScores: rows scan failed: %w", err)
+		}
+		result = append(result, s)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetBidScores: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// GetScoreResults returns average scores of each bid of tender by each criterion, canceled bids are skipped.
+// Returned rankings are not ranked yet, see models.RankBids
+func (repo *Repository) GetScoreResults(ctx context.Context, tenderId string) ([]models.BidRanking, error) {
+	query := `
+	SELECT
+		proposals.id,
+		proposals.name,
+		tender_criteria.id,
+		tender_criteria.weight,
+		COALESCE(AVG(proposal_scores.score), 0)::float8,
+		COUNT(proposal_scores.score)
+	FROM proposals
+		JOIN tender_criteria ON (tender_criteria.tender_id = proposals.tender_id)
+		LEFT JOIN proposal_scores ON (proposal_scores.proposal_id = proposals.id AND proposal_scores.criterion_id = tender_criteria.id)
+	WHERE proposals.tender_id = $1 AND proposals.status <> 'Canceled'
+	GROUP BY proposals.id, proposals.name, tender_criteria.id, tender_criteria.weight, tender_criteria.position
+	ORDER BY proposals.name, proposals.id, tender_criteria.position
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetScoreResults: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.BidRanking{}
+	for rows.Next() {
+		var bidId, bidName string
+		var c models.CriterionResult
+		err = rows.Scan(&bidId, &bidName, &c.CriterionId, &c.Weight, &c.Average, &c.Evaluations)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetScoreResults: rows scan failed: %w", err)
+		}
+
+		if len(result) == 0 || result[len(result)-1].BidId != bidId {
+			result = append(result, models.BidRanking{BidId: bidId, BidName: bidName})
+		}
+		last := &result[len(result)-1]
+		last.Criteria = append(last.Criteria, c)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetScoreResults: %w", rows.Err())
+	}
+
+	return result, nil
+}
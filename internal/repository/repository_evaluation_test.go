@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestEvaluation(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	bids := AddAllBids(t, ctx, repo, tenders, employees)
+
+	var tenderBids []models.Bid
+	for _, bid := range bids {
+		if bid.TenderId == tenders[0].Id {
+			tenderBids = append(tenderBids, bid)
+		}
+	}
+	if len(tenderBids) < 2 {
+		t.Fatalf("Expected at least 2 bids of tender '%s', got %d", tenders[0].Id, len(tenderBids))
+	}
+
+	criteria, err := repo.SetTenderCriteria(ctx, tenders[0].Id, []models.Criterion{
+		{Name: "Price", Weight: 3},
+		{Name: "Quality", Description: "Quality of materials", Weight: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repo.GetTenderCriteria(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 || stored[0].Name != "Price" || stored[1].Id != criteria[1].Id {
+		t.Fatalf("Expected criteria to be stored in order, got %v", stored)
+	}
+
+	scored, err := repo.TenderScored(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scored {
+		t.Errorf("Tender without scores is reported as scored")
+	}
+
+	// two evaluators score first bid by price, one of them changes their mind
+	var evaluators []string
+	for _, users := range employees {
+		evaluators = append(evaluators, users...)
+	}
+	price, quality := criteria[0].Id, criteria[1].Id
+	scores := map[string][]models.BidScore{
+		evaluators[0]: {{CriterionId: price, Score: 2}, {CriterionId: quality, Score: 0}},
+		evaluators[1]: {{CriterionId: price, Score: 6}},
+	}
+	for userId, s := range scores {
+		_, err = repo.SetBidScores(ctx, tenderBids[0].Id, userId, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = repo.SetBidScores(ctx, tenderBids[0].Id, evaluators[0], []models.BidScore{{CriterionId: price, Score: 10, Comment: "Cheapest"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.SetBidScores(ctx, tenderBids[1].Id, evaluators[0], []models.BidScore{{CriterionId: price, Score: 5}, {CriterionId: quality, Score: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	own, err := repo.GetBidScores(ctx, tenderBids[0].Id, evaluators[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(own) != 2 || own[0].Score != 10 || own[0].Comment != "Cheapest" {
+		t.Errorf("Expected replaced price score of evaluator, got %v", own)
+	}
+	all, err := repo.GetBidScores(ctx, tenderBids[0].Id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected 3 scores of bid, got %d", len(all))
+	}
+
+	// first bid: (8 * 3 + 0 * 1) / 4 = 6, second one: (5 * 3 + 10 * 1) / 4 = 6.25
+	results, err := repo.GetScoreResults(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(tenderBids) {
+		t.Fatalf("Expected results of %d bids, got %d", len(tenderBids), len(results))
+	}
+	ranking := models.RankBids(results)
+	if ranking[0].BidId != tenderBids[1].Id || ranking[0].Score != 6.25 || ranking[1].BidId != tenderBids[0].Id || ranking[1].Score != 6 {
+		t.Errorf("Expected bids to be ranked with scores 6.25 and 6, got %v", ranking)
+	}
+
+	scored, err = repo.TenderScored(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !scored {
+		t.Errorf("Tender with scores is not reported as scored")
+	}
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"tenders/internal/models"
+	"time"
+)
+
+// invitationStatus reports pending invitations past their expiry time as expired
+const invitationStatus = `CASE WHEN status = 'pending' AND expires_at < CURRENT_TIMESTAMP THEN 'expired' ELSE status::text END`
+
+const invitationColumns = `
+	id,
+	organization_id,
+	username,
+	role,
+	` + invitationStatus + `,
+	invited_by,
+	token_hash,
+	created_at,
+	expires_at,
+	responded_at
+`
+
+// AddInvitation inserts pending invitation expiring after ttl
+func (repo *Repository) AddInvitation(ctx context.Context, inv models.Invitation, ttl time.Duration) (models.Invitation, error) {
+	query := `
+	INSERT INTO organization_invitations (organization_id, username, role, token_hash, invited_by, expires_at)
+	VALUES
+		($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + $6::float8 * INTERVAL '1 second')
+	RETURNING` + invitationColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, inv.OrganizationId, inv.Username, inv.Role, inv.TokenHash, nullableUUID(inv.InvitedBy), int64(ttl.Seconds()))
+	inv, err := scanInvitation(row)
+	if err != nil {
+		return inv, fmt.Errorf("repository.Repository.AddInvitation: %w", err)
+	}
+	return inv, nil
+}
+
+// GetInvitations lists invitations of organization, empty status means any
+func (repo *Repository) GetInvitations(ctx context.Context, organizationId string, status models.InvitationStatus, limit, offset int) ([]models.Invitation, error) {
+	query := `
+	SELECT` + invitationColumns + `
+	FROM organization_invitations
+	WHERE organization_id = $3 AND ($4 = '' OR ` + invitationStatus + ` = $4)
+	ORDER BY created_at DESC
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, organizationId, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetInvitations: %w", err)
+	}
+	return collectInvitations(rows, "repository.Repository.GetInvitations")
+}
+
+// GetUserInvitations lists pending invitations addressed to username
+func (repo *Repository) GetUserInvitations(ctx context.Context, username string) ([]models.Invitation, error) {
+	query := `
+	SELECT` + invitationColumns + `
+	FROM organization_invitations
+	WHERE username = $1 AND status = 'pending' AND expires_at >= CURRENT_TIMESTAMP
+	ORDER BY created_at DESC
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, username)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetUserInvitations: %w", err)
+	}
+	return collectInvitations(rows, "repository.Repository.GetUserInvitations")
+}
+
+func (repo *Repository) InvitationByTokenHash(ctx context.Context, hash string) (models.Invitation, error) {
+	query := `
+	SELECT` + invitationColumns + `
+	FROM organization_invitations
+	WHERE token_hash = $1
+	`
+
+	inv, err := scanInvitation(repo.conn(ctx).QueryRowContext(ctx, query, hash))
+	if err != nil {
+		return inv, fmt.Errorf("repository.Repository.InvitationByTokenHash: %w", err)
+	}
+	return inv, nil
+}
+
+// PendingInvitationExists reports whether username has unexpired pending invitation to organization
+func (repo *Repository) PendingInvitationExists(ctx context.Context, organizationId, username string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM organization_invitations
+		WHERE organization_id = $1 AND username = $2 AND status = 'pending' AND expires_at >= CURRENT_TIMESTAMP
+	)
+	`
+
+	var exists bool
+	err := repo.conn(ctx).QueryRowContext(ctx, query, organizationId, username).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.PendingInvitationExists: %w", err)
+	}
+	return exists, nil
+}
+
+// RespondInvitation closes unexpired pending invitation with accepted or declined status, accepted
+// invitation makes userId member of organization. False is returned if invitation is not pending anymore
+func (repo *Repository) RespondInvitation(ctx context.Context, inv models.Invitation, status models.InvitationStatus, userId string) (bool, error) {
+	query := `
+	UPDATE organization_invitations
+	SET (status, responded_at) = ($1, CURRENT_TIMESTAMP)
+	WHERE id = $2 AND status = 'pending' AND expires_at >= CURRENT_TIMESTAMP
+	`
+
+	responded := false
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		res, err := repo.conn(ctx).ExecContext(ctx, query, status, inv.Id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+		responded = true
+
+		if status != models.InvitationAccepted {
+			return nil
+		}
+
+		// employee could have been added to organization by other invitation
+		_, err = repo.conn(ctx).ExecContext(ctx, `
+		INSERT INTO organization_responsible (organization_id, user_id, role)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1 FROM organization_responsible WHERE organization_id = $1 AND user_id = $2
+		)
+		`, inv.OrganizationId, userId, inv.Role)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RespondInvitation: %w", err)
+	}
+	return responded, nil
+}
+
+// RevokeInvitation revokes pending invitation, false is returned if there is no such pending invitation
+func (repo *Repository) RevokeInvitation(ctx context.Context, organizationId, invitationId string) (bool, error) {
+	query := `
+	UPDATE organization_invitations
+	SET status = 'revoked'
+	WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, invitationId, organizationId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RevokeInvitation: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RevokeInvitation: %w", err)
+	}
+	return n > 0, nil
+}
+
+//// Service
+
+func collectInvitations(rows *sql.Rows, caller string) ([]models.Invitation, error) {
+	defer rows.Close()
+
+	var result []models.Invitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: rows scan failed: %w", caller, err)
+		}
+		result = append(result, inv)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("%s: %w", caller, rows.Err())
+	}
+
+	return result, nil
+}
+
+func scanInvitation(row rowScanner) (models.Invitation, error) {
+	var inv models.Invitation
+	var invitedBy interface{}
+
+	err := row.Scan(&inv.Id, &inv.OrganizationId, &inv.Username, &inv.Role, &inv.Status, &invitedBy, &inv.TokenHash, &inv.CreatedAt, &inv.ExpiresAt, &inv.RespondedAt)
+	if err != nil {
+		return inv, err
+	}
+	inv.InvitedBy = readUUID(invitedBy)
+	return inv, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+	"time"
+)
+
+func TestInvitations(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+
+	user, err := repo.AddUser(ctx, models.User{Username: "Invitee"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for org, empl := range employees {
+		inv, err := repo.AddInvitation(ctx, models.Invitation{
+			OrganizationId: org,
+			Username:       user.Username,
+			Role:           models.RoleEditor,
+			InvitedBy:      empl[0],
+			TokenHash:      "accept " + org,
+		}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inv.Status != models.InvitationPending || inv.InvitedBy != empl[0] {
+			t.Errorf("Unexpected added invitation: %v", inv)
+		}
+
+		exists, err := repo.PendingInvitationExists(ctx, org, user.Username)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Errorf("Expected pending invitation to exist")
+		}
+
+		found, err := repo.InvitationByTokenHash(ctx, "accept "+org)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Id != inv.Id {
+			t.Errorf("Found invitation does not match added one: expected %v, got %v", inv, found)
+		}
+
+		// accept
+		ok, err := repo.RespondInvitation(ctx, inv, models.InvitationAccepted, user.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("Expected invitation '%s' to be accepted", inv.Id)
+		}
+		role, member, err := repo.UserRole(ctx, user.Id, org)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !member || role != models.RoleEditor {
+			t.Errorf("Expected accepted invitation to make user editor of organization, got '%s'", role)
+		}
+
+		ok, err = repo.RespondInvitation(ctx, inv, models.InvitationDeclined, user.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Errorf("Expected accepted invitation '%s' not to be declined", inv.Id)
+		}
+
+		// expired invitation can not be accepted
+		inv, err = repo.AddInvitation(ctx, models.Invitation{
+			OrganizationId: org,
+			Username:       "Late",
+			Role:           models.RoleViewer,
+			TokenHash:      "expired " + org,
+		}, -time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inv.Status != models.InvitationExpired {
+			t.Errorf("Expected invitation to be expired, got '%s'", inv.Status)
+		}
+
+		ok, err = repo.RespondInvitation(ctx, inv, models.InvitationAccepted, user.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Errorf("Expected expired invitation '%s' not to be accepted", inv.Id)
+		}
+
+		invs, err := repo.GetInvitations(ctx, org, models.InvitationExpired, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(invs) != 1 || invs[0].Id != inv.Id {
+			t.Errorf("Expected to get 1 expired invitation, got %v", invs)
+		}
+
+		invs, err = repo.GetInvitations(ctx, org, "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(invs) != 2 {
+			t.Errorf("Expected to get 2 invitations, got %d", len(invs))
+		}
+
+		// revoke
+		inv, err = repo.AddInvitation(ctx, models.Invitation{
+			OrganizationId: org,
+			Username:       "Revoked",
+			Role:           models.RoleViewer,
+			TokenHash:      "revoked " + org,
+		}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err = repo.RevokeInvitation(ctx, org, inv.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("Expected invitation '%s' to be revoked", inv.Id)
+		}
+
+		ok, err = repo.RevokeInvitation(ctx, org, inv.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Errorf("Expected invitation '%s' not to be revoked twice", inv.Id)
+		}
+	}
+
+	invs, err := repo.GetUserInvitations(ctx, user.Username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invs) != 0 {
+		t.Errorf("Expected user to have no pending invitations, got %d", len(invs))
+	}
+
+	_, err = repo.InvitationByTokenHash(ctx, "none")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for missing invitation, got %v", err)
+	}
+}
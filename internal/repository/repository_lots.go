@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const lotColumns = `
+		id,
+		tender_id,
+		name,
+		description,
+		quantity,
+		budget_amount,
+		budget_currency,
+		status,
+		awarded_bid_id,
+		created_at,
+		updated_at`
+
+// SetTenderLots replaces lots of tender, lots are kept in provided order
+func (repo *Repository) SetTenderLots(ctx context.Context, tenderId string, lots []models.Lot) ([]models.Lot, error) {
+	query := `
+	INSERT INTO tender_lots (tender_id, position, name, description, quantity, budget_amount, budget_currency)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7)
+	RETURNING` + lotColumns
+
+	result := make([]models.Lot, 0, len(lots))
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM tender_lots WHERE tender_id = $1", tenderId)
+		if err != nil {
+			return err
+		}
+
+		for i, lot := range lots {
+			amount, currency := moneyParams(lot.Budget)
+			row := repo.conn(ctx).QueryRowContext(ctx, query, tenderId, i, lot.Name, lot.Description, lot.Quantity, amount, currency)
+			lot, err = scanLot(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, lot)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.SetTenderLots: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetTenderLots(ctx context.Context, tenderId string) ([]models.Lot, error) {
+	query := `
+	SELECT` + lotColumns + `
+	FROM tender_lots
+	WHERE tender_id = $1
+	ORDER BY position
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderLots: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Lot{}
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenderLots: rows scan failed: %w", err)
+		}
+		result = append(result, lot)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderLots: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// UpdateLotStatus changes status of lot along with bid it is awarded to
+func (repo *Repository) UpdateLotStatus(ctx context.Context, lot models.Lot) error {
+	query := `
+	UPDATE tender_lots
+	SET (status, awarded_bid_id, updated_at) = ($1, $2, CURRENT_TIMESTAMP)
+	WHERE id = $3
+	`
+
+	_, err := repo.conn(ctx).ExecContext(ctx, query, lot.Status, nullableUUID(lot.AwardedBidId), lot.Id)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.UpdateLotStatus: %w", err)
+	}
+	return nil
+}
+
+// TenderHasBids reports whether any bid is submitted for tender, including canceled ones
+func (repo *Repository) TenderHasBids(ctx context.Context, tenderId string) (bool, error) {
+	var exists bool
+	err := repo.conn(ctx).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM proposals WHERE tender_id = $1)", tenderId).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.TenderHasBids: %w", err)
+	}
+	return exists, nil
+}
+
+//// Service
+
+func scanLot(row rowScanner) (models.Lot, error) {
+	var lot models.Lot
+	var amount, currency sql.NullString
+	var awardedBidId interface{}
+
+	err := row.Scan(&lot.Id, &lot.TenderId, &lot.Name, &lot.Description, &lot.Quantity, &amount, &currency,
+		&lot.Status, &awardedBidId, &lot.CreatedAt, &lot.UpdatedAt)
+	if err != nil {
+		return lot, err
+	}
+	lot.AwardedBidId = readUUID(awardedBidId)
+	lot.Budget, err = readMoney(amount, currency)
+	return lot, err
+}
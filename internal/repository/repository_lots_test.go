@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestLots(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	submitted, err := repo.TenderHasBids(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submitted {
+		t.Errorf("Tender without bids is reported to have some")
+	}
+
+	lots, err := repo.SetTenderLots(ctx, tenders[0].Id, []models.Lot{
+		{Name: "Cement", Quantity: 100, Budget: &models.Money{Amount: "1500.5", Currency: "RUB"}},
+		{Name: "Bricks", Description: "Red ones", Quantity: 5000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repo.GetTenderLots(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 || stored[0].Id != lots[0].Id || stored[1].Name != "Bricks" || stored[0].Status != models.LotOpen {
+		t.Fatalf("Expected open lots to be stored in order, got %v", stored)
+	}
+	if stored[0].Budget == nil || stored[0].Budget.Amount != "1500.5" || stored[1].Budget != nil {
+		t.Errorf("Expected only first lot to have budget, got %v", stored)
+	}
+
+	// bids are listed by lots they are submitted for
+	var userId, orgId string
+	for org, users := range employees {
+		orgId, userId = org, users[0]
+	}
+	var bids []models.Bid
+	for _, lotIds := range [][]string{{lots[0].Id}, {lots[0].Id, lots[1].Id}} {
+		bid, err := repo.AddBid(ctx, models.Bid{
+			TenderId:       tenders[0].Id,
+			AuthorType:     models.AuthorUser,
+			AuthorId:       userId,
+			OrganizationId: orgId,
+			UserId:         userId,
+			Name:           "Lots",
+			LotIds:         lotIds,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bids = append(bids, bid)
+	}
+	listed, err := repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tenders[0].Id, LotId: lots[1].Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].Id != bids[1].Id || !slices.Equal(listed[0].LotIds, bids[1].LotIds) {
+		t.Errorf("Expected only second bid to be listed for second lot, got %v", listed)
+	}
+
+	// lots are versioned with the rest of bid
+	bids[1].LotIds = bids[1].LotIds[1:]
+	err = repo.UpdateBid(ctx, bids[1], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := repo.GetBidVersions(ctx, bids[1].Id, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || len(versions[0].LotIds) != 2 {
+		t.Errorf("Expected first version of bid to keep both lots, got %v", versions)
+	}
+
+	submitted, err = repo.TenderHasBids(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !submitted {
+		t.Errorf("Tender with bids is reported to have none")
+	}
+
+	// votes are counted per lot
+	err = repo.AddBidApproval(ctx, bids[0].Id, lots[0].Id, userId, models.ATApprove)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.AddBidApproval(ctx, bids[0].Id, lots[0].Id, userId, models.ATReject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, err := repo.ApprovalCounts(ctx, bids[0].Id, lots[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts[models.ATReject] != 1 || counts[models.ATApprove] != 0 {
+		t.Errorf("Expected vote for lot to be replaced, got %v", counts)
+	}
+	counts, err = repo.ApprovalCounts(ctx, bids[0].Id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("Expected no votes for bid without lot, got %v", counts)
+	}
+
+	lots[0].Status = models.LotAwarded
+	lots[0].AwardedBidId = bids[1].Id
+	err = repo.UpdateLotStatus(ctx, lots[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err = repo.GetTenderLots(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored[0].Status != models.LotAwarded || stored[0].AwardedBidId != bids[1].Id {
+		t.Errorf("Expected first lot to be awarded to second bid, got %v", stored[0])
+	}
+	if models.LotsSettled(stored) {
+		t.Errorf("Lots are reported settled while one of them is open")
+	}
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+// AddOrganization inserts organization and makes adminId its first administrator
+func (repo *Repository) AddOrganization(ctx context.Context, org models.Organization, adminId string) (models.Organization, error) {
+	query := `
+	INSERT INTO organization (name, description, type)
+	VALUES
+		($1, $2, $3)
+	RETURNING
+		id, created_at, updated_at
+	`
+
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		row := repo.conn(ctx).QueryRowContext(ctx, query, org.Name, org.Description, org.Type)
+		err := row.Scan(&org.Id, &org.CreatedAt, &org.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.conn(ctx).ExecContext(ctx, "INSERT INTO organization_responsible (organization_id, user_id, role) VALUES ($1, $2, $3)", org.Id, adminId, models.RoleAdmin)
+		return err
+	})
+	if err != nil {
+		return org, fmt.Errorf("repository.Repository.AddOrganization: %w", err)
+	}
+
+	return org, nil
+}
+
+func (repo *Repository) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	query := `
+	SELECT
+		id,
+		name,
+		COALESCE(description, ''),
+		COALESCE(type::text, ''),
+		created_at,
+		updated_at
+	FROM organization
+	ORDER BY name
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetOrganizations: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Organization
+	var org models.Organization
+	for rows.Next() {
+		err = rows.Scan(&org.Id, &org.Name, &org.Description, &org.Type, &org.CreatedAt, &org.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetOrganizations: row scan failed: %w", err)
+		}
+		result = append(result, org)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetOrganizations: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// UpdateOrganization updates name, description and type of organization, false is returned if it does not exist
+func (repo *Repository) UpdateOrganization(ctx context.Context, org models.Organization) (bool, error) {
+	query := `
+	UPDATE organization
+	SET (name, description, type, updated_at) =
+	($1, $2, $3, CURRENT_TIMESTAMP)
+	WHERE id = $4
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, org.Name, org.Description, org.Type, org.Id)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.UpdateOrganization: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.UpdateOrganization: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DeleteOrganization deletes organization along with its memberships, tenders, bids and api keys
+func (repo *Repository) DeleteOrganization(ctx context.Context, organizationId string) (bool, error) {
+	res, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM organization WHERE id = $1", organizationId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteOrganization: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteOrganization: %w", err)
+	}
+	return n > 0, nil
+}
+
+//// Members
+
+func (repo *Repository) GetMembers(ctx context.Context, organizationId string, limit, offset int) ([]models.Member, error) {
+	query := `
+	SELECT
+		e.id,
+		e.username,
+		COALESCE(e.first_name, ''),
+		COALESCE(e.last_name, ''),
+		e.created_at,
+		e.updated_at,
+		r.role
+	FROM organization_responsible AS r
+	JOIN employee AS e ON e.id = r.user_id
+	WHERE r.organization_id = $3
+	ORDER BY e.username
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetMembers: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Member
+	var m models.Member
+	for rows.Next() {
+		err = rows.Scan(&m.Id, &m.Username, &m.FirstName, &m.LastName, &m.CreatedAt, &m.UpdatedAt, &m.Role)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetMembers: row scan failed: %w", err)
+		}
+		result = append(result, m)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetMembers: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// AddMember adds employee to organization with provided role, models.ErrAlreadyMember is returned
+// if employee is already its member
+func (repo *Repository) AddMember(ctx context.Context, organizationId, userId string, role models.Role) error {
+	query := `
+	INSERT INTO organization_responsible (organization_id, user_id, role)
+	SELECT $1, $2, $3
+	WHERE NOT EXISTS (
+		SELECT 1 FROM organization_responsible WHERE organization_id = $1 AND user_id = $2
+	)
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, organizationId, userId, role)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.AddMember: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("repository.Repository.AddMember: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("repository.Repository.AddMember: %w", models.ErrAlreadyMember)
+	}
+	return nil
+}
+
+// SetMemberRole changes role of organization member, false is returned if employee is not its member
+func (repo *Repository) SetMemberRole(ctx context.Context, organizationId, userId string, role models.Role) (bool, error) {
+	res, err := repo.conn(ctx).ExecContext(ctx, "UPDATE organization_responsible SET role = $1 WHERE organization_id = $2 AND user_id = $3", role, organizationId, userId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.SetMemberRole: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.SetMemberRole: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RemoveMember removes employee from organization, false is returned if employee is not its member
+func (repo *Repository) RemoveMember(ctx context.Context, organizationId, userId string) (bool, error) {
+	res, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM organization_responsible WHERE organization_id = $1 AND user_id = $2", organizationId, userId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RemoveMember: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.RemoveMember: %w", err)
+	}
+	return n > 0, nil
+}
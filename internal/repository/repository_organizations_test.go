@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestOrganizations(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+
+	var adminId string
+	for _, empl := range employees {
+		adminId = empl[0]
+		break
+	}
+
+	org, err := repo.AddOrganization(ctx, models.Organization{Name: "New", Description: "New organization", Type: models.LLC}, adminId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repo.OrganizationByUUID(ctx, org.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != org.Name || found.Description != org.Description || found.Type != org.Type {
+		t.Errorf("Found organization does not match added one: expected %v, got %v", org, found)
+	}
+
+	role, ok, err := repo.UserRole(ctx, adminId, org.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || role != models.RoleAdmin {
+		t.Errorf("Expected creator of organization to be its admin, got '%s'", role)
+	}
+
+	orgs, err := repo.GetOrganizations(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != len(employees)+1 {
+		t.Errorf("Expected to get %d organizations, got %d", len(employees)+1, len(orgs))
+	}
+
+	// Update
+	org.Name = "Renamed"
+	org.Type = models.JSC
+	ok, err = repo.UpdateOrganization(ctx, org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Expected organization '%s' to be updated", org.Id)
+	}
+
+	found, err = repo.OrganizationByUUID(ctx, org.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "Renamed" || found.Type != models.JSC {
+		t.Errorf("Organization was not updated: %v", found)
+	}
+
+	// Delete
+	ok, err = repo.DeleteOrganization(ctx, org.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("Expected organization '%s' to be deleted", org.Id)
+	}
+
+	_, ok, err = repo.UserRole(ctx, adminId, org.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("Expected membership to be deleted along with organization")
+	}
+}
+
+func TestMembers(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+
+	user, err := repo.AddUser(ctx, models.User{Username: "Member"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for org, empl := range employees {
+		err = repo.AddMember(ctx, org, user.Id, models.RoleViewer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = repo.AddMember(ctx, org, user.Id, models.RoleViewer)
+		if !errors.Is(err, models.ErrAlreadyMember) {
+			t.Errorf("Expected models.ErrAlreadyMember for repeated membership, got %v", err)
+		}
+
+		members, err := repo.GetMembers(ctx, org, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(members) != len(empl)+1 {
+			t.Errorf("Expected organization '%s' to have %d members, got %d", org, len(empl)+1, len(members))
+		}
+
+		ok, err := repo.SetMemberRole(ctx, org, user.Id, models.RoleEditor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		role, _, err := repo.UserRole(ctx, user.Id, org)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || role != models.RoleEditor {
+			t.Errorf("Expected member role to be changed to editor, got '%s'", role)
+		}
+
+		ok, err = repo.RemoveMember(ctx, org, user.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("Expected member '%s' to be removed", user.Id)
+		}
+
+		ok, err = repo.RemoveMember(ctx, org, user.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Errorf("Expected member '%s' not to be removed twice", user.Id)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const questionColumns = `
+		id,
+		tender_id,
+		author_user_id,
+		question,
+		answer,
+		answered_by,
+		answered_at,
+		status,
+		created_at,
+		updated_at`
+
+func (repo *Repository) AddQuestion(ctx context.Context, question models.Question) (models.Question, error) {
+	query := `
+	INSERT INTO tender_questions (tender_id, author_user_id, question)
+	VALUES
+		($1, $2, $3)
+	RETURNING` + questionColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, question.TenderId, nullableUUID(question.AuthorId), question.Question)
+	question, err := scanQuestion(row)
+	if err != nil {
+		return question, fmt.Errorf("repository.Repository.AddQuestion: %w", err)
+	}
+	return question, nil
+}
+
+// GetTenderQuestions lists questions on tender in order they are asked
+func (repo *Repository) GetTenderQuestions(ctx context.Context, tenderId string, filter models.QuestionFilter, limit, offset int) ([]models.Question, error) {
+	query := `
+	SELECT` + questionColumns + `
+	FROM tender_questions
+	WHERE tender_id = $3 AND ($4 OR status = 'Answered' OR author_user_id = $5::uuid)
+	ORDER BY created_at, id
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, tenderId, filter.All, nullableUUID(filter.AuthorId))
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderQuestions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Question{}
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenderQuestions: rows scan failed: %w", err)
+		}
+		result = append(result, question)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderQuestions: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetQuestion(ctx context.Context, questionId string) (models.Question, error) {
+	query := `
+	SELECT` + questionColumns + `
+	FROM tender_questions
+	WHERE id = $1
+	`
+
+	question, err := scanQuestion(repo.conn(ctx).QueryRowContext(ctx, query, questionId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return question, fmt.Errorf("repository.Repository.GetQuestion: %w", models.ErrNoQuestion)
+		}
+		return question, fmt.Errorf("repository.Repository.GetQuestion: %w", err)
+	}
+	return question, nil
+}
+
+// AnswerQuestion stores answer of question and marks it answered, answered questions can be answered again
+func (repo *Repository) AnswerQuestion(ctx context.Context, question models.Question) (models.Question, error) {
+	query := `
+	UPDATE tender_questions
+	SET (answer, answered_by, answered_at, status, updated_at) = ($1, $2, CURRENT_TIMESTAMP, 'Answered', CURRENT_TIMESTAMP)
+	WHERE id = $3 AND status <> 'Closed'
+	RETURNING` + questionColumns
+
+	row := repo.conn(ctx).QueryRowContext(ctx, query, question.Answer, nullableUUID(question.AnsweredBy), question.Id)
+	answered, err := scanQuestion(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return question, fmt.Errorf("repository.Repository.AnswerQuestion: %w", models.ErrQuestionsClosed)
+		}
+		return question, fmt.Errorf("repository.Repository.AnswerQuestion: %w", err)
+	}
+	return answered, nil
+}
+
+// CloseTenderQuestions closes unanswered questions on tender, answered ones are kept as they are
+func (repo *Repository) CloseTenderQuestions(ctx context.Context, tenderId string) error {
+	query := `
+	UPDATE tender_questions
+	SET (status, updated_at) = ('Closed', CURRENT_TIMESTAMP)
+	WHERE tender_id = $1 AND status = 'Open'
+	`
+
+	_, err := repo.conn(ctx).ExecContext(ctx, query, tenderId)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.CloseTenderQuestions: %w", err)
+	}
+	return nil
+}
+
+//// Service
+
+func scanQuestion(row rowScanner) (models.Question, error) {
+	var question models.Question
+	var authorId, answeredBy interface{}
+	var answer sql.NullString
+	var answeredAt sql.NullTime
+
+	err := row.Scan(&question.Id, &question.TenderId, &authorId, &question.Question, &answer, &answeredBy, &answeredAt,
+		&question.Status, &question.CreatedAt, &question.UpdatedAt)
+	if err != nil {
+		return question, err
+	}
+	question.AuthorId = readUUID(authorId)
+	question.AnsweredBy = readUUID(answeredBy)
+	question.Answer = answer.String
+	if answeredAt.Valid {
+		question.AnsweredAt = &answeredAt.Time
+	}
+	return question, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestQuestions(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	var asker, answerer string
+	for _, users := range employees {
+		if len(asker) == 0 {
+			asker = users[0]
+		} else {
+			answerer = users[0]
+			break
+		}
+	}
+
+	var questions []models.Question
+	for _, text := range []string{"First?", "Second?"} {
+		question, err := repo.AddQuestion(ctx, models.Question{TenderId: tenders[0].Id, AuthorId: asker, Question: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if question.Status != models.QuestionOpen || question.AuthorId != asker || question.AnsweredAt != nil {
+			t.Fatalf("Expected open question of asker, got %v", question)
+		}
+		questions = append(questions, question)
+	}
+
+	answered, err := repo.AnswerQuestion(ctx, models.Question{Id: questions[0].Id, Answer: "Yes", AnsweredBy: answerer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answered.Status != models.QuestionAnswered || answered.Answer != "Yes" || answered.AnsweredBy != answerer || answered.AnsweredAt == nil {
+		t.Errorf("Expected answered question, got %v", answered)
+	}
+
+	// others only see answered questions
+	for _, test := range []struct {
+		filter   models.QuestionFilter
+		expected int
+	}{
+		{models.QuestionFilter{All: true}, 2},
+		{models.QuestionFilter{AuthorId: asker}, 2},
+		{models.QuestionFilter{AuthorId: answerer}, 1},
+		{models.QuestionFilter{}, 1},
+	} {
+		listed, err := repo.GetTenderQuestions(ctx, tenders[0].Id, test.filter, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listed) != test.expected || listed[0].Id != questions[0].Id {
+			t.Errorf("Expected %d questions in order they are asked for filter %v, got %v", test.expected, test.filter, listed)
+		}
+	}
+	listed, err := repo.GetTenderQuestions(ctx, tenders[0].Id, models.QuestionFilter{All: true}, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].Id != questions[1].Id {
+		t.Errorf("Expected second question on second page, got %v", listed)
+	}
+
+	// only unanswered questions are closed along with tender
+	err = repo.CloseTenderQuestions(ctx, tenders[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []models.QuestionStatus{models.QuestionAnswered, models.QuestionClosed} {
+		question, err := repo.GetQuestion(ctx, questions[i].Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if question.Status != expected {
+			t.Errorf("Expected question status %s, got %s", expected, question.Status)
+		}
+	}
+
+	_, err = repo.AnswerQuestion(ctx, models.Question{Id: questions[1].Id, Answer: "Late", AnsweredBy: answerer})
+	if !errors.Is(err, models.ErrQuestionsClosed) {
+		t.Errorf("Expected ErrQuestionsClosed on answer of closed question, got %v", err)
+	}
+	_, err = repo.GetQuestion(ctx, "550e8400-e29b-41d4-a716-446655440000")
+	if !errors.Is(err, models.ErrNoQuestion) {
+		t.Errorf("Expected ErrNoQuestion on unknown question, got %v", err)
+	}
+}
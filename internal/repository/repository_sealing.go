@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"tenders/internal/models"
+	"time"
+)
+
+// AddTenderKey stores key of sealed tender, key is expected to be wrapped and is stored as is
+func (repo *Repository) AddTenderKey(ctx context.Context, tenderId string, wrappedKey []byte) error {
+	_, err := repo.conn(ctx).ExecContext(ctx, "INSERT INTO tender_keys (tender_id, wrapped_key) VALUES ($1, $2)", tenderId, wrappedKey)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.AddTenderKey: %w", err)
+	}
+	return nil
+}
+
+// GetTenderKey returns wrapped key of sealed tender, models.ErrNoTender is returned if tender has none
+func (repo *Repository) GetTenderKey(ctx context.Context, tenderId string) ([]byte, error) {
+	var key []byte
+	err := repo.conn(ctx).QueryRowContext(ctx, "SELECT wrapped_key FROM tender_keys WHERE tender_id = $1", tenderId).Scan(&key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("repository.Repository.GetTenderKey: %w", models.ErrNoTender)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderKey: %w", err)
+	}
+	return key, nil
+}
+
+// OpenTender records opening of bids of sealed tender. Returned flag is false, when tender is not sealed
+// or its bids are already opened
+func (repo *Repository) OpenTender(ctx context.Context, tenderId, userId string, now time.Time) (bool, error) {
+	query := `
+	UPDATE tenders
+	SET (opened_at, opened_by) = ($2, $3)
+	WHERE id = $1 AND sealed AND opened_at IS NULL
+	`
+
+	res, err := repo.conn(ctx).ExecContext(ctx, query, tenderId, now.UTC(), nullableUUID(userId))
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.OpenTender: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.OpenTender: %w", err)
+	}
+	return n > 0, nil
+}
+
+// UnsealBidContent replaces sealed content of bid with plain one, both in bid itself and in its versions
+// sealed with the same content. Versions are otherwise left as is
+func (repo *Repository) UnsealBidContent(ctx context.Context, bidId string, sealed []byte, content models.BidContent) error {
+	amount, currency := moneyParams(content.Price)
+	for _, table := range []string{"proposals", "proposals_versions"} {
+		query := `
+		UPDATE ` + table + `
+		SET (name, description, price_amount, price_currency, sealed_content) = ($3, $4, $5, $6, NULL)
+		WHERE id = $1 AND sealed_content = $2
+		`
+
+		_, err := repo.conn(ctx).ExecContext(ctx, query, bidId, sealed, content.Name, content.Description, amount, currency)
+		if err != nil {
+			return fmt.Errorf("repository.Repository.UnsealBidContent: %w", err)
+		}
+	}
+	return nil
+}
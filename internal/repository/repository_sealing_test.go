@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+	"time"
+)
+
+func TestSealedBids(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	var org, user string
+	for o, users := range employees {
+		org, user = o, users[0]
+		break
+	}
+
+	deadline := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tender, err := repo.AddTender(ctx, models.Tender{
+		Name:               "Sealed tender",
+		Status:             models.TenderPublished,
+		ServiceType:        models.STConstruction,
+		OrganizationId:     org,
+		Author:             user,
+		SubmissionDeadline: &deadline,
+		Sealed:             true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tender, err = repo.GetTenderByUUID(ctx, tender.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tender.Sealed || !tender.BidsSealed() {
+		t.Fatalf("Expected tender to be sealed, got %v", tender)
+	}
+
+	_, err = repo.GetTenderKey(ctx, tender.Id)
+	if !errors.Is(err, models.ErrNoTender) {
+		t.Errorf("Expected tender without key to be reported, got %v", err)
+	}
+	err = repo.AddTenderKey(ctx, tender.Id, []byte("wrapped key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := repo.GetTenderKey(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != "wrapped key" {
+		t.Errorf("Expected stored key to be returned, got %q", key)
+	}
+
+	bid, err := repo.AddBid(ctx, models.Bid{
+		TenderId:       tender.Id,
+		AuthorType:     models.AuthorUser,
+		AuthorId:       user,
+		OrganizationId: org,
+		UserId:         user,
+		SealedContent:  []byte("sealed"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bid, err = repo.GetBidByUUID(ctx, bid.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bid.Sealed || !bytes.Equal(bid.SealedContent, []byte("sealed")) || len(bid.Name) > 0 || bid.Price != nil {
+		t.Fatalf("Expected bid to be stored sealed, got %v", bid)
+	}
+
+	bid.Status = models.BidPublished
+	err = repo.UpdateBid(ctx, bid, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opened, err := repo.OpenTender(ctx, tender.Id, user, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opened {
+		t.Fatal("Expected sealed tender to be opened")
+	}
+	opened, err = repo.OpenTender(ctx, tender.Id, user, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened {
+		t.Error("Expected tender to be opened only once")
+	}
+	tender, err = repo.GetTenderByUUID(ctx, tender.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.BidsSealed() || tender.OpenedAt == nil || tender.OpenedBy != user {
+		t.Errorf("Expected tender to be opened by %s, got %v", user, tender)
+	}
+
+	price := &models.Money{Amount: "100", Currency: "USD"}
+	err = repo.UnsealBidContent(ctx, bid.Id, bid.SealedContent, models.BidContent{Name: "Bid", Description: "Sealed bid", Price: price})
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := repo.GetBidVersions(ctx, bid.Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := repo.GetBidByUUID(ctx, bid.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range append(versions, current) {
+		if b.Sealed || b.Name != "Bid" || b.Description != "Sealed bid" || b.Price == nil || *b.Price != *price {
+			t.Errorf("Expected bid version to be unsealed, got %v", b)
+		}
+	}
+
+	err = repo.DeleteTender(ctx, tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"tenders/internal/models"
+)
+
+const templateColumns = `
+		id,
+		organization_id,
+		name,
+		source_tender_id,
+		description,
+		service_type,
+		budget_amount,
+		budget_currency,
+		budget_confidential,
+		visibility,
+		lots,
+		criteria,
+		author_id,
+		created_at,
+		updated_at`
+
+func (repo *Repository) AddTemplate(ctx context.Context, template models.Template) (models.Template, error) {
+	query := `
+	INSERT INTO tender_templates
+		(organization_id, name, source_tender_id, description, service_type, budget_amount, budget_currency, budget_confidential, visibility, lots, criteria, author_id)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	RETURNING` + templateColumns
+
+	lots, err := json.Marshal(templateLots(template))
+	if err != nil {
+		return template, fmt.Errorf("repository.Repository.AddTemplate: %w", err)
+	}
+	criteria, err := json.Marshal(templateCriteria(template))
+	if err != nil {
+		return template, fmt.Errorf("repository.Repository.AddTemplate: %w", err)
+	}
+
+	amount, currency := moneyParams(template.Budget)
+	row := repo.conn(ctx).QueryRowContext(ctx, query, template.OrganizationId, template.Name, nullableUUID(template.SourceTenderId), template.Description,
+		template.ServiceType, amount, currency, template.BudgetConfidential, visibility(template.Visibility), lots, criteria,
+		nullableUUID(template.AuthorId))
+	template, err = scanTemplate(row)
+	if isUniqueViolation(err) {
+		return template, fmt.Errorf("repository.Repository.AddTemplate: %w", models.ErrTemplateExists)
+	} else if err != nil {
+		return template, fmt.Errorf("repository.Repository.AddTemplate: %w", err)
+	}
+
+	return template, nil
+}
+
+// GetTemplates returns templates of organization ordered by name
+func (repo *Repository) GetTemplates(ctx context.Context, organizationId string, limit, offset int) ([]models.Template, error) {
+	query := `
+	SELECT` + templateColumns + `
+	FROM tender_templates
+	WHERE organization_id = $1
+	ORDER BY name
+	LIMIT $2
+	OFFSET $3
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, organizationId, limitParam(limit), offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTemplates: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Template{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTemplates: rows scan failed: %w", err)
+		}
+		result = append(result, template)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTemplates: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetTemplate(ctx context.Context, templateId string) (models.Template, error) {
+	query := `
+	SELECT` + templateColumns + `
+	FROM tender_templates
+	WHERE id = $1
+	`
+
+	template, err := scanTemplate(repo.conn(ctx).QueryRowContext(ctx, query, templateId))
+	if err != nil {
+		return template, fmt.Errorf("repository.Repository.GetTemplate: %w", err)
+	}
+	return template, nil
+}
+
+// DeleteTemplate removes template, tenders cloned from it lose reference to it
+func (repo *Repository) DeleteTemplate(ctx context.Context, templateId string) (bool, error) {
+	res, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM tender_templates WHERE id = $1", templateId)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteTemplate: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.DeleteTemplate: %w", err)
+	}
+	return n > 0, nil
+}
+
+//// Service
+
+func scanTemplate(row rowScanner) (models.Template, error) {
+	var template models.Template
+	var sourceTender, author interface{}
+	var amount, currency sql.NullString
+	var lots, criteria []byte
+
+	err := row.Scan(&template.Id, &template.OrganizationId, &template.Name, &sourceTender, &template.Description, &template.ServiceType,
+		&amount, &currency, &template.BudgetConfidential, &template.Visibility, &lots, &criteria, &author, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return template, err
+	}
+	template.SourceTenderId = readUUID(sourceTender)
+	template.AuthorId = readUUID(author)
+	template.Budget, err = readMoney(amount, currency)
+	if err != nil {
+		return template, err
+	}
+	err = json.Unmarshal(lots, &template.Lots)
+	if err != nil {
+		return template, err
+	}
+	err = json.Unmarshal(criteria, &template.Criteria)
+	return template, err
+}
+
+// templateLots returns lots of template, column is not nullable
+func templateLots(t models.Template) []models.TemplateLot {
+	if t.Lots == nil {
+		return []models.TemplateLot{}
+	}
+	return t.Lots
+}
+
+// templateCriteria returns criteria of template, column is not nullable
+func templateCriteria(t models.Template) []models.TemplateCriterion {
+	if t.Criteria == nil {
+		return []models.TemplateCriterion{}
+	}
+	return t.Criteria
+}
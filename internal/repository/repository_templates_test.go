@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestTenderTemplates(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	if len(tenders) == 0 {
+		t.Fatalf("Expected tenders to be created using AddAllTenders")
+	}
+	source := tenders[0]
+
+	template, err := repo.AddTemplate(ctx, models.Template{
+		OrganizationId: source.OrganizationId,
+		Name:           "template",
+		SourceTenderId: source.Id,
+		Description:    source.Description,
+		ServiceType:    source.ServiceType,
+		Lots:           []models.TemplateLot{{Name: "lot", Quantity: 2}},
+		Criteria:       []models.TemplateCriterion{{Name: "price", Weight: 100}},
+		AuthorId:       employees[source.OrganizationId][0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Visibility != models.VisibilityPublic || template.SourceTenderId != source.Id {
+		t.Fatalf("Expected public template created from tender %s, got %v", source.Id, template)
+	}
+
+	_, err = repo.AddTemplate(ctx, models.Template{OrganizationId: source.OrganizationId, Name: "template", ServiceType: source.ServiceType})
+	if !errors.Is(err, models.ErrTemplateExists) {
+		t.Fatalf("Expected template name to be unique within organization, got %v", err)
+	}
+
+	stored, err := repo.GetTemplate(ctx, template.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Lots) != 1 || stored.Lots[0].Quantity != 2 || len(stored.Criteria) != 1 || stored.Criteria[0].Weight != 100 {
+		t.Fatalf("Expected lots and criteria of template to be stored, got %v", stored)
+	}
+
+	templates, err := repo.GetTemplates(ctx, source.OrganizationId, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Id != template.Id {
+		t.Fatalf("Expected single template of organization, got %v", templates)
+	}
+
+	// clone keeps reference to its source
+	clone, err := repo.AddTender(ctx, models.Tender{
+		Name:             "clone",
+		ServiceType:      source.ServiceType,
+		Status:           models.TenderCreated,
+		OrganizationId:   source.OrganizationId,
+		Author:           source.Author,
+		SourceTemplateId: template.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Version != 1 || clone.SourceTemplateId != template.Id || len(clone.SourceTenderId) > 0 {
+		t.Fatalf("Expected first version of tender cloned from template, got %v", clone)
+	}
+	_, err = repo.AddTender(ctx, models.Tender{
+		Name:           "clone",
+		ServiceType:    source.ServiceType,
+		Status:         models.TenderCreated,
+		OrganizationId: source.OrganizationId,
+		Author:         source.Author,
+	})
+	if !errors.Is(err, models.ErrTenderNameTaken) {
+		t.Fatalf("Expected tender name to be unique, got %v", err)
+	}
+
+	ok, err := repo.DeleteTemplate(ctx, template.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("Expected template to be deleted")
+	}
+	clone, err = repo.GetTenderByUUID(ctx, clone.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clone.SourceTemplateId) > 0 {
+		t.Fatalf("Expected reference to deleted template to be cleared, got %s", clone.SourceTemplateId)
+	}
+	ok, err = repo.DeleteTemplate(ctx, template.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatalf("Expected missing template not to be deleted")
+	}
+}
@@ -1,300 +1,593 @@
-package repository
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"strconv"
-	"strings"
-	"tenders/internal/models"
-)
-
-func (repo *Repository) prepTendersQuery(limit, offset int, tenderId, userId string, serviceType []models.ServiceType) (query string, queryParams []interface{}) {
-	query = `
-	SELECT
-		id,
-		version,
-		organization_id,
-		author_id,
-		status,
-		service_type,
-		name,
-		description,
-		created_at,
-		updated_at
-	FROM tenders
-	$conditions$
-	ORDER BY name
-	LIMIT $1
-	OFFSET $2
-	`
-
-	queryParams = make([]interface{}, 0, 5)
-	conditions := make([]string, 0, 3)
-
-	if limit <= 0 {
-		queryParams = append(queryParams, nil)
-	} else {
-		queryParams = append(queryParams, limit)
-	}
-	queryParams = append(queryParams, offset)
-
-	if len(tenderId) > 0 {
-		conditions = append(conditions, "id = $$")
-		queryParams = append(queryParams, tenderId)
-	}
-
-	if len(userId) > 0 {
-		conditions = append(conditions, "author_id = $$")
-		queryParams = append(queryParams, userId)
-	}
-
-	if len(serviceType) > 0 {
-		conditions = append(conditions, "service_type = any($$::tender_service_type[])")
-		queryParams = append(queryParams, sliceToSQLList(serviceType))
-	}
-
-	condStr := ""
-	if len(conditions) > 0 {
-		for i := 0; i < len(conditions); i++ {
-			conditions[i] = strings.Replace(conditions[i], "$$", "$"+strconv.Itoa(i+3), -1)
-		}
-		condStr = "WHERE " + strings.Join(conditions, " AND ")
-	}
-	query = strings.Replace(query, "$conditions$", condStr, -1)
-
-	return query, queryParams
-}
-
-func (repo *Repository) GetTenders(ctx context.Context, limit, offset int, tenderId, userId string, serviceType []models.ServiceType) ([]models.Tender, error) {
-	query, queryParams := repo.prepTendersQuery(limit, offset, tenderId, userId, serviceType)
-
-	rows, err := repo.db.QueryContext(ctx, query, queryParams...)
-	if err != nil {
-		return nil, fmt.Errorf("repository.Repository.GetTenders: %w", err)
-	}
-	defer rows.Close()
-
-	var result []models.Tender
-	tender := models.Tender{}
-	for rows.Next() {
-		err = rows.Scan(&tender.Id, &tender.Version, &tender.OrganizationId, &tender.Author, &tender.Status, &tender.ServiceType, &tender.Name, &tender.Description, &tender.CreatedAt, &tender.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("repository.Repository.GetTenders: row scan failed: %w", err)
-		}
-		result = append(result, tender)
-	}
-
-	if rows.Err() != nil {
-		return nil, fmt.Errorf("repository.Repository.GetTenders: %w", err)
-	}
-
-	return result, nil
-}
-
-func (repo *Repository) GetTenderByUUID(ctx context.Context, UUID string, tx *sql.Tx) (models.Tender, error) {
-	var tender models.Tender
-	query, queryParams := repo.prepTendersQuery(1, 0, UUID, "", nil)
-
-	var rows *sql.Rows
-	var err error
-
-	if tx == nil {
-		rows, err = repo.db.QueryContext(ctx, query, queryParams...)
-	} else {
-		rows, err = tx.QueryContext(ctx, query, queryParams...)
-	}
-
-	if err != nil {
-		return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: %w", err)
-	}
-	defer rows.Close()
-
-	if rows.Next() {
-		err = rows.Scan(&tender.Id, &tender.Version, &tender.OrganizationId, &tender.Author, &tender.Status, &tender.ServiceType, &tender.Name, &tender.Description, &tender.CreatedAt, &tender.UpdatedAt)
-		if err != nil {
-			return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: row scan failed: %w", err)
-		}
-	} else {
-		return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: no tender found by UUID %s, %w", UUID, sql.ErrNoRows)
-	}
-
-	if rows.Err() != nil {
-		return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: %w", err)
-	}
-
-	return tender, nil
-}
-
-func (repo *Repository) AddTender(ctx context.Context, t models.Tender) (models.Tender, error) {
-	result := t
-
-	// Validate organization and user
-	ok, err := repo.UserValid(ctx, t.Author, t.OrganizationId)
-	if err != nil {
-		return result, fmt.Errorf("repository.Repository.AddTender: failed to validate user: %w", err)
-	}
-	if !ok {
-		return result, fmt.Errorf("repository.Repository.AddTender: no such user / organization pair (%s, %s)", t.Author, t.OrganizationId)
-	}
-
-	// Insert tender and version entry
-	query := `
-	INSERT INTO tenders 
-		(version, organization_id, author_id, status, service_type, name, description) 
-	VALUES 
-		(1, $1, $2, $3, $4, $5, $6)
-	RETURNING
-		id, version, created_at
-	`
-
-	tx, err := repo.db.BeginTx(ctx, nil)
-	if err != nil {
-		return result, fmt.Errorf("repository.Repository.AddTender: failed to start transaction: %w", err)
-	}
-
-	row := tx.QueryRowContext(ctx, query, t.OrganizationId, t.Author, t.Status, t.ServiceType, t.Name, t.Description)
-	err = row.Scan(&result.Id, &result.Version, &result.CreatedAt)
-	if err != nil {
-		tx.Rollback()
-		return result, fmt.Errorf("repository.Repository.AddTender: %w", err)
-	}
-
-	err = repo.AddTenderVersion(ctx, result, tx)
-	if err != nil {
-		tx.Rollback()
-		return result, fmt.Errorf("repository.Repository.AddTender: %w", err)
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return result, fmt.Errorf("repository.Repository.AddTender: failed to commit transaction: %w", err)
-	}
-
-	return result, nil
-}
-
-func (repo *Repository) UpdateTender(ctx context.Context, t models.Tender, incrementVersion bool) error {
-	// Validate organization and user
-	ok, err := repo.UserValid(ctx, t.Author, t.OrganizationId)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.AddTender: failed to validate user: %w", err)
-	}
-	if !ok {
-		return fmt.Errorf("repository.Repository.AddTender: no such user / organization pair (%s, %s)", t.Author, t.OrganizationId)
-	}
-
-	// Update tender and create version entry
-	query := `
-	UPDATE tenders 
-	SET (version, status, service_type, name, description, updated_at) =
-	($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
-	WHERE id = $6
-	`
-
-	tx, err := repo.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.UpdateTender: failed to start transaction: %w", err)
-	}
-
-	if incrementVersion {
-		t.Version++
-	}
-	_, err = repo.db.Exec(query, t.Version, t.Status, t.ServiceType, t.Name, t.Description, t.Id)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.UpdateTender: %w", err)
-	}
-
-	if incrementVersion {
-		tender, err := repo.GetTenderByUUID(ctx, t.Id, tx)
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("repository.Repository.UpdateTender: %w", err)
-		}
-
-		err = repo.AddTenderVersion(ctx, tender, tx)
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("repository.Repository.UpdateTender: %w", err)
-		}
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("repository.Repository.UpdateTender: failed to commit transaction: %w", err)
-	}
-
-	return nil
-}
-
-func (repo *Repository) DeleteTender(ctx context.Context, tenderId string) error {
-	_, err := repo.db.Exec("DELETE FROM tenders WHERE id = $1", tenderId)
-	if err != nil {
-		return fmt.Errorf("repository.Repository.DeleteTender: %w", err)
-	}
-	return nil
-}
-
-//// Versions
-
-func (repo *Repository) AddTenderVersion(ctx context.Context, t models.Tender, tx *sql.Tx) error {
-	queryVersion := `
-	INSERT INTO tenders_versions 
-		(id, version, organization_id, author_id, status, service_type, name, description, created_at, updated_at) 
-	VALUES 
-		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
-	`
-
-	var err error
-	if tx == nil {
-		_, err = repo.db.ExecContext(ctx, queryVersion, t.Id, t.Version, t.OrganizationId, t.Author, t.Status, t.ServiceType, t.Name, t.Description, t.CreatedAt, t.UpdatedAt)
-	} else {
-		_, err = tx.ExecContext(ctx, queryVersion, t.Id, t.Version, t.OrganizationId, t.Author, t.Status, t.ServiceType, t.Name, t.Description, t.CreatedAt, t.UpdatedAt)
-	}
-
-	if err != nil {
-		return fmt.Errorf("repository.Repository.AddTenderVersion: %w", err)
-	}
-	return nil
-}
-
-func (repo *Repository) GetTenderVersions(ctx context.Context, UUID string, version int) ([]models.Tender, error) {
-	query := `
-	SELECT
-		id,
-		version,
-		organization_id,
-		author_id,
-		status,
-		service_type,
-		name,
-		description,
-		created_at,
-		updated_at
-	FROM tenders_versions
-	WHERE id = $1 AND ($2 <= 0 OR version = $2)
-	ORDER BY updated_at DESC
-	`
-
-	rows, err := repo.db.QueryContext(ctx, query, UUID, version)
-	if err != nil {
-		return nil, fmt.Errorf("repository.Repository.GetTenderVersions: %w", err)
-	}
-	defer rows.Close()
-
-	var result []models.Tender
-	tender := models.Tender{}
-	for rows.Next() {
-		err = rows.Scan(&tender.Id, &tender.Version, &tender.OrganizationId, &tender.Author, &tender.Status, &tender.ServiceType, &tender.Name, &tender.Description, &tender.CreatedAt, &tender.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("repository.Repository.GetTenderVersions: row scan failed: %w", err)
-		}
-		result = append(result, tender)
-	}
-
-	if rows.Err() != nil {
-		return nil, fmt.Errorf("repository.Repository.GetTenderVersions: %w", err)
-	}
-
-	return result, nil
-}
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"tenders/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const tenderColumns = `
+		id,
+		version,
+		organization_id,
+		author_id,
+		status,
+		service_type,
+		name,
+		description,
+		budget_amount,
+		budget_currency,
+		budget_confidential,
+		visibility,
+		publish_at,
+		submission_deadline,
+		cancellation_reason,
+		awarded_bid_ids,
+		source_tender_id,
+		source_template_id,
+		sealed,
+		opened_at,
+		opened_by,
+		created_at,
+		updated_at`
+
+func (repo *Repository) prepTendersQuery(limit, offset int, filter models.TenderFilter) (query string, queryParams []interface{}) {
+	query = `
+	SELECT` + tenderColumns + `
+	FROM tenders
+	$conditions$
+	ORDER BY $order$
+	LIMIT $1
+	OFFSET $2
+	`
+
+	queryParams = make([]interface{}, 0, 9)
+	conditions := make([]string, 0, 7)
+	budget, currency := "budget_amount", "budget_currency"
+
+	if limit <= 0 {
+		queryParams = append(queryParams, nil)
+	} else {
+		queryParams = append(queryParams, limit)
+	}
+	queryParams = append(queryParams, offset)
+
+	if len(filter.TenderId) > 0 {
+		conditions = append(conditions, "id = $$")
+		queryParams = append(queryParams, filter.TenderId)
+	}
+
+	if len(filter.AuthorId) > 0 {
+		conditions = append(conditions, "author_id = $$")
+		queryParams = append(queryParams, filter.AuthorId)
+	}
+
+	if len(filter.OrganizationId) > 0 {
+		conditions = append(conditions, "organization_id = $$")
+		queryParams = append(queryParams, filter.OrganizationId)
+	}
+
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "status = any($$::tender_status[])")
+		queryParams = append(queryParams, sliceToSQLList(filter.Statuses))
+	}
+
+	conditions, queryParams = appendTimeRange(conditions, queryParams, "created_at", filter.Created)
+	conditions, queryParams = appendTimeRange(conditions, queryParams, "updated_at", filter.Updated)
+
+	if len(filter.ServiceTypes) > 0 {
+		// selected categories match tenders of their subcategories as well
+		conditions = append(conditions, `service_type IN (
+			WITH RECURSIVE subtree AS (
+				SELECT code FROM service_categories WHERE code = any($$::text[])
+				UNION
+				SELECT c.code FROM service_categories c JOIN subtree ON c.parent_code = subtree.code
+			)
+			SELECT code FROM subtree
+		)`)
+		queryParams = append(queryParams, sliceToSQLList(filter.ServiceTypes))
+	}
+
+	if filter.Restricted {
+		// scheduled tenders and confidential budgets are only visible to employees of owning organization
+		visible := "(NOT budget_confidential OR organization_id = any($" + strconv.Itoa(len(conditions)+3) + "::uuid[]))"
+		budget = "(CASE WHEN " + visible + " THEN budget_amount END)"
+		currency = "(CASE WHEN " + visible + " THEN budget_currency END)"
+		// invite-only tenders are only visible to owning and invited organizations
+		conditions = append(conditions, `(status <> 'Created' OR publish_at IS NULL OR organization_id = any($$::uuid[]))
+			AND (visibility = 'Public' OR organization_id = any($$::uuid[]) OR EXISTS (
+				SELECT 1 FROM tender_invited_organizations WHERE tender_id = tenders.id AND organization_id = any($$::uuid[])
+			))`)
+		queryParams = append(queryParams, pq.Array(filter.VisibleTo))
+	}
+
+	if len(filter.Budget.Currency) > 0 {
+		conditions = append(conditions, currency+" = $$")
+		queryParams = append(queryParams, filter.Budget.Currency)
+	}
+	if len(filter.Budget.Min) > 0 {
+		conditions = append(conditions, budget+" >= $$")
+		queryParams = append(queryParams, string(filter.Budget.Min))
+	}
+	if len(filter.Budget.Max) > 0 {
+		conditions = append(conditions, budget+" <= $$")
+		queryParams = append(queryParams, string(filter.Budget.Max))
+	}
+
+	sortColumns := map[string]string{
+		"name":      "name",
+		"budget":    budget,
+		"status":    "status",
+		"createdAt": "created_at",
+		"updatedAt": "updated_at",
+	}
+	if len(filter.Query) > 0 {
+		tsquery := "websearch_to_tsquery('simple', $" + strconv.Itoa(len(conditions)+3) + ")"
+		sortColumns["relevance"] = "ts_rank_cd(search_vector, " + tsquery + ")"
+		if len(filter.Sort) == 0 {
+			filter.Sort = []models.SortField{{Field: "relevance", Desc: true}}
+		}
+		conditions = append(conditions, "search_vector @@ "+tsquery)
+		queryParams = append(queryParams, filter.Query)
+	}
+
+	condStr := ""
+	if len(conditions) > 0 {
+		for i := 0; i < len(conditions); i++ {
+			conditions[i] = strings.Replace(conditions[i], "$$", "$"+strconv.Itoa(i+3), -1)
+		}
+		condStr = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query = strings.Replace(query, "$conditions$", condStr, -1)
+	query = strings.Replace(query, "$order$", orderClause(filter.Sort, sortColumns), -1)
+
+	return query, queryParams
+}
+
+func (repo *Repository) GetTenders(ctx context.Context, limit, offset int, filter models.TenderFilter) ([]models.Tender, error) {
+	query, queryParams := repo.prepTendersQuery(limit, offset, filter)
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenders: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Tender
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenders: row scan failed: %w", err)
+		}
+		result = append(result, tender)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenders: %w", err)
+	}
+
+	return result, nil
+}
+
+func (repo *Repository) GetTenderByUUID(ctx context.Context, UUID string, tx *sql.Tx) (models.Tender, error) {
+	var tender models.Tender
+	query, queryParams := repo.prepTendersQuery(1, 0, models.TenderFilter{TenderId: UUID})
+
+	var rows *sql.Rows
+	var err error
+
+	if tx == nil {
+		rows, err = repo.conn(ctx).QueryContext(ctx, query, queryParams...)
+	} else {
+		rows, err = tx.QueryContext(ctx, query, queryParams...)
+	}
+
+	if err != nil {
+		return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		tender, err = scanTender(rows)
+		if err != nil {
+			return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: row scan failed: %w", err)
+		}
+	} else {
+		return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: no tender found by UUID %s, %w", UUID, sql.ErrNoRows)
+	}
+
+	if rows.Err() != nil {
+		return tender, fmt.Errorf("repository.Repository.GetTenderByUUID: %w", err)
+	}
+
+	return tender, nil
+}
+
+func (repo *Repository) AddTender(ctx context.Context, t models.Tender) (models.Tender, error) {
+	result := t
+
+	// Validate organization and user, tenders created by API keys have no author
+	if len(t.Author) > 0 {
+		ok, err := repo.UserValid(ctx, t.Author, t.OrganizationId)
+		if err != nil {
+			return result, fmt.Errorf("repository.Repository.AddTender: failed to validate user: %w", err)
+		}
+		if !ok {
+			return result, fmt.Errorf("repository.Repository.AddTender: no such user / organization pair (%s, %s)", t.Author, t.OrganizationId)
+		}
+	}
+
+	// Insert tender and version entry
+	query := `
+	INSERT INTO tenders 
+		(version, organization_id, author_id, status, service_type, name, description, budget_amount, budget_currency, budget_confidential, visibility, publish_at, submission_deadline,
+		source_tender_id, source_template_id, sealed) 
+	VALUES 
+		(1, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	RETURNING
+		id, version, created_at
+	`
+
+	result.Visibility = visibility(t.Visibility)
+
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		amount, currency := moneyParams(t.Budget)
+		row := repo.conn(ctx).QueryRowContext(ctx, query, t.OrganizationId, nullableUUID(t.Author), t.Status, t.ServiceType, t.Name, t.Description,
+			amount, currency, t.BudgetConfidential, result.Visibility, nullableTime(t.PublishAt), nullableTime(t.SubmissionDeadline),
+			nullableUUID(t.SourceTenderId), nullableUUID(t.SourceTemplateId), t.Sealed)
+		err := row.Scan(&result.Id, &result.Version, &result.CreatedAt)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: %s", models.ErrTenderNameTaken, t.Name)
+		} else if err != nil {
+			return err
+		}
+
+		return repo.AddTenderVersion(ctx, result, nil)
+	})
+	if err != nil {
+		return result, fmt.Errorf("repository.Repository.AddTender: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateTender overwrites tender provided it is still of version t.Version, otherwise models.ErrVersionConflict
+// is returned, so that concurrent changes are not lost. Author is not revalidated, since editors are authorized by
+// the service and author may have since left organization
+func (repo *Repository) UpdateTender(ctx context.Context, t models.Tender, incrementVersion bool) error {
+	// Update tender and create version entry
+	query := `
+	UPDATE tenders 
+	SET (version, status, service_type, name, description, budget_amount, budget_currency, budget_confidential, visibility, publish_at, submission_deadline,
+		cancellation_reason, awarded_bid_ids, updated_at) =
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CURRENT_TIMESTAMP)
+	WHERE id = $14 AND version = $15
+	`
+
+	current := t.Version
+	if incrementVersion {
+		t.Version++
+	}
+
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		amount, currency := moneyParams(t.Budget)
+		res, err := repo.conn(ctx).ExecContext(ctx, query, t.Version, t.Status, t.ServiceType, t.Name, t.Description,
+			amount, currency, t.BudgetConfidential, visibility(t.Visibility), nullableTime(t.PublishAt), nullableTime(t.SubmissionDeadline),
+			t.CancellationReason, pq.Array(awardedBidIds(t)), t.Id, current)
+		if err != nil {
+			return err
+		}
+		err = checkVersionUpdated(res)
+		if err != nil || !incrementVersion {
+			return err
+		}
+
+		tender, err := repo.GetTenderByUUID(ctx, t.Id, nil)
+		if err != nil {
+			return err
+		}
+		return repo.AddTenderVersion(ctx, tender, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("repository.Repository.UpdateTender: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *Repository) DeleteTender(ctx context.Context, tenderId string) error {
+	_, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM tenders WHERE id = $1", tenderId)
+	if err != nil {
+		return fmt.Errorf("repository.Repository.DeleteTender: %w", err)
+	}
+	return nil
+}
+
+//// Deadlines
+
+// GetExpiredTenders returns published tenders, submission deadline of which is not after now
+func (repo *Repository) GetExpiredTenders(ctx context.Context, now time.Time, limit int) ([]models.Tender, error) {
+	query := `
+	SELECT` + tenderColumns + `
+	FROM tenders
+	WHERE status = 'Published' AND submission_deadline <= $1
+	ORDER BY submission_deadline
+	LIMIT $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, now.UTC(), limitParam(limit))
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetExpiredTenders: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Tender
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetExpiredTenders: row scan failed: %w", err)
+		}
+		result = append(result, tender)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetExpiredTenders: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// CloseExpiredTender closes tender and creates version entry, if tender is still published and its deadline is not after now.
+// Returned flag is false, when tender was changed concurrently and has not been closed
+func (repo *Repository) CloseExpiredTender(ctx context.Context, tenderId string, now time.Time) (models.Tender, bool, error) {
+	query := `
+	UPDATE tenders
+	SET (version, status, updated_at) = (version + 1, 'Closed', CURRENT_TIMESTAMP)
+	WHERE id = $1 AND status = 'Published' AND submission_deadline <= $2
+	`
+
+	var tender models.Tender
+	closed := false
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		res, err := repo.conn(ctx).ExecContext(ctx, query, tenderId, now.UTC())
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+
+		tender, err = repo.GetTenderByUUID(ctx, tenderId, nil)
+		if err != nil {
+			return err
+		}
+		closed = true
+		return repo.AddTenderVersion(ctx, tender, nil)
+	})
+	if err != nil {
+		return tender, false, fmt.Errorf("repository.Repository.CloseExpiredTender: %w", err)
+	}
+
+	return tender, closed, nil
+}
+
+//// Scheduled publication
+
+// GetScheduledTenders returns tenders in Created status, publication time of which is not after now
+func (repo *Repository) GetScheduledTenders(ctx context.Context, now time.Time, limit int) ([]models.Tender, error) {
+	query := `
+	SELECT` + tenderColumns + `
+	FROM tenders
+	WHERE status = 'Created' AND publish_at <= $1
+	ORDER BY publish_at
+	LIMIT $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, now.UTC(), limitParam(limit))
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetScheduledTenders: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Tender
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetScheduledTenders: row scan failed: %w", err)
+		}
+		result = append(result, tender)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetScheduledTenders: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// PublishScheduledTender publishes tender, clearing its schedule, and creates version entry, if tender is still in Created
+// status and its publication time is not after now. Returned flag is false, when tender was changed
+// concurrently and has not been published
+func (repo *Repository) PublishScheduledTender(ctx context.Context, tenderId string, now time.Time) (models.Tender, bool, error) {
+	query := `
+	UPDATE tenders
+	SET (version, status, publish_at, updated_at) = (version + 1, 'Published', NULL, CURRENT_TIMESTAMP)
+	WHERE id = $1 AND status = 'Created' AND publish_at <= $2
+	`
+
+	var tender models.Tender
+	published := false
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		res, err := repo.conn(ctx).ExecContext(ctx, query, tenderId, now.UTC())
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+
+		tender, err = repo.GetTenderByUUID(ctx, tenderId, nil)
+		if err != nil {
+			return err
+		}
+		published = true
+		return repo.AddTenderVersion(ctx, tender, nil)
+	})
+	if err != nil {
+		return tender, false, fmt.Errorf("repository.Repository.PublishScheduledTender: %w", err)
+	}
+
+	return tender, published, nil
+}
+
+//// Versions
+
+func (repo *Repository) AddTenderVersion(ctx context.Context, t models.Tender, tx *sql.Tx) error {
+	queryVersion := `
+	INSERT INTO tenders_versions 
+		(id, version, organization_id, author_id, status, service_type, name, description, budget_amount, budget_currency, budget_confidential, visibility, publish_at,
+		submission_deadline, cancellation_reason, awarded_bid_ids, source_tender_id, source_template_id, sealed, opened_at, opened_by, created_at, updated_at) 
+	VALUES 
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23);
+	`
+
+	var err error
+	amount, currency := moneyParams(t.Budget)
+	params := []interface{}{t.Id, t.Version, t.OrganizationId, nullableUUID(t.Author), t.Status, t.ServiceType, t.Name, t.Description,
+		amount, currency, t.BudgetConfidential, visibility(t.Visibility), nullableTime(t.PublishAt), nullableTime(t.SubmissionDeadline), t.CancellationReason,
+		pq.Array(awardedBidIds(t)), nullableUUID(t.SourceTenderId), nullableUUID(t.SourceTemplateId), t.Sealed, nullableTime(t.OpenedAt), nullableUUID(t.OpenedBy),
+		t.CreatedAt, t.UpdatedAt}
+	if tx == nil {
+		_, err = repo.conn(ctx).ExecContext(ctx, queryVersion, params...)
+	} else {
+		_, err = tx.ExecContext(ctx, queryVersion, params...)
+	}
+
+	if err != nil {
+		return fmt.Errorf("repository.Repository.AddTenderVersion: %w", err)
+	}
+	return nil
+}
+
+func (repo *Repository) GetTenderVersions(ctx context.Context, UUID string, version int) ([]models.Tender, error) {
+	query := `
+	SELECT` + tenderColumns + `
+	FROM tenders_versions
+	WHERE id = $1 AND ($2 <= 0 OR version = $2)
+	ORDER BY updated_at DESC
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, UUID, version)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderVersions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Tender
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenderVersions: row scan failed: %w", err)
+		}
+		result = append(result, tender)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderVersions: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetTenderVersionHistory lists stored versions of tender in ascending order
+func (repo *Repository) GetTenderVersionHistory(ctx context.Context, UUID string, limit, offset int) ([]models.Tender, error) {
+	query := `
+	SELECT` + tenderColumns + `
+	FROM tenders_versions
+	WHERE id = $3
+	ORDER BY version, updated_at
+	LIMIT $1
+	OFFSET $2
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, limitParam(limit), offset, UUID)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderVersionHistory: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Tender{}
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetTenderVersionHistory: row scan failed: %w", err)
+		}
+		result = append(result, tender)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetTenderVersionHistory: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func scanTender(row rowScanner) (models.Tender, error) {
+	var tender models.Tender
+	var author, sourceTender, sourceTemplate, openedBy interface{}
+	var publishAt, deadline, openedAt sql.NullTime
+	var amount, currency sql.NullString
+	var awarded pq.StringArray
+
+	err := row.Scan(&tender.Id, &tender.Version, &tender.OrganizationId, &author, &tender.Status, &tender.ServiceType, &tender.Name, &tender.Description,
+		&amount, &currency, &tender.BudgetConfidential, &tender.Visibility, &publishAt, &deadline, &tender.CancellationReason, &awarded,
+		&sourceTender, &sourceTemplate, &tender.Sealed, &openedAt, &openedBy, &tender.CreatedAt, &tender.UpdatedAt)
+	if err != nil {
+		return tender, err
+	}
+	tender.SourceTenderId = readUUID(sourceTender)
+	tender.SourceTemplateId = readUUID(sourceTemplate)
+	if len(awarded) > 0 {
+		tender.AwardedBidIds = awarded
+	}
+	tender.Author = readUUID(author)
+	tender.Budget, err = readMoney(amount, currency)
+	if err != nil {
+		return tender, err
+	}
+	if publishAt.Valid {
+		tender.PublishAt = &publishAt.Time
+	}
+	if deadline.Valid {
+		tender.SubmissionDeadline = &deadline.Time
+	}
+	if openedAt.Valid {
+		tender.OpenedAt = &openedAt.Time
+	}
+	tender.OpenedBy = readUUID(openedBy)
+	return tender, nil
+}
+
+// awardedBidIds returns bids tender is awarded to, column is not nullable
+func awardedBidIds(t models.Tender) []string {
+	if t.AwardedBidIds == nil {
+		return []string{}
+	}
+	return t.AwardedBidIds
+}
+
+// visibility defaults to public one, tenders are public unless stated otherwise
+func visibility(v models.TenderVisibility) models.TenderVisibility {
+	if len(v) == 0 {
+		return models.VisibilityPublic
+	}
+	return v
+}
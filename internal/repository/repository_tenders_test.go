@@ -1,182 +1,641 @@
-package repository
-
-import (
-	"context"
-	"fmt"
-	"tenders/internal/models"
-	"testing"
-)
-
-func TestAddTender(t *testing.T) {
-	ctx := context.Background()
-	repo := OpenTestRepo(t)
-	defer repo.Close()
-
-	employees := InsertTestInitData(t, repo.db)
-	tenders := AddAllTenders(t, repo, employees)
-
-	// cleanup
-	for _, tender := range tenders {
-		err := repo.DeleteTender(ctx, tender.Id)
-		if err != nil {
-			t.Errorf("Could not delete tender %s: %s", tender.Id, err)
-		}
-	}
-}
-
-func TestGetTenders(t *testing.T) {
-	ctx := context.Background()
-	repo := OpenTestRepo(t)
-	defer repo.Close()
-
-	employees := InsertTestInitData(t, repo.db)
-
-	allTenders := AddAllTenders(t, repo, employees)
-	if len(allTenders) < len(AllServiceTypes()) {
-		t.Fatalf("Expeceted at least 2 tenders to be created using result of InsertTestInitData, got %d", len(allTenders))
-	}
-
-	// ensure tenders list without pagination and service type condition has all tenders
-	tenders, err := repo.GetTenders(ctx, 0, 0, "", "", nil)
-	if err != nil {
-		t.Fatalf("Could not get tenders: %s", err)
-	}
-
-	if len(allTenders) != len(tenders) {
-		t.Fatalf("Amount of added and received tenders does not match: %d - %d", len(allTenders), len(tenders))
-	}
-
-	// ensure tenders list without pagination and with service type condition by all possible service types has all tenders
-	tenders, err = repo.GetTenders(ctx, 0, 0, "", "", AllServiceTypes())
-	if err != nil {
-		t.Fatalf("Could not get tenders: %s", err)
-	}
-
-	if len(allTenders) != len(tenders) {
-		t.Fatalf("Amount of added and received tenders does not match: %d - %d", len(allTenders), len(tenders))
-	}
-
-	// ensure service type condition works correctly
-	tenders, err = repo.GetTenders(ctx, 0, 0, "", "", []models.ServiceType{models.STConstruction})
-	if err != nil {
-		t.Fatalf("Could not get tenders: %s", err)
-	}
-
-	if len(allTenders) == len(tenders) {
-		t.Fatal("Received complete tenders list, despite service type condition")
-	}
-
-	// ensure pagination works correctly
-	// limit
-	for _, lim := range []int{1, len(allTenders) / 2, len(allTenders)} {
-		tenders, err = repo.GetTenders(ctx, lim, 0, "", "", nil)
-		if err != nil {
-			t.Fatalf("Could not get tenders: %s", err)
-		}
-
-		if len(tenders) != lim {
-			t.Fatalf("Received wrong amount of tenders with limit set: expected %d, got %d", lim, len(tenders))
-		}
-	}
-
-	// offset
-	for _, off := range []int{1, len(allTenders) / 2, len(allTenders)} {
-		tenders, err = repo.GetTenders(ctx, 0, off, "", "", nil)
-		if err != nil {
-			t.Fatalf("Could not get tenders: %s", err)
-		}
-
-		if len(tenders) != len(allTenders)-off {
-			t.Fatalf("Received wrong amount of tenders with limit set: expected %d, got %d", len(allTenders)-off, len(tenders))
-		}
-	}
-
-	// both
-	for _, n := range []int{1, len(allTenders) / 2, len(allTenders)} {
-		tenders, err = repo.GetTenders(ctx, n, n, "", "", nil)
-		if err != nil {
-			t.Fatalf("Could not get tenders: %s", err)
-		}
-
-		expectedLen := maxInt(len(allTenders)-n, n)
-		if len(tenders) != expectedLen {
-			t.Fatalf("Received wrong amount of tenders with limit set: expected %d, got %d", expectedLen, len(tenders))
-		}
-	}
-}
-
-func TestUpdateTender(t *testing.T) {
-	ctx := context.Background()
-	repo := OpenTestRepo(t)
-	defer repo.Close()
-
-	employees := InsertTestInitData(t, repo.db)
-	tenders := AddAllTenders(t, repo, employees)
-	if len(tenders) < 2 {
-		t.Fatalf("Expeceted at least 2 tenders to be created using result of InsertTestInitData, got %d", len(tenders))
-	}
-
-	tenders[0].Name = "Updated name"
-	err := repo.UpdateTender(ctx, tenders[0], true)
-	if err != nil {
-		t.Fatalf("Could not update tender: %s", err)
-	}
-
-	tender, err := repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if tender.Name != tenders[0].Name {
-		t.Errorf("Tender name have not been updated: expected '%s', got '%s'", tenders[0].Name, tender.Name)
-	}
-	if tender.Version != tenders[0].Version+1 {
-		t.Errorf("Wrong tender version after update: expected '%d', got '%d'", tenders[0].Version+1, tender.Version)
-	}
-
-	// Ensure version entry have been added
-	versions, err := repo.GetTenderVersions(ctx, tender.Id, tender.Version)
-	if err != nil {
-		t.Fatalf("Could not fetch tender version: %s", err)
-	}
-	if len(versions) == 0 {
-		t.Fatalf("Version entry have not been added after tender update")
-	}
-	if len(versions) > 1 {
-		t.Fatalf("Multiple entries with same version of specific tender found")
-	}
-	if versions[0].Id != tender.Id || versions[0].Name != tender.Name || versions[0].Version != tender.Version {
-		t.Fatalf("Version entry is invalid: expected:\n%v\ngot:\n%v", tender, versions[0])
-	}
-}
-
-//// Service
-
-func AddAllTenders(t *testing.T, repo *Repository, employees map[string][]string) []models.Tender {
-	var tenders []models.Tender
-	ctx := context.Background()
-
-	count := 0
-	for _, serviceType := range AllServiceTypes() {
-		for org, users := range employees {
-			for _, user := range users {
-				count++
-				tender, err := repo.AddTender(ctx, models.Tender{
-					Name:           fmt.Sprintf("Test tender %d - %s", count, serviceType),
-					Description:    "",
-					Status:         models.TenderCreated,
-					ServiceType:    serviceType,
-					Version:        1,
-					OrganizationId: org,
-					Author:         user,
-				})
-
-				if err != nil {
-					t.Fatalf("Could not create tender: %s", err)
-				}
-				tenders = append(tenders, tender)
-			}
-		}
-	}
-
-	return tenders
-}
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tenders/internal/models"
+	"testing"
+	"time"
+)
+
+func TestAddTender(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	// cleanup
+	for _, tender := range tenders {
+		err := repo.DeleteTender(ctx, tender.Id)
+		if err != nil {
+			t.Errorf("Could not delete tender %s: %s", tender.Id, err)
+		}
+	}
+}
+
+func TestGetTenders(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+
+	allTenders := AddAllTenders(t, repo, employees)
+	if len(allTenders) < len(AllServiceTypes()) {
+		t.Fatalf("Expeceted at least 2 tenders to be created using result of InsertTestInitData, got %d", len(allTenders))
+	}
+
+	// ensure tenders list without pagination and service type condition has all tenders
+	tenders, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{})
+	if err != nil {
+		t.Fatalf("Could not get tenders: %s", err)
+	}
+
+	if len(allTenders) != len(tenders) {
+		t.Fatalf("Amount of added and received tenders does not match: %d - %d", len(allTenders), len(tenders))
+	}
+
+	// ensure tenders list without pagination and with service type condition by all possible service types has all tenders
+	tenders, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{ServiceTypes: AllServiceTypes()})
+	if err != nil {
+		t.Fatalf("Could not get tenders: %s", err)
+	}
+
+	if len(allTenders) != len(tenders) {
+		t.Fatalf("Amount of added and received tenders does not match: %d - %d", len(allTenders), len(tenders))
+	}
+
+	// ensure service type condition works correctly
+	tenders, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{ServiceTypes: []models.ServiceType{models.STConstruction}})
+	if err != nil {
+		t.Fatalf("Could not get tenders: %s", err)
+	}
+
+	if len(allTenders) == len(tenders) {
+		t.Fatal("Received complete tenders list, despite service type condition")
+	}
+
+	// ensure pagination works correctly
+	// limit
+	for _, lim := range []int{1, len(allTenders) / 2, len(allTenders)} {
+		tenders, err = repo.GetTenders(ctx, lim, 0, models.TenderFilter{})
+		if err != nil {
+			t.Fatalf("Could not get tenders: %s", err)
+		}
+
+		if len(tenders) != lim {
+			t.Fatalf("Received wrong amount of tenders with limit set: expected %d, got %d", lim, len(tenders))
+		}
+	}
+
+	// offset
+	for _, off := range []int{1, len(allTenders) / 2, len(allTenders)} {
+		tenders, err = repo.GetTenders(ctx, 0, off, models.TenderFilter{})
+		if err != nil {
+			t.Fatalf("Could not get tenders: %s", err)
+		}
+
+		if len(tenders) != len(allTenders)-off {
+			t.Fatalf("Received wrong amount of tenders with limit set: expected %d, got %d", len(allTenders)-off, len(tenders))
+		}
+	}
+
+	// both
+	for _, n := range []int{1, len(allTenders) / 2, len(allTenders)} {
+		tenders, err = repo.GetTenders(ctx, n, n, models.TenderFilter{})
+		if err != nil {
+			t.Fatalf("Could not get tenders: %s", err)
+		}
+
+		expectedLen := maxInt(len(allTenders)-n, n)
+		if len(tenders) != expectedLen {
+			t.Fatalf("Received wrong amount of tenders with limit set: expected %d, got %d", expectedLen, len(tenders))
+		}
+	}
+}
+
+func TestUpdateTender(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	if len(tenders) < 2 {
+		t.Fatalf("Expeceted at least 2 tenders to be created using result of InsertTestInitData, got %d", len(tenders))
+	}
+
+	tenders[0].Name = "Updated name"
+	err := repo.UpdateTender(ctx, tenders[0], true)
+	if err != nil {
+		t.Fatalf("Could not update tender: %s", err)
+	}
+
+	tender, err := repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Name != tenders[0].Name {
+		t.Errorf("Tender name have not been updated: expected '%s', got '%s'", tenders[0].Name, tender.Name)
+	}
+	if tender.Version != tenders[0].Version+1 {
+		t.Errorf("Wrong tender version after update: expected '%d', got '%d'", tenders[0].Version+1, tender.Version)
+	}
+
+	// Ensure version entry have been added
+	versions, err := repo.GetTenderVersions(ctx, tender.Id, tender.Version)
+	if err != nil {
+		t.Fatalf("Could not fetch tender version: %s", err)
+	}
+	if len(versions) == 0 {
+		t.Fatalf("Version entry have not been added after tender update")
+	}
+	if len(versions) > 1 {
+		t.Fatalf("Multiple entries with same version of specific tender found")
+	}
+	if versions[0].Id != tender.Id || versions[0].Name != tender.Name || versions[0].Version != tender.Version {
+		t.Fatalf("Version entry is invalid: expected:\n%v\ngot:\n%v", tender, versions[0])
+	}
+
+	// History is ordered by version and paginated
+	history, err := repo.GetTenderVersionHistory(ctx, tender.Id, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Version != 1 || history[1].Version != 2 || history[1].Name != "Updated name" {
+		t.Errorf("Unexpected tender history: %v", history)
+	}
+	history, err = repo.GetTenderVersionHistory(ctx, tender.Id, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Version != 2 {
+		t.Errorf("Expected second version only, got %v", history)
+	}
+
+	// Stale copy of tender does not overwrite its later version
+	tenders[0].Name = "Stale name"
+	err = repo.UpdateTender(ctx, tenders[0], true)
+	if !errors.Is(err, models.ErrVersionConflict) {
+		t.Fatalf("Expected version conflict on update of stale tender, got %v", err)
+	}
+	tender, err = repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Name != "Updated name" || tender.Version != 2 {
+		t.Errorf("Stale update is applied to tender: %v", tender)
+	}
+}
+
+func TestUpdateTenderOfFormerMember(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	var org string
+	for o := range employees {
+		org = o
+		break
+	}
+
+	user, err := repo.AddUser(ctx, models.User{Username: "Former member"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.DeleteUser(ctx, user.Id)
+	err = repo.AddMember(ctx, org, user.Id, models.RoleEditor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tender, err := repo.AddTender(ctx, models.Tender{
+		Name:           "Tender of former member",
+		Status:         models.TenderCreated,
+		ServiceType:    models.STConstruction,
+		OrganizationId: org,
+		Author:         user.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.DeleteTender(ctx, tender.Id)
+
+	ok, err := repo.RemoveMember(ctx, org, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("Expected member '%s' to be removed", user.Id)
+	}
+
+	// Tender stays editable by organization after its author leaves
+	tender.Name = "Updated name"
+	err = repo.UpdateTender(ctx, tender, true)
+	if err != nil {
+		t.Fatalf("Could not update tender of former member: %s", err)
+	}
+	tender, err = repo.GetTenderByUUID(ctx, tender.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Name != "Updated name" || tender.Version != 2 || tender.Author != user.Id {
+		t.Errorf("Expected tender to be updated keeping its author, got %v", tender)
+	}
+}
+
+func TestExpiredTenders(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	if len(tenders) < 3 {
+		t.Fatalf("Expeceted at least 3 tenders to be created using result of InsertTestInitData, got %d", len(tenders))
+	}
+
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	// only published tenders with passed deadline are expired
+	for i, deadline := range []*time.Time{&past, &future, &past} {
+		tenders[i].SubmissionDeadline = deadline
+		tenders[i].Status = models.TenderPublished
+		if i == 2 {
+			tenders[i].Status = models.TenderCreated
+		}
+		err := repo.UpdateTender(ctx, tenders[i], true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tender, err := repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.SubmissionDeadline == nil || !tender.SubmissionDeadline.Equal(past.Truncate(time.Microsecond)) {
+		t.Errorf("Expected submission deadline to be %s, got %v", past, tender.SubmissionDeadline)
+	}
+
+	expired, err := repo.GetExpiredTenders(ctx, now, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0].Id != tenders[0].Id {
+		t.Fatalf("Expected only tender '%s' to be expired, got %v", tenders[0].Id, expired)
+	}
+
+	_, ok, err := repo.CloseExpiredTender(ctx, tenders[1].Id, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("Tender with future deadline should not be closed")
+	}
+
+	closed, ok, err := repo.CloseExpiredTender(ctx, tenders[0].Id, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || closed.Status != models.TenderClosed || closed.Version != tender.Version+1 {
+		t.Fatalf("Expected expired tender to be closed with version %d, got %v", tender.Version+1, closed)
+	}
+
+	tender, err = repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Status != models.TenderClosed || tender.Version != closed.Version {
+		t.Errorf("Expected expired tender to be closed with version %d, got status %s and version %d", closed.Version, tender.Status, tender.Version)
+	}
+
+	versions, err := repo.GetTenderVersions(ctx, tender.Id, tender.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Status != models.TenderClosed {
+		t.Errorf("Expected version entry of closed tender, got %v", versions)
+	}
+}
+
+func TestScheduledTenders(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	tenders[0].PublishAt = &past
+	tenders[1].PublishAt = &future
+	for _, tender := range tenders[:2] {
+		err := repo.UpdateTender(ctx, tender, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// scheduled tenders are hidden from other organizations
+	all, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	visible, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{Restricted: true, VisibleTo: []string{tenders[0].OrganizationId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := len(all)
+	if tenders[1].OrganizationId != tenders[0].OrganizationId {
+		expected--
+	}
+	if len(visible) != expected {
+		t.Errorf("Expected %d of %d tenders to be visible, got %d", expected, len(all), len(visible))
+	}
+
+	scheduled, err := repo.GetScheduledTenders(ctx, now, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scheduled) != 1 || scheduled[0].Id != tenders[0].Id {
+		t.Fatalf("Expected only tender '%s' to be due for publication, got %v", tenders[0].Id, scheduled)
+	}
+
+	_, ok, err := repo.PublishScheduledTender(ctx, tenders[1].Id, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("Tender scheduled in the future should not be published")
+	}
+
+	tender, ok, err := repo.PublishScheduledTender(ctx, tenders[0].Id, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || tender.Status != models.TenderPublished || tender.PublishAt != nil || tender.Version != tenders[0].Version+1 {
+		t.Fatalf("Expected tender to be published with version %d, got %v", tenders[0].Version+1, tender)
+	}
+
+	versions, err := repo.GetTenderVersions(ctx, tender.Id, tender.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Status != models.TenderPublished {
+		t.Errorf("Expected version entry of published tender, got %v", versions)
+	}
+}
+
+func TestTenderBudgets(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	tenders[0].Budget = &models.Money{Amount: "100.25", Currency: "USD"}
+	tenders[1].Budget = &models.Money{Amount: "5000", Currency: "USD"}
+	tenders[1].BudgetConfidential = true
+	tenders[2].Budget = &models.Money{Amount: "300", Currency: "EUR"}
+	for _, tender := range tenders[:3] {
+		err := repo.UpdateTender(ctx, tender, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tender, err := repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Budget == nil || *tender.Budget != *tenders[0].Budget {
+		t.Fatalf("Expected budget %v, got %v", tenders[0].Budget, tender.Budget)
+	}
+	versions, err := repo.GetTenderVersions(ctx, tender.Id, tender.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Budget == nil || *versions[0].Budget != *tenders[0].Budget {
+		t.Errorf("Expected budget to be versioned, got %v", versions)
+	}
+
+	// sorted by amount, tenders without budget go last
+	sorted, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{
+		Budget: models.MoneyRange{Currency: "USD", Min: "100"},
+		Sort:   []models.SortField{{Field: "budget", Desc: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sorted) != 2 || sorted[0].Id != tenders[1].Id || sorted[1].Id != tenders[0].Id {
+		t.Fatalf("Expected USD tenders in descending order of budget, got %v", sorted)
+	}
+
+	// confidential budget is not matched for other organizations
+	restricted, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{
+		Budget:     models.MoneyRange{Currency: "USD"},
+		Restricted: true,
+		VisibleTo:  []string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restricted) != 1 || restricted[0].Id != tenders[0].Id {
+		t.Errorf("Expected only public USD budget to be matched, got %v", restricted)
+	}
+}
+
+func TestTenderSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+
+	tenders[0].Description = "Cement delivery to construction site"
+	tenders[1].Name = "Cement supply"
+	tenders[1].Description = "Cement of grade M500 for cement plant"
+	tenders[2].Description = "Bricks delivery"
+	for _, tender := range tenders[:3] {
+		err := repo.UpdateTender(ctx, tender, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// matches in name and repeated matches rank higher
+	found, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{Query: "cement"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Id != tenders[1].Id || found[1].Id != tenders[0].Id {
+		t.Fatalf("Expected tenders mentioning cement ordered by relevance, got %v", found)
+	}
+
+	found, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{Query: "delivery -cement", Sort: []models.SortField{{Field: "name"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Id != tenders[2].Id {
+		t.Fatalf("Expected single tender about delivery without cement, got %v", found)
+	}
+
+	// search is combined with other filters and pagination
+	found, err = repo.GetTenders(ctx, 1, 1, models.TenderFilter{Query: "cement", ServiceTypes: []models.ServiceType{tenders[0].ServiceType}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := 0
+	if tenders[1].ServiceType == tenders[0].ServiceType {
+		expected = 1
+	}
+	if len(found) != expected {
+		t.Fatalf("Expected %d tenders on second page of search results, got %v", expected, found)
+	}
+}
+
+func TestTenderFilters(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	start := time.Now().Add(-time.Minute)
+
+	tenders[0].Status = models.TenderPublished
+	tenders[1].Status = models.TenderClosed
+	for _, tender := range tenders[:2] {
+		err := repo.UpdateTender(ctx, tender, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{
+		Statuses: []models.TenderStatus{models.TenderPublished, models.TenderClosed},
+		Sort:     []models.SortField{{Field: "status", Desc: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Id != tenders[1].Id || found[1].Id != tenders[0].Id {
+		t.Fatalf("Expected closed and published tenders in descending order of status, got %v", found)
+	}
+
+	found, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{OrganizationId: tenders[0].OrganizationId, AuthorId: tenders[0].Author})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tender := range found {
+		if tender.OrganizationId != tenders[0].OrganizationId || tender.Author != tenders[0].Author {
+			t.Fatalf("Expected tenders of organization %s by %s only, got %v", tenders[0].OrganizationId, tenders[0].Author, tender)
+		}
+	}
+	if len(found) == 0 {
+		t.Fatalf("Expected tenders of organization %s to be found", tenders[0].OrganizationId)
+	}
+
+	future := time.Now().Add(time.Hour)
+	found, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{Created: models.TimeRange{From: &start, To: &future}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != len(tenders) {
+		t.Fatalf("Expected all %d tenders to be created within last minute, got %d", len(tenders), len(found))
+	}
+	found, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{Updated: models.TimeRange{From: &future}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 0 {
+		t.Fatalf("Expected no tenders to be updated in the future, got %v", found)
+	}
+
+	found, err = repo.GetTenders(ctx, 0, 0, models.TenderFilter{Sort: []models.SortField{{Field: "updatedAt", Desc: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(found); i++ {
+		if found[i].UpdatedAt.After(found[i-1].UpdatedAt) {
+			t.Fatalf("Expected tenders in descending order of update time, got %v", found)
+		}
+	}
+}
+
+//// Service
+
+func TestTenderOutcomes(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	if len(tenders) < 2 {
+		t.Fatalf("Expeceted at least 2 tenders to be created using result of InsertTestInitData, got %d", len(tenders))
+	}
+	bids := AddAllBids(t, ctx, repo, tenders[:1], employees)
+	if len(bids) == 0 {
+		t.Fatal("Expected bids to be created for tender")
+	}
+
+	tenders[0].Status = models.TenderAwarded
+	tenders[0].AwardedBidIds = []string{bids[0].Id}
+	tenders[1].Status = models.TenderCancelled
+	tenders[1].CancellationReason = "Budget withdrawn"
+	for _, tender := range tenders[:2] {
+		err := repo.UpdateTender(ctx, tender, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tender, err := repo.GetTenderByUUID(ctx, tenders[0].Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Status != models.TenderAwarded || len(tender.AwardedBidIds) != 1 || tender.AwardedBidIds[0] != bids[0].Id {
+		t.Errorf("Expected tender to be awarded to bid %s, got status %s and bids %v", bids[0].Id, tender.Status, tender.AwardedBidIds)
+	}
+
+	versions, err := repo.GetTenderVersions(ctx, tenders[1].Id, tenders[1].Version+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Status != models.TenderCancelled || versions[0].CancellationReason != "Budget withdrawn" {
+		t.Fatalf("Expected cancelled version with reason to be recorded, got %+v", versions)
+	}
+	if versions[0].AwardedBidIds != nil {
+		t.Errorf("Expected cancelled tender to have no awarded bids, got %v", versions[0].AwardedBidIds)
+	}
+}
+
+func AddAllTenders(t *testing.T, repo *Repository, employees map[string][]string) []models.Tender {
+	var tenders []models.Tender
+	ctx := context.Background()
+
+	count := 0
+	for _, serviceType := range AllServiceTypes() {
+		for org, users := range employees {
+			for _, user := range users {
+				count++
+				tender, err := repo.AddTender(ctx, models.Tender{
+					Name:           fmt.Sprintf("Test tender %d - %s", count, serviceType),
+					Description:    "",
+					Status:         models.TenderCreated,
+					ServiceType:    serviceType,
+					Version:        1,
+					OrganizationId: org,
+					Author:         user,
+				})
+
+				if err != nil {
+					t.Fatalf("Could not create tender: %s", err)
+				}
+				tenders = append(tenders, tender)
+			}
+		}
+	}
+
+	return tenders
+}
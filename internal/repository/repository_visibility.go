@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// SetInvitedOrganizations replaces organizations invited to tender, organizations invited before keep their invitation time
+func (repo *Repository) SetInvitedOrganizations(ctx context.Context, tenderId string, organizationIds []string, invitedBy string) ([]models.InvitedOrganization, error) {
+	query := `
+	INSERT INTO tender_invited_organizations (tender_id, organization_id, invited_by)
+	VALUES
+		($1, $2, $3)
+	ON CONFLICT (tender_id, organization_id) DO NOTHING
+	`
+
+	var result []models.InvitedOrganization
+	err := repo.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := repo.conn(ctx).ExecContext(ctx, "DELETE FROM tender_invited_organizations WHERE tender_id = $1 AND NOT organization_id = any($2::uuid[])",
+			tenderId, pq.Array(organizationIds))
+		if err != nil {
+			return err
+		}
+
+		for _, organizationId := range organizationIds {
+			_, err = repo.conn(ctx).ExecContext(ctx, query, tenderId, organizationId, nullableUUID(invitedBy))
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = repo.GetInvitedOrganizations(ctx, tenderId)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.SetInvitedOrganizations: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetInvitedOrganizations returns organizations invited to tender in order they are invited
+func (repo *Repository) GetInvitedOrganizations(ctx context.Context, tenderId string) ([]models.InvitedOrganization, error) {
+	query := `
+	SELECT
+		inv.organization_id,
+		org.name,
+		inv.invited_by,
+		inv.created_at
+	FROM tender_invited_organizations AS inv
+	JOIN organization AS org ON org.id = inv.organization_id
+	WHERE inv.tender_id = $1
+	ORDER BY inv.created_at, org.name
+	`
+
+	rows, err := repo.conn(ctx).QueryContext(ctx, query, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("repository.Repository.GetInvitedOrganizations: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.InvitedOrganization{}
+	for rows.Next() {
+		var invited models.InvitedOrganization
+		var invitedBy interface{}
+		err = rows.Scan(&invited.OrganizationId, &invited.Name, &invitedBy, &invited.InvitedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository.Repository.GetInvitedOrganizations: rows scan failed: %w", err)
+		}
+		invited.InvitedBy = readUUID(invitedBy)
+		result = append(result, invited)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("repository.Repository.GetInvitedOrganizations: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+// TenderInvites reports whether any of organizations is invited to tender
+func (repo *Repository) TenderInvites(ctx context.Context, tenderId string, organizationIds []string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM tender_invited_organizations WHERE tender_id = $1 AND organization_id = any($2::uuid[])
+	)
+	`
+
+	var invited bool
+	err := repo.conn(ctx).QueryRowContext(ctx, query, tenderId, pq.Array(organizationIds)).Scan(&invited)
+	if err != nil {
+		return false, fmt.Errorf("repository.Repository.TenderInvites: %w", err)
+	}
+	return invited, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"tenders/internal/models"
+	"testing"
+)
+
+func TestTenderVisibility(t *testing.T) {
+	ctx := context.Background()
+	repo := OpenTestRepo(t)
+	defer repo.Close()
+
+	employees := InsertTestInitData(t, repo.db)
+	tenders := AddAllTenders(t, repo, employees)
+	if len(tenders) == 0 || tenders[0].Visibility != models.VisibilityPublic {
+		t.Fatalf("Expected tenders to be public by default, got %v", tenders)
+	}
+
+	// pick organizations other than owning one
+	tender := tenders[0]
+	var others []string
+	for org := range employees {
+		if org != tender.OrganizationId {
+			others = append(others, org)
+		}
+	}
+	if len(others) < 2 {
+		t.Fatalf("Expected at least 3 organizations to be created using InsertTestInitData, got %d", len(others)+1)
+	}
+	invitee, stranger := others[0], others[1]
+
+	tender.Visibility = models.VisibilityInviteOnly
+	err := repo.UpdateTender(ctx, tender, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repo.GetTenderByUUID(ctx, tender.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Visibility != models.VisibilityInviteOnly {
+		t.Fatalf("Expected tender to be invite-only, got %s", stored.Visibility)
+	}
+
+	invited, err := repo.SetInvitedOrganizations(ctx, tender.Id, []string{invitee, stranger}, employees[tender.OrganizationId][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invited) != 2 {
+		t.Fatalf("Expected 2 organizations to be invited, got %v", invited)
+	}
+	invited, err = repo.SetInvitedOrganizations(ctx, tender.Id, []string{invitee}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invited) != 1 || invited[0].OrganizationId != invitee || invited[0].InvitedBy != employees[tender.OrganizationId][0] {
+		t.Fatalf("Expected only first organization to stay invited by its original author, got %v", invited)
+	}
+
+	for org, expected := range map[string]bool{invitee: true, stranger: false} {
+		ok, err := repo.TenderInvites(ctx, tender.Id, []string{org})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != expected {
+			t.Errorf("Expected invitation of organization %s to be %t, got %t", org, expected, ok)
+		}
+
+		listed, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{Restricted: true, VisibleTo: []string{org}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := slices.ContainsFunc(listed, func(listed models.Tender) bool { return listed.Id == tender.Id })
+		if found != expected {
+			t.Errorf("Expected invite-only tender to be listed to organization %s: %t, got %t", org, expected, found)
+		}
+	}
+
+	listed, err := repo.GetTenders(ctx, 0, 0, models.TenderFilter{Restricted: true, VisibleTo: []string{tender.OrganizationId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(listed, func(listed models.Tender) bool { return listed.Id == tender.Id }) {
+		t.Errorf("Expected invite-only tender to be listed to owning organization")
+	}
+}
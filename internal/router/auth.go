@@ -0,0 +1,98 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (models.User, error)
+	AuthenticateLegacy(ctx context.Context, username string) (models.User, error)
+	AuthenticateAPIKey(ctx context.Context, secret string) (models.APIKey, error)
+}
+
+// authMiddleware resolves bearer token (or username, if legacy authentication is enabled)
+// into models.User, or X-API-Key header into models.APIKey, and stores it in request context.
+// Requests without credentials are passed as is, since it is up to service to decide
+// whether anonymous access is allowed.
+func authMiddleware(next http.Handler, a Authenticator, legacy bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var user models.User
+		var err error
+		withUser := auth.WithUser
+
+		if secret := r.Header.Get("X-API-Key"); len(secret) > 0 {
+			key, err := a.AuthenticateAPIKey(r.Context(), secret)
+			if err != nil {
+				log.Println("router.authMiddleware:", err)
+				unauthorized(w, "supplied api key is invalid, expired or revoked")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithAPIKey(r.Context(), key)))
+			return
+		}
+
+		if header := r.Header.Get("Authorization"); len(header) > 0 {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				unauthorized(w, "malformed authorization header")
+				return
+			}
+			user, err = a.Authenticate(r.Context(), token)
+		} else if username := legacyUsername(r); legacy && len(username) > 0 {
+			user, err = a.AuthenticateLegacy(r.Context(), username)
+			withUser = auth.WithLegacyUser
+		} else {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err != nil {
+			log.Println("router.authMiddleware:", err)
+			unauthorized(w, "user does not exist or supplied credentials are invalid")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
+	})
+}
+
+// legacyUsername looks for username in query parameters, or in body of tender creation request
+func legacyUsername(r *http.Request) string {
+	query := r.URL.Query()
+	for _, key := range []string{"username", "requesterUsername"} {
+		if username := query.Get(key); len(username) > 0 {
+			return username
+		}
+	}
+
+	if r.Method != http.MethodPost || r.URL.Path != "/api/tenders/new" || r.Body == nil {
+		return ""
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+
+	body := struct {
+		Username string `json:"creatorUsername"`
+	}{}
+	json.Unmarshal(data, &body)
+	return body.Username
+}
+
+func unauthorized(w http.ResponseWriter, reason string) {
+	w.WriteHeader(http.StatusUnauthorized)
+	data, _ := json.Marshal(map[string]string{"reason": reason})
+	w.Write(data)
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+	"tenders/internal/auth"
+)
+
+// requestIDMiddleware reuses X-Request-Id header supplied by client (or proxy), or generates
+// new one, and stores it in request context, so that it could be written to audit log
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if len(id) == 0 || len(id) > 64 {
+			// request is served without id, if generation fails
+			id, _ = auth.RandomSecret(16)
+		}
+
+		if len(id) > 0 {
+			w.Header().Set("X-Request-Id", id)
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithRequestID(r.Context(), id)))
+	})
+}
@@ -1,50 +1,125 @@
-package router
-
-import (
-	"net/http"
-	"tenders/internal/controller"
-)
-
-func NewRouter(c *controller.Controller) http.Handler {
-	mux := http.NewServeMux()
-
-	mux.HandleFunc("GET /api/ping", c.Ping)
-	mux.HandleFunc("GET /api/tenders", c.GetTenders)
-	mux.HandleFunc("POST /api/tenders/new", c.NewTender)
-	mux.HandleFunc("GET /api/tenders/my", c.MyTenders)
-	mux.HandleFunc("GET /api/tenders/{tenderId}/status", c.TenderStatus)
-	mux.HandleFunc("PUT /api/tenders/{tenderId}/status", c.SetTenderStatus)
-	mux.HandleFunc("PATCH /api/tenders/{tenderId}/edit", c.EditTender)
-	mux.HandleFunc("PUT /api/tenders/{tenderId}/rollback/{version}", c.RollbackTender)
-	mux.HandleFunc("POST /api/bids/new", c.NewBid)
-	mux.HandleFunc("GET /api/bids/my", c.MyBids)
-	mux.HandleFunc("GET /api/bids/{tenderId}/list", c.TenderBids)
-	mux.HandleFunc("GET /api/bids/{bidId}/status", c.BidStatus)
-	mux.HandleFunc("PUT /api/bids/{bidId}/status", c.SetBidStatus)
-	mux.HandleFunc("PATCH /api/bids/{bidId}/edit", c.EditBid)
-	mux.HandleFunc("PUT /api/bids/{bidId}/submit_decision", c.BidDecision)
-	mux.HandleFunc("PUT /api/bids/{bidId}/feedback", c.BidReview)
-	mux.HandleFunc("PUT /api/bids/{bidId}/rollback/{version}", c.BidRollback)
-	mux.HandleFunc("GET /api/bids/{tenderId}/reviews", c.GetBidReviews)
-
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("page not found"))
-	})
-
-	cors := http.NewServeMux()
-	cors.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("Accept", "*/*")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-		} else {
-			mux.ServeHTTP(w, r)
-		}
-	})
-
-	return cors
-}
+package router
+
+import (
+	"net/http"
+	"tenders/internal/config"
+	"tenders/internal/controller"
+)
+
+func NewRouter(c *controller.Controller, a Authenticator, cfg *config.AuthConfig) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/ping", c.Ping)
+	mux.HandleFunc("POST /api/auth/token", c.Login)
+	mux.HandleFunc("PUT /api/auth/password", c.SetPassword)
+	mux.HandleFunc("POST /api/auth/password/reset", c.ResetPassword)
+	mux.HandleFunc("GET /api/tenders", c.GetTenders)
+	mux.HandleFunc("POST /api/tenders/new", c.NewTender)
+	mux.HandleFunc("GET /api/tenders/my", c.MyTenders)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/status", c.TenderStatus)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/status", c.SetTenderStatus)
+	mux.HandleFunc("PATCH /api/tenders/{tenderId}/edit", c.EditTender)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/rollback/{version}", c.RollbackTender)
+	mux.HandleFunc("POST /api/tenders/{tenderId}/open", c.OpenTenderBids)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/amendments", c.TenderAmendments)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/versions", c.TenderVersions)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/versions/diff", c.TenderVersionsDiff)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/bid_events", c.BidEvents)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/criteria", c.TenderCriteria)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/criteria", c.SetTenderCriteria)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/lots", c.TenderLots)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/lots", c.SetTenderLots)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/lots/{lotId}/status", c.SetLotStatus)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/auction", c.SetTenderAuction)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/auction", c.TenderAuction)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/auction/ranking", c.AuctionRanking)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/auction/ledger", c.AuctionLedger)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/invited_organizations", c.InvitedOrganizations)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/invited_organizations", c.SetInvitedOrganizations)
+	mux.HandleFunc("POST /api/tenders/{tenderId}/clone", c.CloneTender)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/attachments", c.TenderAttachments)
+	mux.HandleFunc("POST /api/tenders/{tenderId}/attachments", c.NewTenderAttachment)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/attachments/{attachmentId}", c.TenderAttachment)
+	mux.HandleFunc("DELETE /api/tenders/{tenderId}/attachments/{attachmentId}", c.DeleteTenderAttachment)
+	mux.HandleFunc("POST /api/tenders/{tenderId}/questions", c.AskQuestion)
+	mux.HandleFunc("GET /api/tenders/{tenderId}/questions", c.TenderQuestions)
+	mux.HandleFunc("PUT /api/tenders/{tenderId}/questions/{questionId}/answer", c.AnswerQuestion)
+	mux.HandleFunc("GET /api/categories", c.Categories)
+	mux.HandleFunc("POST /api/categories", c.NewCategory)
+	mux.HandleFunc("PATCH /api/categories/{code}", c.EditCategory)
+	mux.HandleFunc("POST /api/bids/new", c.NewBid)
+	mux.HandleFunc("GET /api/bids/my", c.MyBids)
+	mux.HandleFunc("GET /api/bids/{tenderId}/list", c.TenderBids)
+	mux.HandleFunc("GET /api/bids/{bidId}/status", c.BidStatus)
+	mux.HandleFunc("PUT /api/bids/{bidId}/status", c.SetBidStatus)
+	mux.HandleFunc("PATCH /api/bids/{bidId}/edit", c.EditBid)
+	mux.HandleFunc("PUT /api/bids/{bidId}/reconfirm", c.ReconfirmBid)
+	mux.HandleFunc("PUT /api/bids/{bidId}/withdraw", c.WithdrawBid)
+	mux.HandleFunc("PUT /api/bids/{bidId}/resubmit", c.ResubmitBid)
+	mux.HandleFunc("PUT /api/bids/{bidId}/submit_decision", c.BidDecision)
+	mux.HandleFunc("PUT /api/bids/{bidId}/feedback", c.BidReview)
+	mux.HandleFunc("PUT /api/bids/{bidId}/rollback/{version}", c.BidRollback)
+	mux.HandleFunc("GET /api/bids/{bidId}/versions", c.BidVersions)
+	mux.HandleFunc("GET /api/bids/{bidId}/versions/diff", c.BidVersionsDiff)
+	mux.HandleFunc("GET /api/bids/{tenderId}/reviews", c.GetBidReviews)
+	mux.HandleFunc("PUT /api/bids/{bidId}/scores", c.ScoreBid)
+	mux.HandleFunc("GET /api/bids/{bidId}/scores", c.BidScores)
+	mux.HandleFunc("GET /api/bids/{tenderId}/ranking", c.TenderRanking)
+	mux.HandleFunc("POST /api/bids/{bidId}/auction/price", c.PlaceAuctionPrice)
+	mux.HandleFunc("GET /api/bids/{bidId}/attachments", c.BidAttachments)
+	mux.HandleFunc("POST /api/bids/{bidId}/attachments", c.NewBidAttachment)
+	mux.HandleFunc("GET /api/bids/{bidId}/attachments/{attachmentId}", c.BidAttachment)
+	mux.HandleFunc("DELETE /api/bids/{bidId}/attachments/{attachmentId}", c.DeleteBidAttachment)
+	mux.HandleFunc("POST /api/employees", c.NewEmployee)
+	mux.HandleFunc("GET /api/employees", c.Employees)
+	mux.HandleFunc("GET /api/employees/{employeeId}", c.Employee)
+	mux.HandleFunc("PATCH /api/employees/{employeeId}", c.EditEmployee)
+	mux.HandleFunc("DELETE /api/employees/{employeeId}", c.DeleteEmployee)
+	mux.HandleFunc("POST /api/organizations", c.NewOrganization)
+	mux.HandleFunc("GET /api/organizations", c.Organizations)
+	mux.HandleFunc("GET /api/organizations/{organizationId}", c.Organization)
+	mux.HandleFunc("PATCH /api/organizations/{organizationId}", c.EditOrganization)
+	mux.HandleFunc("DELETE /api/organizations/{organizationId}", c.DeleteOrganization)
+	mux.HandleFunc("GET /api/organizations/{organizationId}/members", c.Members)
+	mux.HandleFunc("PUT /api/organizations/{organizationId}/members/{employeeId}", c.SetMemberRole)
+	mux.HandleFunc("DELETE /api/organizations/{organizationId}/members/{employeeId}", c.RemoveMember)
+	mux.HandleFunc("POST /api/organizations/{organizationId}/members/{employeeId}/password_reset", c.ResetMemberPassword)
+	mux.HandleFunc("POST /api/organizations/{organizationId}/invitations", c.NewInvitation)
+	mux.HandleFunc("GET /api/organizations/{organizationId}/invitations", c.Invitations)
+	mux.HandleFunc("DELETE /api/organizations/{organizationId}/invitations/{invitationId}", c.RevokeInvitation)
+	mux.HandleFunc("GET /api/invitations/my", c.MyInvitations)
+	mux.HandleFunc("POST /api/invitations/accept", c.AcceptInvitation)
+	mux.HandleFunc("POST /api/invitations/decline", c.DeclineInvitation)
+	mux.HandleFunc("POST /api/organizations/{organizationId}/api_keys", c.NewAPIKey)
+	mux.HandleFunc("GET /api/organizations/{organizationId}/api_keys", c.APIKeys)
+	mux.HandleFunc("DELETE /api/organizations/{organizationId}/api_keys/{keyId}", c.RevokeAPIKey)
+	mux.HandleFunc("GET /api/organizations/{organizationId}/templates", c.Templates)
+	mux.HandleFunc("POST /api/organizations/{organizationId}/templates", c.NewTemplate)
+	mux.HandleFunc("DELETE /api/organizations/{organizationId}/templates/{templateId}", c.DeleteTemplate)
+	mux.HandleFunc("GET /api/audit", c.AuditLog)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("page not found"))
+	})
+
+	handler := authMiddleware(mux, a, cfg.LegacyUsernameAuth)
+	handler = requestIDMiddleware(handler)
+
+	cors := http.NewServeMux()
+	cors.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-Id, If-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+		w.Header().Set("Accept", "*/*")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			handler.ServeHTTP(w, r)
+		}
+	})
+
+	return cors
+}
@@ -0,0 +1,113 @@
+package sealing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// KeySize is size of master key and of keys of sealed tenders in bytes, keys are AES-256 ones
+const KeySize = 32
+
+var ErrMalformed = errors.New("sealed data is malformed or sealed with another key")
+
+// Sealer encrypts bid contents of sealed tenders with per-tender keys. Keys are generated by Sealer and
+// kept wrapped by its master key, so that database alone is not enough to read sealed contents
+type Sealer struct {
+	master cipher.AEAD
+}
+
+func NewSealer(masterKey []byte) (*Sealer, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("sealing.NewSealer: %w", err)
+	}
+	return &Sealer{master: aead}, nil
+}
+
+// NewKey generates key of tender identified by id and returns it wrapped by master key
+func (s *Sealer) NewKey(id string) ([]byte, error) {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("sealing.Sealer.NewKey: %w", err)
+	}
+
+	wrapped, err := seal(s.master, key, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("sealing.Sealer.NewKey: %w", err)
+	}
+	return wrapped, nil
+}
+
+// Seal encrypts data with wrapped key of tender identified by id
+func (s *Sealer) Seal(wrappedKey []byte, id string, data []byte) ([]byte, error) {
+	aead, err := s.unwrap(wrappedKey, id)
+	if err != nil {
+		return nil, fmt.Errorf("sealing.Sealer.Seal: %w", err)
+	}
+
+	sealed, err := seal(aead, data, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("sealing.Sealer.Seal: %w", err)
+	}
+	return sealed, nil
+}
+
+// Open decrypts data sealed with wrapped key of tender identified by id
+func (s *Sealer) Open(wrappedKey []byte, id string, sealed []byte) ([]byte, error) {
+	aead, err := s.unwrap(wrappedKey, id)
+	if err != nil {
+		return nil, fmt.Errorf("sealing.Sealer.Open: %w", err)
+	}
+
+	data, err := open(aead, sealed, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("sealing.Sealer.Open: %w", err)
+	}
+	return data, nil
+}
+
+func (s *Sealer) unwrap(wrappedKey []byte, id string) (cipher.AEAD, error) {
+	key, err := open(s.master, wrappedKey, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key should be %d bytes long, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data with random nonce, which is prepended to result. Additional data binds result
+// to tender it belongs to, so that it can not be moved to another one
+func seal(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, additional), nil
+}
+
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return data, nil
+}
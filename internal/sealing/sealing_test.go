@@ -0,0 +1,79 @@
+package sealing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSealer(t *testing.T) {
+	master := make([]byte, KeySize)
+	_, err := rand.Read(master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSealer(master)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := s.NewKey("tender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"name": "bid", "price": {"amount": "100", "currency": "USD"}}`)
+	sealed, err := s.Seal(key, "tender", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, []byte("bid")) {
+		t.Errorf("Sealed data contains plain text: %q", sealed)
+	}
+
+	opened, err := s.Open(key, "tender", sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, data) {
+		t.Errorf("Expected opened data to be %q, got %q", data, opened)
+	}
+
+	// sealed data is bound to its tender and key
+	_, err = s.Open(key, "another tender", sealed)
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected data of another tender to be rejected, got %v", err)
+	}
+	other, err := s.NewKey("tender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Open(other, "tender", sealed)
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected data sealed with another key to be rejected, got %v", err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	_, err = s.Open(key, "tender", sealed)
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected tampered data to be rejected, got %v", err)
+	}
+
+	// keys are only usable with master key they are wrapped by
+	_, err = rand.Read(master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = NewSealer(master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Seal(key, "tender", data)
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected key wrapped by another master key to be rejected, got %v", err)
+	}
+
+	_, err = NewSealer([]byte("short"))
+	if err == nil {
+		t.Errorf("Expected short master key to be rejected")
+	}
+}
@@ -1,757 +1,1281 @@
-package service
-
-import (
-	"context"
-	"database/sql"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"tenders/internal/models"
-	"tenders/internal/repository"
-)
-
-type Service struct {
-	repo *repository.Repository
-}
-
-func NewService(repo *repository.Repository) *Service {
-	return &Service{repo: repo}
-}
-
-//// Tenders
-
-func (s *Service) GetTenders(ctx context.Context, limit, offset int, tenderId, userId string, serviceType []models.ServiceType) ([]models.Tender, error) {
-	tenders, err := s.repo.GetTenders(ctx, limit, offset, tenderId, userId, serviceType)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetTenders: %w", err)
-	}
-	return tenders, nil
-}
-
-func (s *Service) AddTender(ctx context.Context, username string, tender models.Tender) (models.Tender, error) {
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return tender, fmt.Errorf("service.Service.AddTender: %w", err)
-	}
-
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return tender, fmt.Errorf("service.Service.AddTender: %w", err)
-	}
-	if !valid {
-		return tender, fmt.Errorf("service.Service.AddTender: %w: %s", models.ErrForbidden, username)
-	}
-
-	tender.Author = user.Id
-	tender, err = s.repo.AddTender(ctx, tender)
-	if err != nil {
-		return tender, fmt.Errorf("service.Service.AddTender: %w", err)
-	}
-
-	return tender, nil
-}
-
-func (s *Service) GetUserTenders(ctx context.Context, username string, limit, offset int) ([]models.Tender, error) {
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetUserTenders: %w", err)
-	}
-
-	tenders, err := s.repo.GetTenders(ctx, limit, offset, "", user.Id, nil)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetUserTenders: %w", err)
-	}
-
-	return tenders, nil
-}
-
-func (s *Service) GetTenderStatus(ctx context.Context, username, tenderId string) (models.TenderStatus, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return "", fmt.Errorf("service.Service.GetTenderStatus: %w", err)
-	}
-
-	// get tender
-	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return "", fmt.Errorf("service.Service.GetTenderStatus: %w", models.ErrNoTender)
-	} else if err != nil {
-		return "", fmt.Errorf("service.Service.GetTenderStatus: %w", err)
-	}
-
-	// check whether user is employee of organization or not
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return "", fmt.Errorf("service.Service.GetTenderStatus: %w", err)
-	}
-
-	// if user is employee of organization owning tender, or tender is public, return status
-	if valid || tender.Status == models.TenderPublished {
-		return tender.Status, nil
-	}
-
-	return "", models.ErrForbidden
-}
-
-func (s *Service) SetTenderStatus(ctx context.Context, username, tenderId string, status models.TenderStatus) (models.Tender, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
-	}
-
-	// get tender
-	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", models.ErrNoTender)
-	} else if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
-	}
-
-	// check whether user is employee of organization or not
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
-	}
-
-	// check tender status
-	if tender.Status == models.TenderClosed && status != models.TenderClosed {
-		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", models.ErrTenderFinalized)
-	}
-
-	// if user is employee of organization owning tender, change status
-	if valid {
-		tender.Status = status
-		s.repo.UpdateTender(ctx, tender, status != models.TenderClosed)
-		return tender, nil
-	}
-
-	return models.Tender{}, models.ErrForbidden
-}
-
-func (s *Service) EditTender(ctx context.Context, username, tenderId string, changes map[string]string) (models.Tender, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
-	}
-
-	// get tender
-	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", models.ErrNoTender)
-	} else if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
-	}
-
-	// check whether user is employee of organization or not
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
-	}
-
-	// if user is not employee of organization owning tender, return error
-	if !valid {
-		return models.Tender{}, models.ErrForbidden
-	}
-
-	// check tender status
-	if tender.Status == models.TenderClosed {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", models.ErrTenderFinalized)
-	}
-
-	// remarshal changes into tender
-	data, err := json.Marshal(changes)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
-	}
-	err = json.Unmarshal(data, &tender)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
-	}
-
-	// update tender
-	err = s.repo.UpdateTender(ctx, tender, true)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
-	}
-
-	return tender, nil
-}
-
-func (s *Service) RollbackTender(ctx context.Context, username, tenderId string, version int) (models.Tender, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
-	}
-
-	// get tender
-	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", models.ErrNoTender)
-	} else if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
-	}
-
-	// check whether user is employee of organization or not
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
-	}
-
-	// if user is not employee of organization owning tender, return error
-	if !valid {
-		return models.Tender{}, models.ErrForbidden
-	}
-
-	// check version number
-	if version < 1 || version > tender.Version {
-		return models.Tender{}, models.ErrNoVersion
-	}
-	if version == tender.Version {
-		return tender, nil
-	}
-
-	// check tender status
-	if tender.Status == models.TenderClosed {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", models.ErrTenderFinalized)
-	}
-
-	// find version and do rollback
-	versions, err := s.repo.GetTenderVersions(ctx, tender.Id, version)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
-	}
-	if len(versions) == 0 {
-		return models.Tender{}, models.ErrNoVersion
-	}
-
-	versions[0].Version = tender.Version
-	err = s.repo.UpdateTender(ctx, versions[0], true)
-	if err != nil {
-		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
-	}
-	versions[0].Version++
-
-	return versions[0], nil
-}
-
-//// Bids
-
-func (s *Service) AddBid(ctx context.Context, bid models.Bid) (models.Bid, error) {
-	// check if username exists
-	err := s.setBidUserAndOrganization(ctx, &bid)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
-	}
-
-	// check if tender exists
-	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", models.ErrNoTender)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
-	}
-
-	// check if tender is open for proposals
-	if tender.Status != models.TenderPublished {
-		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", models.ErrNoTender)
-	}
-
-	// add bid
-	bid, err = s.repo.AddBid(ctx, bid)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
-	}
-
-	return bid, nil
-}
-
-func (s *Service) GetUserBids(ctx context.Context, username string, limit, offset int) ([]models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetUserBids: %w", err)
-	}
-
-	// get bids
-	bids, err := s.repo.GetBids(ctx, limit, offset, user.Id, "")
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetUserBids: %w", err)
-	}
-
-	return bids, nil
-}
-
-func (s *Service) GetTenderBids(ctx context.Context, username, tenderId string, limit, offset int) ([]models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
-	}
-
-	// get tender
-	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", models.ErrNoTender)
-	} else if err != nil {
-		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
-	}
-
-	// check whether user is employee of organization or not
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
-	}
-
-	// if user is not employee and tender is not public, forbid access
-	if !valid && tender.Status != models.TenderPublished {
-		return nil, models.ErrForbidden
-	}
-
-	// get bids
-	bids, err := s.repo.GetBids(ctx, limit, offset, "", tender.Id)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
-	}
-
-	return bids, nil
-}
-
-func (s *Service) GetBidStatus(ctx context.Context, username, bidId string) (models.BidStatus, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return "", fmt.Errorf("service.Service.GetBidStatus: %w", err)
-	}
-
-	// find bid
-	bid, err := s.repo.GetBidByUUID(ctx, bidId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return "", fmt.Errorf("service.Service.GetBidStatus: %w", models.ErrNoBid)
-	} else if err != nil {
-		return "", fmt.Errorf("service.Service.GetBidStatus: %w", err)
-	}
-
-	if bid.AuthorId == user.Id {
-		return bid.Status, nil
-	}
-
-	// find tender
-	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return "", fmt.Errorf("service.Service.GetBidStatus: %w", models.ErrNoTender)
-	} else if err != nil {
-		return "", fmt.Errorf("service.Service.GetBidStatus: %w", err)
-	}
-
-	// check whether user is employee of organization or not
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return "", fmt.Errorf("service.Service.GetBidStatus: %w", err)
-	}
-
-	if valid || tender.Status == models.TenderPublished {
-		return bid.Status, nil
-	}
-
-	return "", models.ErrForbidden
-}
-
-func (s *Service) SetBidStatus(ctx context.Context, username, bidId string, status models.BidStatus) (models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-	}
-
-	// find bid
-	bid, err := s.repo.GetBidByUUID(ctx, bidId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrNoBid)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-	}
-
-	// check bid's status
-	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
-		return models.Bid{}, models.ErrBidFinalized
-	}
-
-	// check whether user is bid's author or employee of organization owning bid
-	valid := false
-	if status == models.BidApproved || status == models.BidRejected {
-		// only for tender's owner
-		tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrNoTender)
-		} else if err != nil {
-			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-		}
-
-		valid, err = s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-		if err != nil {
-			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-		}
-
-		if valid && status == models.BidApproved {
-			m, err := s.repo.ApprovalCounts(ctx, bid.Id)
-			if err != nil {
-				return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-			}
-
-			if m[models.ATReject] > 0 {
-				return models.Bid{}, models.ErrBidFinalized
-			}
-
-			count, err := s.repo.EmployeeCount(ctx, tender.OrganizationId)
-			if err != nil {
-				return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-			}
-
-			if !(m[models.ATApprove] >= 3 || m[models.ATApprove] >= count) {
-				return models.Bid{}, models.ErrBidCannotBeApprovedYet
-			}
-		}
-	}
-
-	if !valid {
-		valid, err = s.userAllowedToEditBid(ctx, user, bid)
-		if err != nil {
-			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
-		}
-	}
-
-	if !valid {
-		return models.Bid{}, models.ErrForbidden
-	}
-
-	// update status
-	bid.Status = status
-	s.repo.UpdateBid(ctx, bid, true)
-	bid.Version++
-	return bid, nil
-}
-
-func (s *Service) EditBid(ctx context.Context, username, bidId string, changes map[string]string) (models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
-	}
-
-	// find bid
-	bid, err := s.repo.GetBidByUUID(ctx, bidId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", models.ErrNoBid)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
-	}
-
-	// check bid's status
-	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
-		return models.Bid{}, models.ErrBidFinalized
-	}
-
-	valid, err := s.userAllowedToEditBid(ctx, user, bid)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
-	}
-	if !valid {
-		return models.Bid{}, models.ErrForbidden
-	}
-
-	// remarshal changes into bid
-	data, err := json.Marshal(changes)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
-	}
-	err = json.Unmarshal(data, &bid)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
-	}
-
-	// update bid
-	err = s.repo.UpdateBid(ctx, bid, true)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
-	}
-	bid.Version++
-	return bid, nil
-}
-
-func (s *Service) BidApproval(ctx context.Context, username, bidId string, status models.ApproveType) (models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-	}
-
-	// find bid
-	bid, err := s.repo.GetBidByUUID(ctx, bidId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrNoBid)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-	}
-
-	// check bid's status
-	if bid.Status == models.BidCreated || bid.Status == models.BidCanceled {
-		return models.Bid{}, models.ErrForbidden
-	}
-
-	if bid.Status == models.BidApproved && status != models.ATApprove ||
-		bid.Status == models.BidRejected && status != models.ATReject {
-		return models.Bid{}, models.ErrBidFinalized
-	}
-
-	// ensure user has rights to approve bid (employee of organization owning tender)
-	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrNoTender)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-	}
-
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-	}
-	if !valid {
-		return models.Bid{}, models.ErrForbidden
-	}
-
-	// add approval
-	err = s.repo.AddBidApproval(ctx, bidId, user.Id, status)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-	}
-
-	// count approvals and change bid / tender status
-	counts, err := s.repo.ApprovalCounts(ctx, bid.Id)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-	}
-
-	if counts[models.ATReject] > 0 {
-		// if at least 1 reject present, mark bid as rejected
-		bid.Status = models.BidRejected
-		err = s.repo.UpdateBid(ctx, bid, false)
-		if err != nil {
-			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-		}
-
-	} else if counts[models.ATApprove] > 0 {
-		// if at least 1 approve present, check approve count against employee count and change statuses if necessary
-		n, err := s.repo.EmployeeCount(ctx, tender.OrganizationId)
-		if err != nil {
-			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-		}
-
-		if counts[models.ATApprove] >= 3 || counts[models.ATApprove] >= n {
-			bid.Status = models.BidApproved
-			err = s.repo.UpdateBid(ctx, bid, false)
-			if err != nil {
-				return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-			}
-
-			tender.Status = models.TenderClosed
-			err = s.repo.UpdateTender(ctx, tender, false)
-			if err != nil {
-				return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
-			}
-		}
-	}
-
-	return bid, nil
-}
-
-func (s *Service) BidFeedback(ctx context.Context, username, bidId, feedback string) (models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
-	}
-
-	// find bid
-	bid, err := s.repo.GetBidByUUID(ctx, bidId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", models.ErrNoBid)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
-	}
-
-	// find tender
-	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", models.ErrNoTender)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
-	}
-
-	// if user is not employee of organization owning tender - forbid action
-	valid, err := s.repo.UserValid(ctx, user.Id, tender.OrganizationId)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
-	}
-	if !valid {
-		return models.Bid{}, models.ErrForbidden
-	}
-
-	err = s.repo.AddReview(ctx, models.BidReview{
-		BidId:       bid.Id,
-		UserId:      user.Id,
-		Description: feedback,
-	})
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
-	}
-
-	return bid, nil
-}
-
-func (s *Service) BidRollback(ctx context.Context, username, bidId string, version int) (models.Bid, error) {
-	// check if username exists
-	user, err := s.userByUsername(ctx, username)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
-	}
-
-	// find bid
-	bid, err := s.repo.GetBidByUUID(ctx, bidId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", models.ErrNoBid)
-	} else if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
-	}
-
-	// check bid's status
-	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
-		return models.Bid{}, models.ErrBidFinalized
-	}
-
-	// check user's rights
-	valid, err := s.userAllowedToEditBid(ctx, user, bid)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
-	}
-	if !valid {
-		return models.Bid{}, models.ErrForbidden
-	}
-
-	// find version
-	versions, err := s.repo.GetBidVersions(ctx, bid.Id, version)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
-	}
-	if len(versions) == 0 {
-		return models.Bid{}, models.ErrNoVersion
-	}
-
-	// update bid
-	versions[0].Version = bid.Version
-	err = s.repo.UpdateBid(ctx, versions[0], true)
-	if err != nil {
-		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
-	}
-	versions[0].Version++
-
-	return versions[0], nil
-}
-
-func (s *Service) PastUserBidsReviews(ctx context.Context, tenderId, requesterName, authorName string, limit, offset int) ([]models.BidReview, error) {
-	// check users
-	requester, err := s.userByUsername(ctx, requesterName)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
-	}
-	author, err := s.userByUsername(ctx, authorName)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
-	}
-
-	// get tender
-	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", models.ErrNoTender)
-	} else if err != nil {
-		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
-	}
-
-	// check if requester is actually employee of organization owning tender
-	valid, err := s.repo.UserValid(ctx, requester.Id, tender.OrganizationId)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
-	}
-	if !valid {
-		return nil, models.ErrForbidden
-	}
-
-	// get reviews
-	reviews, err := s.repo.GetReviews(ctx, limit, offset, "", "", author.Id)
-	if err != nil {
-		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
-	}
-	return reviews, nil
-}
-
-//// Service
-
-func (s *Service) userAllowedToEditBid(ctx context.Context, user models.User, bid models.Bid) (bool, error) {
-	var err error
-
-	valid := false
-	if bid.AuthorId == user.Id {
-		valid = true
-
-	} else if bid.AuthorType == models.AuthorOrganization {
-		valid, err = s.repo.UserValid(ctx, user.Id, bid.AuthorId)
-		if err != nil {
-			return false, fmt.Errorf("service.Service.userAllowedToEditBid: %w", err)
-		}
-
-	} else {
-		valid, err = s.repo.UsersAreColleagues(ctx, user.Id, bid.AuthorId)
-		if err != nil {
-			return false, fmt.Errorf("service.Service.userAllowedToEditBid: %w", err)
-		}
-	}
-
-	return valid, err
-}
-
-func (s *Service) setBidUserAndOrganization(ctx context.Context, bid *models.Bid) error {
-	if bid.AuthorType == models.AuthorUser {
-		bid.UserId = bid.AuthorId
-		_, exist, err := s.repo.UserByUUID(ctx, bid.AuthorId)
-		if err != nil {
-			return fmt.Errorf("service.Service.setBidUserAndOrganization: %w", err)
-		}
-		if !exist {
-			return models.ErrInvalidUser
-		}
-	} else if bid.AuthorType == models.AuthorOrganization {
-		_, err := s.repo.OrganizationByUUID(ctx, bid.AuthorId)
-		if err == sql.ErrNoRows {
-			return models.ErrInvalidUser
-		} else if err != nil {
-			return fmt.Errorf("service.Service.setBidUserAndOrganization: %w", err)
-		}
-		bid.OrganizationId = bid.AuthorId
-	}
-
-	return nil
-}
-
-func (s *Service) userByUsername(ctx context.Context, username string) (models.User, error) {
-	user, ok, err := s.repo.UserByUsername(ctx, username)
-	if err != nil {
-		return models.User{}, fmt.Errorf("service.Service.userByUsername: %w", err)
-	}
-	if !ok {
-		return models.User{}, fmt.Errorf("service.Service.userByUsername: %w: %s", models.ErrInvalidUser, username)
-	}
-	return user, err
-}
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"tenders/internal/auth"
+	"tenders/internal/config"
+	"tenders/internal/models"
+	"tenders/internal/repository"
+	"tenders/internal/sealing"
+	"tenders/internal/storage"
+	"time"
+)
+
+type Service struct {
+	repo       *repository.Repository
+	storage    storage.Storage
+	cfg        *config.AuthConfig
+	storageCfg *config.StorageConfig
+
+	tokenSecret []byte
+	sealer      *sealing.Sealer
+}
+
+func NewService(repo *repository.Repository, store storage.Storage, cfg *config.AuthConfig, storageCfg *config.StorageConfig) (*Service, error) {
+	s := &Service{repo: repo, storage: store, cfg: cfg, storageCfg: storageCfg}
+
+	if len(cfg.JWTSecret) > 0 {
+		s.tokenSecret = []byte(cfg.JWTSecret)
+	} else {
+		secret, err := auth.RandomSecret(32)
+		if err != nil {
+			return nil, fmt.Errorf("service.NewService: %w", err)
+		}
+		log.Println("JWT_SECRET is not set, issued tokens will not survive restart")
+		s.tokenSecret = []byte(secret)
+	}
+
+	// random master key would not survive restart along with sealed bids, so sealing is disabled without configured one
+	if len(storageCfg.SealingKey) > 0 {
+		masterKey, err := base64.StdEncoding.DecodeString(storageCfg.SealingKey)
+		if err != nil {
+			return nil, fmt.Errorf("service.NewService: SEALING_KEY is not valid base64: %w", err)
+		}
+		s.sealer, err = sealing.NewSealer(masterKey)
+		if err != nil {
+			return nil, fmt.Errorf("service.NewService: %w", err)
+		}
+	} else {
+		log.Println("SEALING_KEY is not set, sealed tenders are not available")
+	}
+
+	return s, nil
+}
+
+//// Tenders
+
+func (s *Service) GetTenders(ctx context.Context, limit, offset int, filter models.TenderFilter) ([]models.Tender, error) {
+	// scheduled tenders and confidential budgets are only listed to employees (and API keys) of owning organization
+	orgs, err := s.principalOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenders: %w", err)
+	}
+
+	filter.Restricted = true
+	filter.VisibleTo = orgs
+	tenders, err := s.repo.GetTenders(ctx, limit, offset, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenders: %w", err)
+	}
+
+	hideConfidentialBudgets(tenders, orgs)
+	return tenders, nil
+}
+
+func (s *Service) AddTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	// check whether request is performed by employee or API key of organization
+	err := s.authorize(ctx, tender.OrganizationId, models.PermTenderCreate)
+	if err != nil {
+		return tender, fmt.Errorf("service.Service.AddTender: %s: %w", principalName(ctx), err)
+	}
+
+	// tenders created by API keys have no author
+	if user, ok := auth.UserFromContext(ctx); ok {
+		tender.Author = user.Id
+	}
+
+	if !validSchedule(tender) {
+		return tender, fmt.Errorf("service.Service.AddTender: %w", models.ErrInvalidSchedule)
+	}
+	if tender.Sealed && s.sealer == nil {
+		return tender, fmt.Errorf("service.Service.AddTender: %w", models.ErrSealingUnavailable)
+	}
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		tender, err = s.repo.AddTender(ctx, tender)
+		if err != nil {
+			return err
+		}
+		if tender.Sealed {
+			key, err := s.sealer.NewKey(tender.Id)
+			if err != nil {
+				return err
+			}
+			err = s.repo.AddTenderKey(ctx, tender.Id, key)
+			if err != nil {
+				return err
+			}
+		}
+		return s.auditTender(ctx, models.ActionTenderCreate, tender, nil, tender)
+	})
+	if err != nil {
+		return tender, fmt.Errorf("service.Service.AddTender: %w", err)
+	}
+
+	return tender, nil
+}
+
+func (s *Service) GetUserTenders(ctx context.Context, limit, offset int, filter models.TenderFilter) ([]models.Tender, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserTenders: %w", err)
+	}
+
+	// author could have left organization since, so confidential budgets are still restricted
+	orgs, err := s.principalOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserTenders: %w", err)
+	}
+
+	filter.AuthorId = user.Id
+	filter.Restricted = true
+	filter.VisibleTo = orgs
+	tenders, err := s.repo.GetTenders(ctx, limit, offset, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserTenders: %w", err)
+	}
+
+	hideConfidentialBudgets(tenders, orgs)
+	return tenders, nil
+}
+
+// hideConfidentialBudgets clears confidential budgets of tenders, which do not belong to one of organizations
+func hideConfidentialBudgets(tenders []models.Tender, organizationIds []string) {
+	for i := range tenders {
+		if tenders[i].BudgetConfidential && !slices.Contains(organizationIds, tenders[i].OrganizationId) {
+			tenders[i].Budget = nil
+		}
+	}
+}
+
+// GetTenderStatus returns tender to report its status along with version, which clients use as precondition
+func (s *Service) GetTenderStatus(ctx context.Context, tenderId string) (models.Tender, error) {
+	// get authenticated user
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.GetTenderStatus: %w", err)
+	}
+
+	// get tender
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tender{}, fmt.Errorf("service.Service.GetTenderStatus: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.GetTenderStatus: %w", err)
+	}
+
+	err = s.checkTenderViewable(ctx, tender)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.GetTenderStatus: %w", err)
+	}
+
+	return tender, nil
+}
+
+// checkTenderViewable reports whether tender may be viewed by request principal: it should be visible to them
+// and either be published or belong to their organization
+func (s *Service) checkTenderViewable(ctx context.Context, tender models.Tender) error {
+	// hidden tender is reported missing
+	err := s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkTenderViewable: %w", err)
+	}
+
+	// check whether user is allowed to view tenders of organization or not
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermTenderView)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkTenderViewable: %w", err)
+	}
+
+	if valid || tender.Status == models.TenderPublished {
+		return nil
+	}
+	return &models.PermissionError{Permission: models.PermTenderView}
+}
+
+// SetTenderStatus changes status of tender, cancelled tender requires reason. Tenders are awarded by bid decisions only
+func (s *Service) SetTenderStatus(ctx context.Context, tenderId string, status models.TenderStatus, reason string, expectedVersion int) (models.Tender, error) {
+	// get authenticated user
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
+	}
+
+	// get tender
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
+	}
+
+	// check whether user is allowed to change status of organization's tenders or not
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderStatus)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
+	}
+	err = checkVersion(tender.Version, expectedVersion)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
+	}
+
+	// check tender status, closed tender may still be cancelled, while outcome is never changed
+	if tender.Status.Outcome() || tender.Status == models.TenderClosed && status != models.TenderClosed && status != models.TenderCancelled {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", models.ErrTenderFinalized)
+	}
+	if status == models.TenderCancelled && len(reason) == 0 {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", models.ErrCancellationReason)
+	}
+
+	// change status, manual change cancels scheduled publication
+	before := tender
+	if tender.Status != status {
+		tender.PublishAt = nil
+	}
+	tender.Status = status
+	if status == models.TenderCancelled {
+		tender.CancellationReason = reason
+	}
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateTender(ctx, tender, !status.Final())
+		if err != nil {
+			return err
+		}
+		if !status.Final() {
+			tender.Version++
+		}
+		if status.Final() {
+			err = s.repo.CloseTenderQuestions(ctx, tender.Id)
+			if err != nil {
+				return err
+			}
+		}
+		return s.auditTender(ctx, models.ActionTenderStatus, tender, before, tender)
+	})
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.SetTenderStatus: %w", err)
+	}
+	return tender, nil
+}
+
+// EditTender applies changes to tender, material changes of published tender are recorded as its amendment
+func (s *Service) EditTender(ctx context.Context, tenderId string, changes map[string]any, reason string, expectedVersion int) (models.Tender, error) {
+	// get authenticated user
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+
+	// get tender
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+
+	// check whether user is allowed to edit tenders of organization or not
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+	err = checkVersion(tender.Version, expectedVersion)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+
+	// check tender status, tender is not changed once its bids are opened
+	if tender.Status.Final() || tender.OpenedAt != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", models.ErrTenderFinalized)
+	}
+
+	// remarshal changes into tender
+	before := tender
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+	err = json.Unmarshal(data, &tender)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+	if !validSchedule(tender) {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", models.ErrInvalidSchedule)
+	}
+	amendment, err := s.amendment(ctx, before, tender, reason)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+
+	// update tender
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateTender(ctx, tender, true)
+		if err != nil {
+			return err
+		}
+		tender.Version++
+		err = s.auditTender(ctx, models.ActionTenderEdit, tender, before, tender)
+		if err != nil {
+			return err
+		}
+		return s.recordAmendment(ctx, tender, amendment)
+	})
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.EditTender: %w", err)
+	}
+
+	return tender, nil
+}
+
+// RollbackTender restores tender's version as the new one, material changes of published tender are recorded as its amendment
+func (s *Service) RollbackTender(ctx context.Context, tenderId string, version int, reason string, expectedVersion int) (models.Tender, error) {
+	// get authenticated user
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+
+	// get tender
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+
+	// check whether user is allowed to edit tenders of organization or not
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+	err = checkVersion(tender.Version, expectedVersion)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+
+	// check version number
+	if version < 1 || version > tender.Version {
+		return models.Tender{}, models.ErrNoVersion
+	}
+	if version == tender.Version {
+		return tender, nil
+	}
+
+	// check tender status
+	if tender.Status.Final() || tender.OpenedAt != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", models.ErrTenderFinalized)
+	}
+
+	// find version and do rollback
+	versions, err := s.repo.GetTenderVersions(ctx, tender.Id, version)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+	if len(versions) == 0 {
+		return models.Tender{}, models.ErrNoVersion
+	}
+	amendment, err := s.amendment(ctx, tender, versions[0], reason)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+
+	versions[0].Version = tender.Version
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateTender(ctx, versions[0], true)
+		if err != nil {
+			return err
+		}
+		versions[0].Version++
+		err = s.auditTender(ctx, models.ActionTenderRollback, tender, tender, versions[0])
+		if err != nil {
+			return err
+		}
+		return s.recordAmendment(ctx, versions[0], amendment)
+	})
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.RollbackTender: %w", err)
+	}
+
+	return versions[0], nil
+}
+
+//// Bids
+
+func (s *Service) AddBid(ctx context.Context, bid models.Bid) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+
+	// check if author exists
+	err = s.setBidUserAndOrganization(ctx, &bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+
+	// check whether user is allowed to act on behalf of author
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+
+	// check if tender exists
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+
+	// only invited organizations may bid for invite-only tender
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+
+	// check if tender is open for proposals
+	if tender.Status != models.TenderPublished {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", models.ErrNoTender)
+	}
+	if tender.DeadlinePassed(time.Now()) {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", models.ErrDeadlinePassed)
+	}
+	if !priceMatchesBudget(bid, tender) {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", models.ErrCurrencyMismatch)
+	}
+	err = s.checkBidLots(ctx, bid, tender)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+	bid.TenderVersion = tender.Version
+	err = s.sealBid(ctx, tender, &bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+
+	// add bid
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		bid, err = s.repo.AddBid(ctx, bid)
+		if err != nil {
+			return err
+		}
+		return s.auditBid(ctx, models.ActionBidCreate, bid, nil, bid)
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+
+	bid, err = s.revealBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.AddBid: %w", err)
+	}
+	return bid, nil
+}
+
+func (s *Service) GetUserBids(ctx context.Context, limit, offset int, filter models.BidFilter) ([]models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserBids: %w", err)
+	}
+
+	// get bids
+	filter.UserId = user.Id
+	bids, err := s.repo.GetBids(ctx, limit, offset, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserBids: %w", err)
+	}
+
+	err = s.revealBids(ctx, user, bids)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserBids: %w", err)
+	}
+	return bids, nil
+}
+
+func (s *Service) GetTenderBids(ctx context.Context, tenderId string, limit, offset int, filter models.BidFilter) ([]models.Bid, error) {
+	// get tender
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", models.ErrNoTender)
+	} else if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
+	}
+
+	// check whether tender is visible to user or not
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
+	}
+
+	// check whether user is employee (or API key) of organization or not
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
+	}
+
+	// if user is not employee and tender is not public, forbid access
+	if !valid && tender.Status != models.TenderPublished {
+		return nil, &models.PermissionError{Permission: models.PermBidsView}
+	}
+
+	// get bids
+	filter.TenderId = tender.Id
+	bids, err := s.repo.GetBids(ctx, limit, offset, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
+	}
+
+	// until bids of sealed tender are opened, they are placeholders to anyone but their authors
+	if user, ok := auth.UserFromContext(ctx); ok {
+		err = s.revealBids(ctx, user, bids)
+		if err != nil {
+			return nil, fmt.Errorf("service.Service.GetTenderBids: %w", err)
+		}
+	}
+	return bids, nil
+}
+
+// GetBidStatus returns bid to report its status along with version, which clients use as precondition
+func (s *Service) GetBidStatus(ctx context.Context, bidId string) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.GetBidStatus: %w", err)
+	}
+
+	// find bid
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.GetBidStatus: %w", models.ErrNoBid)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.GetBidStatus: %w", err)
+	}
+
+	err = s.checkBidViewable(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.GetBidStatus: %w", err)
+	}
+
+	return bid, nil
+}
+
+// checkBidViewable reports whether bid may be viewed by user: either by its author, or by employees of organization
+// owning tender, or by anyone tender is visible to while it is published
+func (s *Service) checkBidViewable(ctx context.Context, user models.User, bid models.Bid) error {
+	if bid.AuthorId == user.Id {
+		return nil
+	}
+
+	// find tender
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("service.Service.checkBidViewable: %w", models.ErrNoTender)
+	} else if err != nil {
+		return fmt.Errorf("service.Service.checkBidViewable: %w", err)
+	}
+
+	// check whether tender is visible to user or not
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkBidViewable: %w", err)
+	}
+
+	// check whether user is allowed to view bids of organization or not
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkBidViewable: %w", err)
+	}
+
+	if valid || tender.Status == models.TenderPublished {
+		return nil
+	}
+	return &models.PermissionError{Permission: models.PermBidsView}
+}
+
+func (s *Service) SetBidStatus(ctx context.Context, bidId string, status models.BidStatus, expectedVersion int) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+	}
+
+	// find bid
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrNoBid)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+	}
+
+	// check bid's status
+	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
+		return models.Bid{}, models.ErrBidFinalized
+	}
+	// bids are withdrawn and resubmitted by separate actions, so that history of withdrawals is kept
+	if status == models.BidCanceled && bid.Status != models.BidCanceled {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrWithdrawalReason)
+	}
+	if bid.Status == models.BidCanceled && status != models.BidCanceled {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrBidWithdrawn)
+	}
+
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+	}
+
+	// check whether user is bid's author or employee of organization owning bid
+	valid := false
+	if status == models.BidApproved || status == models.BidRejected {
+		// only for tender's owner
+		err = s.authorize(ctx, tender.OrganizationId, models.PermBidApprove)
+		if err != nil {
+			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+		}
+		valid = true
+		// bids are decided on after tender is closed, but not after it is awarded or cancelled
+		if tender.Status.Outcome() {
+			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrTenderFinalized)
+		}
+		if tender.BidsSealed() {
+			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrBidsSealed)
+		}
+
+		// bids of tenders with lots are only decided on per lot
+		lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+		if err != nil {
+			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+		}
+		if len(lots) > 0 {
+			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrLotRequired)
+		}
+
+		if status == models.BidApproved {
+			if bid.ReconfirmationRequired {
+				return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrReconfirmationRequired)
+			}
+
+			m, err := s.repo.ApprovalCounts(ctx, bid.Id, "")
+			if err != nil {
+				return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+			}
+
+			if m[models.ATReject] > 0 {
+				return models.Bid{}, models.ErrBidFinalized
+			}
+
+			count, err := s.approversCount(ctx, tender.OrganizationId)
+			if err != nil {
+				return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+			}
+
+			if !approvalQuorum(m[models.ATApprove], count) {
+				return models.Bid{}, models.ErrBidCannotBeApprovedYet
+			}
+		}
+	}
+
+	if !valid {
+		valid, err = s.userAllowedToEditBid(ctx, user, bid)
+		if err != nil {
+			return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+		}
+	}
+
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+	// authors can't change their bids once tender no longer accepts them
+	if tender.Status.Final() && status != models.BidApproved && status != models.BidRejected {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", models.ErrTenderFinalized)
+	}
+	err = checkVersion(bid.Version, expectedVersion)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+	}
+
+	// update status, approved bid awards tender the same way as decisions do
+	before := bid
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		if status == models.BidApproved {
+			bid, tender, err = s.applyApprovals(ctx, bid, tender)
+			if err != nil {
+				return err
+			}
+		} else {
+			bid.Status = status
+			err := s.repo.UpdateBid(ctx, bid, true)
+			if err != nil {
+				return err
+			}
+			bid.Version++
+		}
+		return s.auditBid(ctx, models.ActionBidStatus, bid, before, bid)
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+	}
+
+	bid, err = s.revealBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.SetBidStatus: %w", err)
+	}
+	return bid, nil
+}
+
+func (s *Service) EditBid(ctx context.Context, bidId string, changes map[string]any, expectedVersion int) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+
+	// find bid
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", models.ErrNoBid)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+
+	// check bid's status
+	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
+		return models.Bid{}, models.ErrBidFinalized
+	}
+
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+	err = checkVersion(bid.Version, expectedVersion)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+
+	// bids can not be changed after submission deadline
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+	if tender.DeadlinePassed(time.Now()) {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", models.ErrDeadlinePassed)
+	}
+
+	// remarshal changes into bid, sealed one is revealed to be changed and sealed again
+	before := bid
+	bid, err = s.revealBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+	err = json.Unmarshal(data, &bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+	if !priceMatchesBudget(bid, tender) {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", models.ErrCurrencyMismatch)
+	}
+	if _, ok := changes["price"]; ok {
+		err = s.checkAuctionNotStarted(ctx, tender.Id)
+		if err != nil {
+			return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+		}
+	}
+	if _, ok := changes["lotIds"]; ok {
+		err = s.checkBidLots(ctx, bid, tender)
+		if err != nil {
+			return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+		}
+	}
+	// revised bid is submitted against current version of tender
+	bid.TenderVersion = tender.Version
+	bid.ReconfirmationRequired = false
+	err = s.sealBid(ctx, tender, &bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+
+	// update bid
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateBid(ctx, bid, true)
+		if err != nil {
+			return err
+		}
+		bid.Version++
+		return s.auditBid(ctx, models.ActionBidEdit, bid, before, bid)
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+
+	bid, err = s.revealBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.EditBid: %w", err)
+	}
+	return bid, nil
+}
+
+// BidApproval adds vote of current user for bid. Bids of tenders split into lots are voted for per lot,
+// lotId is required for them and should be empty otherwise
+func (s *Service) BidApproval(ctx context.Context, bidId, lotId string, status models.ApproveType) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
+	}
+
+	// find bid
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrNoBid)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
+	}
+
+	// check bid's status
+	if bid.Status == models.BidCreated || bid.Status == models.BidCanceled {
+		return models.Bid{}, models.ErrForbidden
+	}
+	if status == models.ATApprove && bid.ReconfirmationRequired {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrReconfirmationRequired)
+	}
+
+	// ensure user has rights to approve bid (approver of organization owning tender)
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidApprove)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
+	}
+	if tender.Status == models.TenderCancelled {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrTenderFinalized)
+	}
+	if tender.BidsSealed() {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrBidsSealed)
+	}
+
+	lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
+	}
+
+	if len(lots) == 0 {
+		if len(lotId) > 0 {
+			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w: %s", models.ErrNoLot, lotId)
+		}
+		if bid.Status == models.BidApproved && status != models.ATApprove ||
+			bid.Status == models.BidRejected && status != models.ATReject {
+			return models.Bid{}, models.ErrBidFinalized
+		}
+		// tender is awarded to single bid
+		if tender.Status == models.TenderAwarded && bid.Status != models.BidApproved {
+			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrTenderFinalized)
+		}
+	} else {
+		// bid approved for one lot may still be voted for on others
+		if len(lotId) == 0 {
+			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrLotRequired)
+		}
+		i := slices.IndexFunc(lots, func(lot models.Lot) bool { return lot.Id == lotId })
+		if i < 0 || !slices.Contains(bid.LotIds, lotId) {
+			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w: %s", models.ErrNoLot, lotId)
+		}
+		if lots[i].Status != models.LotOpen {
+			return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", models.ErrLotFinalized)
+		}
+		if bid.Status == models.BidRejected {
+			return models.Bid{}, models.ErrBidFinalized
+		}
+	}
+
+	// add approval, change bid / lot / tender status and write it all to audit log at once
+	before := bid
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.AddBidApproval(ctx, bidId, lotId, user.Id, status)
+		if err != nil {
+			return err
+		}
+
+		if len(lots) == 0 {
+			bid, tender, err = s.applyApprovals(ctx, bid, tender)
+		} else {
+			bid, tender, err = s.applyLotApprovals(ctx, bid, tender, lots, lotId)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.auditBid(ctx, models.ActionBidDecision, bid, before, struct {
+			models.Bid
+			Decision models.ApproveType `json:"decision"`
+			LotId    string             `json:"lotId,omitempty"`
+		}{bid, status, lotId})
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidApproval: %w", err)
+	}
+
+	return bid, nil
+}
+
+func (s *Service) BidFeedback(ctx context.Context, bidId, feedback string) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
+	}
+
+	// find bid
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", models.ErrNoBid)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
+	}
+
+	// find tender
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", models.ErrNoTender)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
+	}
+
+	// if user is not allowed to review bids of organization owning tender - forbid action
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidFeedback)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
+	}
+
+	review := models.BidReview{
+		BidId:       bid.Id,
+		UserId:      user.Id,
+		Description: feedback,
+	}
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.AddReview(ctx, review)
+		if err != nil {
+			return err
+		}
+		return s.auditBid(ctx, models.ActionBidFeedback, bid, nil, review)
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidFeedback: %w", err)
+	}
+
+	return bid, nil
+}
+
+func (s *Service) BidRollback(ctx context.Context, bidId string, version, expectedVersion int) (models.Bid, error) {
+	// get authenticated user
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+
+	// find bid
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", models.ErrNoBid)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+
+	// check bid's status
+	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
+		return models.Bid{}, models.ErrBidFinalized
+	}
+
+	// check user's rights
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+	err = checkVersion(bid.Version, expectedVersion)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+
+	// find version
+	versions, err := s.repo.GetBidVersions(ctx, bid.Id, version)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+	if len(versions) == 0 {
+		return models.Bid{}, models.ErrNoVersion
+	}
+
+	err = s.checkAuctionNotStarted(ctx, bid.TenderId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+
+	// restored version has to be reconfirmed if tender is amended since it was submitted
+	amended, err := s.repo.LatestAmendmentVersion(ctx, bid.TenderId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+	versions[0].ReconfirmationRequired = versions[0].TenderVersion < amended
+
+	// update bid
+	versions[0].Version = bid.Version
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateBid(ctx, versions[0], true)
+		if err != nil {
+			return err
+		}
+		versions[0].Version++
+		return s.auditBid(ctx, models.ActionBidRollback, bid, bid, versions[0])
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+
+	bid, err = s.revealBid(ctx, user, versions[0])
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.BidRollback: %w", err)
+	}
+	return bid, nil
+}
+
+func (s *Service) PastUserBidsReviews(ctx context.Context, tenderId, authorName string, limit, offset int) ([]models.BidReview, error) {
+	// check users
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
+	}
+	author, err := s.userByUsername(ctx, authorName)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
+	}
+
+	// get tender
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", models.ErrNoTender)
+	} else if err != nil {
+		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
+	}
+
+	// check if requester is actually allowed to view bids of organization owning tender
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
+	}
+
+	// get reviews
+	reviews, err := s.repo.GetReviews(ctx, limit, offset, "", "", author.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.PastUserBidsReviews: %w", err)
+	}
+	return reviews, nil
+}
+
+//// Service
+
+// validSchedule reports whether scheduled publication of tender is consistent with its status and deadline
+func validSchedule(tender models.Tender) bool {
+	if tender.PublishAt == nil {
+		return true
+	}
+	if tender.Status != models.TenderCreated {
+		return false
+	}
+	return tender.SubmissionDeadline == nil || tender.PublishAt.Before(*tender.SubmissionDeadline)
+}
+
+// priceMatchesBudget reports whether bid price is in currency of tender budget, so they can be compared.
+// Any currency is accepted, if tender has no budget
+func priceMatchesBudget(bid models.Bid, tender models.Tender) bool {
+	return tender.Budget == nil || bid.Price == nil || bid.Price.Currency == tender.Budget.Currency
+}
+
+// applyApprovals counts decisions on bid and changes bid / tender status, when they become final
+func (s *Service) applyApprovals(ctx context.Context, bid models.Bid, tender models.Tender) (models.Bid, models.Tender, error) {
+	counts, err := s.repo.ApprovalCounts(ctx, bid.Id, "")
+	if err != nil {
+		return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+	}
+
+	if counts[models.ATReject] > 0 {
+		// if at least 1 reject present, mark bid as rejected
+		bid.Status = models.BidRejected
+		err = s.repo.UpdateBid(ctx, bid, false)
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+		}
+
+	} else if counts[models.ATApprove] > 0 {
+		// if at least 1 approve present, check approve count against employee count and change statuses if necessary
+		n, err := s.approversCount(ctx, tender.OrganizationId)
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+		}
+
+		if approvalQuorum(counts[models.ATApprove], n) {
+			bid.Status = models.BidApproved
+			err = s.repo.UpdateBid(ctx, bid, false)
+			if err != nil {
+				return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+			}
+
+			// tender is awarded to approved bid, repeated approvals of it change nothing
+			if tender.Status == models.TenderAwarded {
+				return bid, tender, nil
+			}
+			before := tender
+			tender.Status = models.TenderAwarded
+			tender.AwardedBidIds = []string{bid.Id}
+			err = s.repo.UpdateTender(ctx, tender, false)
+			if err != nil {
+				return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+			}
+			err = s.repo.CloseTenderQuestions(ctx, tender.Id)
+			if err != nil {
+				return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+			}
+			err = s.auditTender(ctx, models.ActionTenderStatus, tender, before, tender)
+			if err != nil {
+				return bid, tender, fmt.Errorf("service.Service.applyApprovals: %w", err)
+			}
+		}
+	}
+
+	return bid, tender, nil
+}
+
+func (s *Service) userAllowedToEditBid(ctx context.Context, user models.User, bid models.Bid) (bool, error) {
+	var err error
+
+	valid := false
+	if bid.AuthorId == user.Id {
+		valid = true
+
+	} else if bid.AuthorType == models.AuthorOrganization {
+		valid, err = s.allowed(ctx, bid.AuthorId, models.PermBidSubmit)
+		if err != nil {
+			return false, fmt.Errorf("service.Service.userAllowedToEditBid: %w", err)
+		}
+
+	} else if len(bid.OrganizationId) > 0 {
+		// colleagues of author may manage bid, if they are allowed to submit bids of author's organization
+		valid, err = s.allowed(ctx, bid.OrganizationId, models.PermBidSubmit)
+		if err != nil {
+			return false, fmt.Errorf("service.Service.userAllowedToEditBid: %w", err)
+		}
+
+	} else {
+		valid, err = s.repo.UsersAreColleagues(ctx, user.Id, bid.AuthorId)
+		if err != nil {
+			return false, fmt.Errorf("service.Service.userAllowedToEditBid: %w", err)
+		}
+	}
+
+	return valid, err
+}
+
+// checkVersion reports conflict unless entity is of version client expects it to be,
+// zero expected version means client has no expectations
+func checkVersion(version, expected int) error {
+	if expected > 0 && version != expected {
+		return models.ErrVersionConflict
+	}
+	return nil
+}
+
+// approvalQuorum reports whether bid has enough approvals to be approved: 3 or all approvers of organization
+func approvalQuorum(approvals, approvers int) bool {
+	return approvals >= 3 || approvals >= approvers
+}
+
+// approversCount returns amount of organization's members allowed to vote for bids
+func (s *Service) approversCount(ctx context.Context, organizationId string) (int, error) {
+	count, err := s.repo.EmployeeCountByRoles(ctx, organizationId, models.RolesWith(models.PermBidApprove))
+	if err != nil {
+		return 0, fmt.Errorf("service.Service.approversCount: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Service) setBidUserAndOrganization(ctx context.Context, bid *models.Bid) error {
+	if bid.AuthorType == models.AuthorUser {
+		bid.UserId = bid.AuthorId
+		_, exist, err := s.repo.UserByUUID(ctx, bid.AuthorId)
+		if err != nil {
+			return fmt.Errorf("service.Service.setBidUserAndOrganization: %w", err)
+		}
+		if !exist {
+			return models.ErrInvalidUser
+		}
+	} else if bid.AuthorType == models.AuthorOrganization {
+		_, err := s.repo.OrganizationByUUID(ctx, bid.AuthorId)
+		if err == sql.ErrNoRows {
+			return models.ErrInvalidUser
+		} else if err != nil {
+			return fmt.Errorf("service.Service.setBidUserAndOrganization: %w", err)
+		}
+		bid.OrganizationId = bid.AuthorId
+	}
+
+	return nil
+}
+
+func (s *Service) userByUsername(ctx context.Context, username string) (models.User, error) {
+	user, ok, err := s.repo.UserByUsername(ctx, username)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.userByUsername: %w", err)
+	}
+	if !ok {
+		return models.User{}, fmt.Errorf("service.Service.userByUsername: %w: %s", models.ErrInvalidUser, username)
+	}
+	return user, err
+}
+
+func (s *Service) tenderByUUID(ctx context.Context, tenderId string) (models.Tender, error) {
+	tender, err := s.repo.GetTenderByUUID(ctx, tenderId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Tender{}, fmt.Errorf("service.Service.tenderByUUID: %w: %s", models.ErrNoTender, tenderId)
+	} else if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.tenderByUUID: %w", err)
+	}
+	return tender, nil
+}
+
+func (s *Service) bidByUUID(ctx context.Context, bidId string) (models.Bid, error) {
+	bid, err := s.repo.GetBidByUUID(ctx, bidId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Bid{}, fmt.Errorf("service.Service.bidByUUID: %w: %s", models.ErrNoBid, bidId)
+	} else if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.bidByUUID: %w", err)
+	}
+	return bid, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+	"time"
+)
+
+// GetTenderAmendments returns amendments of tender, they are public once tender is published
+func (s *Service) GetTenderAmendments(ctx context.Context, tenderId string, limit, offset int) ([]models.Amendment, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAmendments: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAmendments: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAmendments: %w", err)
+	}
+
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAmendments: %w", err)
+	}
+	if !valid && tender.Status == models.TenderCreated {
+		return nil, &models.PermissionError{Permission: models.PermTenderView}
+	}
+
+	amendments, err := s.repo.GetTenderAmendments(ctx, tender.Id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAmendments: %w", err)
+	}
+	return amendments, nil
+}
+
+// ReconfirmBid confirms bid submitted against earlier version of tender stands for its current version as is,
+// revising bid by EditBid confirms it as well
+func (s *Service) ReconfirmBid(ctx context.Context, bidId string) (models.Bid, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", err)
+	}
+	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", models.ErrBidFinalized)
+	}
+
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", err)
+	}
+	if tender.Status.Final() {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", models.ErrTenderFinalized)
+	}
+	if tender.DeadlinePassed(time.Now()) {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", models.ErrDeadlinePassed)
+	}
+
+	before := bid
+	bid.TenderVersion = tender.Version
+	bid.ReconfirmationRequired = false
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateBid(ctx, bid, false)
+		if err != nil {
+			return err
+		}
+		return s.auditBid(ctx, models.ActionBidReconfirm, bid, before, bid)
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", err)
+	}
+
+	bid, err = s.revealBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ReconfirmBid: %w", err)
+	}
+	return bid, nil
+}
+
+// amendment returns amendment of published tender materially changed from before into after, or nil if change
+// is not an amendment. Reason is required once there are bids to be reconfirmed
+func (s *Service) amendment(ctx context.Context, before, after models.Tender, reason string) (*models.Amendment, error) {
+	if before.Status != models.TenderPublished || !before.MateriallyDiffers(after) {
+		return nil, nil
+	}
+
+	active, err := s.repo.TenderHasActiveBids(ctx, before.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.amendment: %w", err)
+	}
+	if active && len(reason) == 0 {
+		return nil, fmt.Errorf("service.Service.amendment: %w", models.ErrAmendmentReason)
+	}
+
+	amendment := &models.Amendment{TenderId: before.Id, Version: before.Version + 1, Reason: reason}
+	if user, ok := auth.UserFromContext(ctx); ok {
+		amendment.AuthorId = user.Id
+	}
+	return amendment, nil
+}
+
+// recordAmendment stores amendment of tender and requires bids submitted against earlier versions to be
+// reconfirmed, it has to be called from s.repo.RunInTx along with tender update
+func (s *Service) recordAmendment(ctx context.Context, tender models.Tender, amendment *models.Amendment) error {
+	if amendment == nil {
+		return nil
+	}
+
+	stored, err := s.repo.AddAmendment(ctx, *amendment)
+	if err != nil {
+		return fmt.Errorf("service.Service.recordAmendment: %w", err)
+	}
+	_, err = s.repo.RequireBidsReconfirmation(ctx, tender.Id, stored.Version)
+	if err != nil {
+		return fmt.Errorf("service.Service.recordAmendment: %w", err)
+	}
+	return s.auditTender(ctx, models.ActionTenderAmend, tender, nil, stored)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+	"time"
+)
+
+const apiKeyPrefix = "tk_"
+
+// CreateAPIKey issues new API key for organization, returned secret is not stored and can not be recovered later
+func (s *Service) CreateAPIKey(ctx context.Context, key models.APIKey) (models.APIKey, string, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("service.Service.CreateAPIKey: %w", err)
+	}
+
+	// only administrators of organization can manage its keys
+	err = s.authorize(ctx, key.OrganizationId, models.PermOrganizationManage)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("service.Service.CreateAPIKey: %w", err)
+	}
+
+	secret, err := auth.RandomSecret(32)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("service.Service.CreateAPIKey: %w", err)
+	}
+	secret = apiKeyPrefix + secret
+
+	key.Hash = hashSecret(secret)
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		key, err = s.repo.AddAPIKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, models.AuditEntry{
+			Action:          models.ActionAPIKeyCreate,
+			EntityType:      models.EntityAPIKey,
+			EntityId:        key.Id,
+			OrganizationIds: []string{key.OrganizationId},
+		}, nil, key)
+	})
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("service.Service.CreateAPIKey: %w", err)
+	}
+
+	return key, secret, nil
+}
+
+func (s *Service) GetAPIKeys(ctx context.Context, organizationId string) ([]models.APIKey, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAPIKeys: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAPIKeys: %w", err)
+	}
+
+	keys, err := s.repo.GetAPIKeys(ctx, organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAPIKeys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, organizationId, keyId string) error {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.RevokeAPIKey: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return fmt.Errorf("service.Service.RevokeAPIKey: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.RevokeAPIKey(ctx, organizationId, keyId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoAPIKey
+		}
+		return s.audit(ctx, models.AuditEntry{
+			Action:          models.ActionAPIKeyRevoke,
+			EntityType:      models.EntityAPIKey,
+			EntityId:        keyId,
+			OrganizationIds: []string{organizationId},
+		}, nil, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.RevokeAPIKey: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns active API key by its secret and records its usage
+func (s *Service) AuthenticateAPIKey(ctx context.Context, secret string) (models.APIKey, error) {
+	key, err := s.repo.APIKeyByHash(ctx, hashSecret(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.APIKey{}, fmt.Errorf("service.Service.AuthenticateAPIKey: %w", models.ErrInvalidCredentials)
+	} else if err != nil {
+		return models.APIKey{}, fmt.Errorf("service.Service.AuthenticateAPIKey: %w", err)
+	}
+
+	if !key.Active(time.Now()) {
+		return models.APIKey{}, fmt.Errorf("service.Service.AuthenticateAPIKey: %w: key %s is revoked or expired", models.ErrInvalidCredentials, key.Id)
+	}
+
+	err = s.repo.TouchAPIKey(ctx, key.Id)
+	if err != nil {
+		log.Println("service.Service.AuthenticateAPIKey:", err)
+	}
+
+	return key, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+	"time"
+)
+
+//// Tender attachments
+
+// GetTenderAttachments lists attachments of tender to anyone allowed to view tender itself
+func (s *Service) GetTenderAttachments(ctx context.Context, tenderId string) ([]models.Attachment, error) {
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAttachments: %w", err)
+	}
+
+	err = s.checkTenderViewable(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAttachments: %w", err)
+	}
+
+	attachments, err := s.repo.GetTenderAttachments(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderAttachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// AddTenderAttachment stores document attached to tender, tender can not be changed once it is finalized
+func (s *Service) AddTenderAttachment(ctx context.Context, tenderId string, attachment models.Attachment, content io.Reader) (models.Attachment, error) {
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddTenderAttachment: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddTenderAttachment: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddTenderAttachment: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddTenderAttachment: %w", models.ErrTenderFinalized)
+	}
+
+	attachment.TenderId = tender.Id
+	attachment, err = s.saveAttachment(ctx, path.Join("tenders", tender.Id), attachment, content, func(ctx context.Context, added models.Attachment) error {
+		return s.auditTender(ctx, models.ActionTenderAttach, tender, nil, added)
+	})
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddTenderAttachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// OpenTenderAttachment returns attachment of tender along with its content, which has to be closed by caller
+func (s *Service) OpenTenderAttachment(ctx context.Context, tenderId, attachmentId string) (models.Attachment, io.ReadCloser, error) {
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenTenderAttachment: %w", err)
+	}
+
+	err = s.checkTenderViewable(ctx, tender)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenTenderAttachment: %w", err)
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentId)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenTenderAttachment: %w", err)
+	}
+	if attachment.TenderId != tender.Id {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenTenderAttachment: %w: %s", models.ErrNoAttachment, attachmentId)
+	}
+
+	content, err := s.openAttachment(ctx, attachment)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenTenderAttachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+func (s *Service) DeleteTenderAttachment(ctx context.Context, tenderId, attachmentId string) error {
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w", models.ErrTenderFinalized)
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentId)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w", err)
+	}
+	if attachment.TenderId != tender.Id {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w: %s", models.ErrNoAttachment, attachmentId)
+	}
+
+	err = s.removeAttachment(ctx, attachment, func(ctx context.Context) error {
+		return s.auditTender(ctx, models.ActionTenderDetach, tender, attachment, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTenderAttachment: %w", err)
+	}
+	return nil
+}
+
+//// Bid attachments
+
+// GetBidAttachments lists attachments of bid to anyone allowed to view bid itself, attachments of sealed bids
+// are only listed to their authors until bids are opened
+func (s *Service) GetBidAttachments(ctx context.Context, bidId string) ([]models.Attachment, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidAttachments: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidAttachments: %w", err)
+	}
+
+	err = s.checkBidAttachmentsViewable(ctx, user, bid)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidAttachments: %w", err)
+	}
+
+	attachments, err := s.repo.GetBidAttachments(ctx, bid.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidAttachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// AddBidAttachment stores document attached to bid, it is allowed to those who may edit bid until submission deadline
+func (s *Service) AddBidAttachment(ctx context.Context, bidId string, attachment models.Attachment, content io.Reader) (models.Attachment, error) {
+	bid, err := s.editableBid(ctx, bidId)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddBidAttachment: %w", err)
+	}
+
+	attachment.BidId = bid.Id
+	attachment, err = s.saveAttachment(ctx, path.Join("bids", bid.Id), attachment, content, func(ctx context.Context, added models.Attachment) error {
+		return s.auditBid(ctx, models.ActionBidAttach, bid, nil, added)
+	})
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.AddBidAttachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// OpenBidAttachment returns attachment of bid along with its content, which has to be closed by caller
+func (s *Service) OpenBidAttachment(ctx context.Context, bidId, attachmentId string) (models.Attachment, io.ReadCloser, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenBidAttachment: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenBidAttachment: %w", err)
+	}
+
+	err = s.checkBidAttachmentsViewable(ctx, user, bid)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenBidAttachment: %w", err)
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentId)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenBidAttachment: %w", err)
+	}
+	if attachment.BidId != bid.Id {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenBidAttachment: %w: %s", models.ErrNoAttachment, attachmentId)
+	}
+
+	content, err := s.openAttachment(ctx, attachment)
+	if err != nil {
+		return models.Attachment{}, nil, fmt.Errorf("service.Service.OpenBidAttachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+func (s *Service) DeleteBidAttachment(ctx context.Context, bidId, attachmentId string) error {
+	bid, err := s.editableBid(ctx, bidId)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteBidAttachment: %w", err)
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentId)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteBidAttachment: %w", err)
+	}
+	if attachment.BidId != bid.Id {
+		return fmt.Errorf("service.Service.DeleteBidAttachment: %w: %s", models.ErrNoAttachment, attachmentId)
+	}
+
+	err = s.removeAttachment(ctx, attachment, func(ctx context.Context) error {
+		return s.auditBid(ctx, models.ActionBidDetach, bid, attachment, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteBidAttachment: %w", err)
+	}
+	return nil
+}
+
+// checkBidAttachmentsViewable reports whether attachments of bid may be viewed by user, same as bid itself.
+// Documents are not sealed along with content of bid, so until bids are opened they are hidden from anyone but authors
+func (s *Service) checkBidAttachmentsViewable(ctx context.Context, user models.User, bid models.Bid) error {
+	err := s.checkBidViewable(ctx, user, bid)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkBidAttachmentsViewable: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkBidAttachmentsViewable: %w", err)
+	}
+	if !tender.BidsSealed() {
+		return nil
+	}
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkBidAttachmentsViewable: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("service.Service.checkBidAttachmentsViewable: %w", models.ErrBidsSealed)
+	}
+	return nil
+}
+
+// editableBid finds bid which attachments may be changed by user, same as bid itself
+func (s *Service) editableBid(ctx context.Context, bidId string) (models.Bid, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.editableBid: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.editableBid: %w", err)
+	}
+
+	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
+		return models.Bid{}, fmt.Errorf("service.Service.editableBid: %w", models.ErrBidFinalized)
+	}
+
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.editableBid: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.editableBid: %w", err)
+	}
+	if tender.DeadlinePassed(time.Now()) {
+		return models.Bid{}, fmt.Errorf("service.Service.editableBid: %w", models.ErrDeadlinePassed)
+	}
+
+	return bid, nil
+}
+
+//// Storage
+
+// saveAttachment writes content to storage under provided prefix and records attachment along with audit entry,
+// content is removed from storage if attachment could not be recorded
+func (s *Service) saveAttachment(ctx context.Context, prefix string, attachment models.Attachment, content io.Reader, audit func(context.Context, models.Attachment) error) (models.Attachment, error) {
+	name, err := auth.RandomSecret(16)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.saveAttachment: %w", err)
+	}
+	attachment.StorageKey = path.Join(prefix, name)
+
+	// attachments uploaded by API keys have no author
+	if user, ok := auth.UserFromContext(ctx); ok {
+		attachment.AuthorId = user.Id
+	}
+
+	buffered := bufio.NewReader(content)
+	head, err := buffered.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return models.Attachment{}, fmt.Errorf("service.Service.saveAttachment: %w", err)
+	}
+	attachment.ContentType, err = s.attachmentType(attachment.ContentType, head)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.saveAttachment: %w", err)
+	}
+
+	hash := sha256.New()
+	limited := &limitedReader{r: io.TeeReader(buffered, hash), limit: s.storageCfg.AttachmentMaxSize}
+	err = s.storage.Save(ctx, attachment.StorageKey, limited)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("service.Service.saveAttachment: %w", err)
+	}
+	attachment.Size = limited.n
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	key := attachment.StorageKey
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		attachment, err = s.repo.AddAttachment(ctx, attachment)
+		if err != nil {
+			return err
+		}
+		return audit(ctx, attachment)
+	})
+	if err != nil {
+		if err := s.storage.Delete(context.WithoutCancel(ctx), key); err != nil {
+			log.Println("service.Service.saveAttachment: could not remove orphaned content:", err)
+		}
+		return models.Attachment{}, fmt.Errorf("service.Service.saveAttachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// removeAttachment deletes record of attachment along with audit entry, its content is removed from storage afterwards
+func (s *Service) removeAttachment(ctx context.Context, attachment models.Attachment, audit func(context.Context) error) error {
+	err := s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.DeleteAttachment(ctx, attachment.Id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoAttachment
+		}
+		return audit(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.removeAttachment: %w", err)
+	}
+
+	// record is already gone, leftover content is only reported
+	err = s.storage.Delete(ctx, attachment.StorageKey)
+	if err != nil {
+		log.Println("service.Service.removeAttachment: could not remove content:", err)
+	}
+	return nil
+}
+
+func (s *Service) openAttachment(ctx context.Context, attachment models.Attachment) (io.ReadCloser, error) {
+	content, err := s.storage.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("service.Service.openAttachment: content is missing: %w: %s", models.ErrNoAttachment, attachment.Id)
+	} else if err != nil {
+		return nil, fmt.Errorf("service.Service.openAttachment: %w", err)
+	}
+	return content, nil
+}
+
+// attachmentType resolves MIME type of attachment: declared one is taken unless it is missing or generic,
+// in which case type is detected from first bytes of content. Type should be one of accepted ones
+func (s *Service) attachmentType(declared string, head []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	}
+
+	if !slices.Contains(s.storageCfg.AttachmentTypes, mediaType) {
+		return "", fmt.Errorf("%w: %s", models.ErrAttachmentType, mediaType)
+	}
+	return mediaType, nil
+}
+
+// limitedReader counts bytes read and fails once more than limit of them are read
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.n > r.limit {
+		return n, models.ErrAttachmentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"tenders/internal/models"
+	"time"
+)
+
+// SetTenderAuction schedules reverse auction among bids of tender or replaces terms of scheduled one.
+// Auction starts after submission deadline of tender, once tender is closed and its bids are no longer
+// visible to competitors. Prices are in currency of its minimum decrement
+func (s *Service) SetTenderAuction(ctx context.Context, auction models.Auction) (models.Auction, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, auction.TenderId)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderStatus)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", err)
+	}
+
+	err = s.checkAuctionAvailable(ctx, tender)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", err)
+	}
+	if tender.Budget != nil && tender.Budget.Currency != auction.MinDecrement.Currency {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", models.ErrCurrencyMismatch)
+	}
+	if tender.SubmissionDeadline == nil || auction.StartAt.Before(*tender.SubmissionDeadline) {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", models.ErrAuctionSchedule)
+	}
+
+	auction.TenderId = tender.Id
+	auction.EndAt = auction.StartAt.Add(time.Duration(auction.DurationMinutes) * time.Minute)
+	auction.CreatedBy = user.Id
+
+	var before any
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		current, err := s.repo.LockAuction(ctx, tender.Id)
+		if err == nil {
+			before = current
+		} else if !errors.Is(err, models.ErrNoAuction) {
+			return err
+		}
+		auction, err = s.repo.SetAuction(ctx, auction)
+		if err != nil {
+			return err
+		}
+		return s.auditTender(ctx, models.ActionTenderAuction, tender, before, auction)
+	})
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.SetTenderAuction: %w", err)
+	}
+
+	return auction, nil
+}
+
+// GetTenderAuction returns auction of tender, it is visible to everyone tender is visible to and to its participants
+func (s *Service) GetTenderAuction(ctx context.Context, tenderId string) (models.Auction, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.GetTenderAuction: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.GetTenderAuction: %w", err)
+	}
+
+	err = s.checkTenderViewable(ctx, tender)
+	if err != nil {
+		_, own, accessErr := s.auctionAccess(ctx, user, tender)
+		if accessErr != nil {
+			return models.Auction{}, fmt.Errorf("service.Service.GetTenderAuction: %w", accessErr)
+		}
+		if len(own) == 0 {
+			return models.Auction{}, fmt.Errorf("service.Service.GetTenderAuction: %w", err)
+		}
+	}
+
+	auction, err := s.repo.GetAuction(ctx, tender.Id)
+	if err != nil {
+		return models.Auction{}, fmt.Errorf("service.Service.GetTenderAuction: %w", err)
+	}
+	return auction, nil
+}
+
+// PlaceAuctionPrice submits new price of bid during auction of its tender. Price should be lower than
+// current one at least by minimum decrement, price submitted shortly before the end extends auction
+func (s *Service) PlaceAuctionPrice(ctx context.Context, bidId string, price models.Money) (models.AuctionPrice, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.AuctionPrice{}, fmt.Errorf("service.Service.PlaceAuctionPrice: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.AuctionPrice{}, fmt.Errorf("service.Service.PlaceAuctionPrice: %w", err)
+	}
+
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.AuctionPrice{}, fmt.Errorf("service.Service.PlaceAuctionPrice: %w", err)
+	}
+	if !valid {
+		return models.AuctionPrice{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return models.AuctionPrice{}, fmt.Errorf("service.Service.PlaceAuctionPrice: %w", err)
+	}
+	// prices of bids of published tender are visible to anyone, so they are only placed once tender is closed
+	if tender.Status != models.TenderClosed {
+		return models.AuctionPrice{}, fmt.Errorf("service.Service.PlaceAuctionPrice: %w", models.ErrAuctionNotRunning)
+	}
+
+	var entry models.AuctionPrice
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		// prices of auction are placed one at a time, so that decrement and extension are checked against latest state
+		auction, err := s.repo.LockAuction(ctx, tender.Id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if !auction.Running(now) {
+			return models.ErrAuctionNotRunning
+		}
+
+		bid, err := s.bidByUUID(ctx, bid.Id)
+		if err != nil {
+			return err
+		}
+		if !auctionQualified(bid, auction) {
+			return models.ErrNotQualified
+		}
+		if price.Currency != auction.MinDecrement.Currency {
+			return models.ErrCurrencyMismatch
+		}
+		if price.Amount.Add(auction.MinDecrement.Amount).Cmp(bid.Price.Amount) > 0 {
+			return models.ErrPriceDecrement
+		}
+
+		// anti-sniping: competitors are given time to answer price submitted at the last moment
+		extension := time.Duration(auction.ExtensionMinutes) * time.Minute
+		if auction.EndAt.Sub(now) < extension {
+			auction.EndAt = now.Add(extension)
+			auction.Extensions++
+		}
+		auction.Status = models.AuctionRunning
+		err = s.repo.UpdateAuctionProgress(ctx, auction)
+		if err != nil {
+			return err
+		}
+
+		entry, err = s.repo.AddAuctionPrice(ctx, models.AuctionPrice{
+			TenderId:      tender.Id,
+			BidId:         bid.Id,
+			Price:         price,
+			PreviousPrice: bid.Price,
+			UserId:        user.Id,
+		})
+		if err != nil {
+			return err
+		}
+
+		before := bid
+		bid.Price = &price
+		err = s.repo.UpdateBid(ctx, bid, true)
+		if err != nil {
+			return err
+		}
+		bid.Version++
+		return s.auditBid(ctx, models.ActionBidAuctionPrice, bid, before, bid)
+	})
+	if err != nil {
+		return models.AuctionPrice{}, fmt.Errorf("service.Service.PlaceAuctionPrice: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetAuctionRanking ranks qualified bids of auction by current price. Employees of organization owning tender
+// see all ranks with prices, participants only see ranks of their own bids along with amount of participants
+func (s *Service) GetAuctionRanking(ctx context.Context, tenderId string) (models.AuctionRanking, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.AuctionRanking{}, fmt.Errorf("service.Service.GetAuctionRanking: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.AuctionRanking{}, fmt.Errorf("service.Service.GetAuctionRanking: %w", err)
+	}
+
+	owner, own, err := s.auctionAccess(ctx, user, tender)
+	if err != nil {
+		return models.AuctionRanking{}, fmt.Errorf("service.Service.GetAuctionRanking: %w", err)
+	}
+	if !owner && len(own) == 0 {
+		return models.AuctionRanking{}, &models.PermissionError{Permission: models.PermBidsView}
+	}
+
+	auction, err := s.repo.GetAuction(ctx, tender.Id)
+	if err != nil {
+		return models.AuctionRanking{}, fmt.Errorf("service.Service.GetAuctionRanking: %w", err)
+	}
+
+	standings, err := s.repo.GetAuctionStandings(ctx, tender.Id, auction.MinDecrement.Currency)
+	if err != nil {
+		return models.AuctionRanking{}, fmt.Errorf("service.Service.GetAuctionRanking: %w", err)
+	}
+
+	ranking := models.AuctionRanking{
+		Status:       auction.Status,
+		EndAt:        auction.EndAt,
+		Participants: len(standings),
+		Ranks:        []models.AuctionRank{},
+	}
+	for _, rank := range standings {
+		if owner || own[rank.BidId] {
+			ranking.Ranks = append(ranking.Ranks, rank)
+		}
+	}
+	return ranking, nil
+}
+
+// GetAuctionLedger lists prices submitted during auction, ledger is only visible to employees of organization owning tender
+func (s *Service) GetAuctionLedger(ctx context.Context, tenderId string, limit, offset int) ([]models.AuctionPrice, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuctionLedger: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuctionLedger: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuctionLedger: %w", err)
+	}
+
+	_, err = s.repo.GetAuction(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuctionLedger: %w", err)
+	}
+
+	prices, err := s.repo.GetAuctionPrices(ctx, tender.Id, "", limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuctionLedger: %w", err)
+	}
+	return prices, nil
+}
+
+// StartDueAuctions starts scheduled auctions, start time of which has come, and returns amount of started
+// auctions. It is meant to be called periodically by scheduler of application
+func (s *Service) StartDueAuctions(ctx context.Context) (int, error) {
+	ctx = asSystem(ctx)
+	now := time.Now()
+
+	auctions, err := s.repo.GetDueAuctions(ctx, now, schedulerBatch)
+	if err != nil {
+		return 0, fmt.Errorf("service.Service.StartDueAuctions: %w", err)
+	}
+
+	started := 0
+	for _, auction := range auctions {
+		if ctx.Err() != nil {
+			return started, fmt.Errorf("service.Service.StartDueAuctions: %w", ctx.Err())
+		}
+
+		before := auction
+		auction.Status = models.AuctionRunning
+
+		ok := false
+		err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+			ok, err = s.repo.StartDueAuction(ctx, auction.TenderId, now)
+			if err != nil || !ok {
+				return err
+			}
+			return s.auditAuction(ctx, models.ActionAuctionStart, auction, before)
+		})
+		if err != nil {
+			// auction is retried on the next run
+			log.Printf("service.Service.StartDueAuctions: tender %s: %s\n", auction.TenderId, err)
+			continue
+		}
+		if ok {
+			started++
+		}
+	}
+
+	return started, nil
+}
+
+// CloseExpiredAuctions closes auctions, end time of which has passed, and returns amount of closed auctions.
+// It is meant to be called periodically by scheduler of application
+func (s *Service) CloseExpiredAuctions(ctx context.Context) (int, error) {
+	ctx = asSystem(ctx)
+	now := time.Now()
+
+	auctions, err := s.repo.GetExpiredAuctions(ctx, now, schedulerBatch)
+	if err != nil {
+		return 0, fmt.Errorf("service.Service.CloseExpiredAuctions: %w", err)
+	}
+
+	closed := 0
+	for _, auction := range auctions {
+		if ctx.Err() != nil {
+			return closed, fmt.Errorf("service.Service.CloseExpiredAuctions: %w", ctx.Err())
+		}
+
+		before := auction
+		auction.Status = models.AuctionClosed
+		auction.ClosedAt = &now
+
+		ok := false
+		err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+			// auction extended by price submitted since it was read is left running
+			ok, err = s.repo.CloseExpiredAuction(ctx, auction.TenderId, now)
+			if err != nil || !ok {
+				return err
+			}
+			return s.auditAuction(ctx, models.ActionAuctionClose, auction, before)
+		})
+		if err != nil {
+			// auction is retried on the next run
+			log.Printf("service.Service.CloseExpiredAuctions: tender %s: %s\n", auction.TenderId, err)
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+
+	return closed, nil
+}
+
+//// Service
+
+// checkAuctionAvailable reports whether auction may be held for tender: prices of bids are only compared
+// for the whole tender and have to be readable by everyone taking part
+func (s *Service) checkAuctionAvailable(ctx context.Context, tender models.Tender) error {
+	if tender.Status != models.TenderPublished && tender.Status != models.TenderClosed {
+		return models.ErrAuctionUnavailable
+	}
+	if tender.BidsSealed() {
+		return models.ErrAuctionUnavailable
+	}
+	lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkAuctionAvailable: %w", err)
+	}
+	if len(lots) > 0 {
+		return models.ErrAuctionUnavailable
+	}
+	return nil
+}
+
+// checkAuctionNotStarted reports models.ErrAuctionStarted once auction of tender starts, from then on
+// prices of its bids are only changed by submitting them to auction
+func (s *Service) checkAuctionNotStarted(ctx context.Context, tenderId string) error {
+	auction, err := s.repo.GetAuction(ctx, tenderId)
+	if errors.Is(err, models.ErrNoAuction) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("service.Service.checkAuctionNotStarted: %w", err)
+	}
+	if auction.Status != models.AuctionScheduled || !time.Now().Before(auction.StartAt) {
+		return models.ErrAuctionStarted
+	}
+	return nil
+}
+
+// auctionAccess reports whether user sees auction of tender as its owner, along with bids of tender user may act on behalf of
+func (s *Service) auctionAccess(ctx context.Context, user models.User, tender models.Tender) (bool, map[string]bool, error) {
+	owner, err := s.allowed(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return false, nil, fmt.Errorf("service.Service.auctionAccess: %w", err)
+	}
+
+	bids, err := s.repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tender.Id})
+	if err != nil {
+		return false, nil, fmt.Errorf("service.Service.auctionAccess: %w", err)
+	}
+	own := make(map[string]bool)
+	for _, bid := range bids {
+		valid, err := s.userAllowedToEditBid(ctx, user, bid)
+		if err != nil {
+			return false, nil, fmt.Errorf("service.Service.auctionAccess: %w", err)
+		}
+		if valid {
+			own[bid.Id] = true
+		}
+	}
+	return owner, own, nil
+}
+
+// auctionQualified reports whether bid takes part in auction
+func auctionQualified(bid models.Bid, auction models.Auction) bool {
+	return bid.Status == models.BidPublished && !bid.ReconfirmationRequired && bid.Price != nil &&
+		bid.Price.Currency == auction.MinDecrement.Currency
+}
+
+// auditAuction records phase change of auction, visible to organization owning its tender
+func (s *Service) auditAuction(ctx context.Context, action models.AuditAction, auction, before models.Auction) error {
+	tender, err := s.repo.GetTenderByUUID(ctx, auction.TenderId, nil)
+	if err != nil {
+		return fmt.Errorf("service.Service.auditAuction: %w", err)
+	}
+	return s.auditTender(ctx, action, tender, before, auction)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+)
+
+// GetAuditLog returns audit log entries of organization, only organization's admins may view it
+func (s *Service) GetAuditLog(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]models.AuditEntry, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuditLog: %w", err)
+	}
+
+	_, err = s.organizationByUUID(ctx, filter.OrganizationId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuditLog: %w", err)
+	}
+
+	err = s.authorize(ctx, filter.OrganizationId, models.PermAuditView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuditLog: %w", err)
+	}
+
+	entries, err := s.repo.GetAuditEntries(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetAuditLog: %w", err)
+	}
+	return entries, nil
+}
+
+type systemKey struct{}
+
+// asSystem marks ctx of background job, so that its actions are audited as performed by service itself
+func asSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+// audit writes entry of mutation performed by request principal along with entity state before
+// and after it (nil when entity did not exist), it has to be called from s.repo.RunInTx together
+// with mutation itself, so that neither of them is saved without the other
+func (s *Service) audit(ctx context.Context, entry models.AuditEntry, before, after any) error {
+	var err error
+
+	entry.ActorType = models.ActorAnonymous
+	if system, _ := ctx.Value(systemKey{}).(bool); system {
+		entry.ActorType = models.ActorSystem
+	} else if user, ok := auth.UserFromContext(ctx); ok {
+		entry.ActorType = models.ActorUser
+		entry.ActorId = user.Id
+		entry.ActorName = user.Username
+	} else if key, ok := auth.APIKeyFromContext(ctx); ok {
+		entry.ActorType = models.ActorAPIKey
+		entry.ActorId = key.Id
+		entry.ActorName = key.Name
+	}
+	entry.RequestId = auth.RequestIDFromContext(ctx)
+
+	if before != nil {
+		entry.Before, err = json.Marshal(before)
+		if err != nil {
+			return fmt.Errorf("service.Service.audit: %w", err)
+		}
+	}
+	if after != nil {
+		entry.After, err = json.Marshal(after)
+		if err != nil {
+			return fmt.Errorf("service.Service.audit: %w", err)
+		}
+	}
+	if entry.OrganizationIds == nil {
+		entry.OrganizationIds = []string{}
+	}
+
+	_, err = s.repo.AddAuditEntry(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("service.Service.audit: %w", err)
+	}
+	return nil
+}
+
+// auditTender writes entry of tender mutation, visible to organization owning tender
+func (s *Service) auditTender(ctx context.Context, action models.AuditAction, tender models.Tender, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityTender,
+		EntityId:        tender.Id,
+		OrganizationIds: []string{tender.OrganizationId},
+	}, before, after)
+}
+
+// auditCategory records change of service-wide category, which belongs to no organization
+func (s *Service) auditCategory(ctx context.Context, action models.AuditAction, category models.Category, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:     action,
+		EntityType: models.EntityCategory,
+		EntityId:   string(category.Code),
+	}, before, after)
+}
+
+// auditQuestion writes entry of question mutation, visible to provided organizations only
+func (s *Service) auditQuestion(ctx context.Context, action models.AuditAction, question models.Question, orgs []string, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityQuestion,
+		EntityId:        question.Id,
+		OrganizationIds: orgs,
+	}, before, after)
+}
+
+// auditTemplate writes entry of template mutation, visible to organization owning template
+func (s *Service) auditTemplate(ctx context.Context, action models.AuditAction, template models.Template, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityTemplate,
+		EntityId:        template.Id,
+		OrganizationIds: []string{template.OrganizationId},
+	}, before, after)
+}
+
+// auditBid writes entry of bid mutation, visible to organization owning tender and to bid's organization
+func (s *Service) auditBid(ctx context.Context, action models.AuditAction, bid models.Bid, before, after any) error {
+	tender, err := s.repo.GetTenderByUUID(ctx, bid.TenderId, nil)
+	if err != nil {
+		return fmt.Errorf("service.Service.auditBid: %w", err)
+	}
+
+	orgs := []string{tender.OrganizationId}
+	if len(bid.OrganizationId) > 0 && bid.OrganizationId != tender.OrganizationId {
+		orgs = append(orgs, bid.OrganizationId)
+	}
+
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityBid,
+		EntityId:        bid.Id,
+		OrganizationIds: orgs,
+	}, before, after)
+}
+
+// auditOrganization writes entry of organization mutation, visible to organization itself
+func (s *Service) auditOrganization(ctx context.Context, action models.AuditAction, organizationId string, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityOrganization,
+		EntityId:        organizationId,
+		OrganizationIds: []string{organizationId},
+	}, before, after)
+}
+
+// auditMember writes entry of membership mutation, entity id is id of employee
+func (s *Service) auditMember(ctx context.Context, action models.AuditAction, organizationId, employeeId string, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityMember,
+		EntityId:        employeeId,
+		OrganizationIds: []string{organizationId},
+	}, before, after)
+}
+
+// auditInvitation writes entry of invitation mutation, visible to organization employee is invited to
+func (s *Service) auditInvitation(ctx context.Context, action models.AuditAction, inv models.Invitation, before, after any) error {
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityInvitation,
+		EntityId:        inv.Id,
+		OrganizationIds: []string{inv.OrganizationId},
+	}, before, after)
+}
+
+// auditEmployee writes entry of employee mutation, visible to organizations employee is member of
+func (s *Service) auditEmployee(ctx context.Context, action models.AuditAction, user models.User, before, after any) error {
+	orgs, err := s.repo.UserOrganizationIds(ctx, user.Id)
+	if err != nil {
+		return fmt.Errorf("service.Service.auditEmployee: %w", err)
+	}
+
+	return s.audit(ctx, models.AuditEntry{
+		Action:          action,
+		EntityType:      models.EntityEmployee,
+		EntityId:        user.Id,
+		OrganizationIds: orgs,
+	}, before, after)
+}
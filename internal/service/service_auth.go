@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+	"time"
+)
+
+const passwordTokenPrefix = "pwd_"
+
+// Login checks user's credentials and issues signed access token
+func (s *Service) Login(ctx context.Context, username, password string) (string, time.Time, error) {
+	user, err := s.userByUsername(ctx, username)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.Login: %w", err)
+	}
+
+	hash, err := s.repo.UserPasswordHash(ctx, user.Id)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.Login: %w", err)
+	}
+	if !auth.CheckPassword(password, hash) {
+		return "", time.Time{}, fmt.Errorf("service.Service.Login: %w: %s", models.ErrInvalidCredentials, username)
+	}
+
+	now := time.Now()
+	expires := now.Add(s.cfg.JWTExpiry)
+	token, err := auth.NewToken(auth.Claims{
+		Subject:   user.Id,
+		Username:  user.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+	}, s.tokenSecret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.Login: %w", err)
+	}
+
+	return token, expires, nil
+}
+
+// Authenticate validates access token and returns user it was issued to
+func (s *Service) Authenticate(ctx context.Context, token string) (models.User, error) {
+	claims, err := auth.ParseToken(token, s.tokenSecret, time.Now())
+	if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenExpired) {
+		return models.User{}, fmt.Errorf("service.Service.Authenticate: %w: %w", models.ErrInvalidCredentials, err)
+	} else if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.Authenticate: %w", err)
+	}
+
+	user, ok, err := s.repo.UserByUUID(ctx, claims.Subject)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.Authenticate: %w", err)
+	}
+	if !ok {
+		return models.User{}, fmt.Errorf("service.Service.Authenticate: %w: %s", models.ErrInvalidUser, claims.Username)
+	}
+
+	return user, nil
+}
+
+// AuthenticateLegacy returns user by username without any credentials check,
+// only meant to be used when legacy username authentication is enabled
+func (s *Service) AuthenticateLegacy(ctx context.Context, username string) (models.User, error) {
+	user, err := s.userByUsername(ctx, username)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.AuthenticateLegacy: %w", err)
+	}
+	return user, nil
+}
+
+// SetPassword changes password of authenticated user, current password has to be confirmed if it is set.
+// Since legacy authentication does not prove identity of user, it does not allow to change password
+func (s *Service) SetPassword(ctx context.Context, currentPassword, password string) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.SetPassword: %w", err)
+	}
+	if auth.IsLegacy(ctx) {
+		return fmt.Errorf("service.Service.SetPassword: %w", models.ErrLegacyAuth)
+	}
+
+	current, err := s.repo.UserPasswordHash(ctx, user.Id)
+	if err != nil {
+		return fmt.Errorf("service.Service.SetPassword: %w", err)
+	}
+	if len(current) > 0 && !auth.CheckPassword(currentPassword, current) {
+		return fmt.Errorf("service.Service.SetPassword: %w: %s", models.ErrInvalidCredentials, user.Username)
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("service.Service.SetPassword: %w", err)
+	}
+
+	// neither of password hashes is written to audit log
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.SetUserPasswordHash(ctx, user.Id, hash)
+		if err != nil {
+			return err
+		}
+		return s.auditEmployee(ctx, models.ActionEmployeePassword, user, nil, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.SetPassword: %w", err)
+	}
+	return nil
+}
+
+// ResetMemberPassword issues one-time token, with which member of organization sets new password without logging in.
+// Token replaces previously issued one and is not stored, so it can not be recovered later. Since legacy authentication
+// does not prove identity of administrator, it only allows to issue tokens to members without password
+func (s *Service) ResetMemberPassword(ctx context.Context, organizationId, employeeId string) (string, time.Time, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", err)
+	}
+
+	user, err := s.employeeByUUID(ctx, employeeId)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", err)
+	}
+	_, member, err := s.repo.UserRole(ctx, user.Id, organizationId)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", err)
+	}
+	if !member {
+		return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", models.ErrNoMember)
+	}
+
+	if auth.IsLegacy(ctx) {
+		hash, err := s.repo.UserPasswordHash(ctx, user.Id)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", err)
+		}
+		if len(hash) > 0 {
+			return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", models.ErrLegacyAuth)
+		}
+	}
+
+	var token string
+	var expires time.Time
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		token, expires, err = s.issuePasswordToken(ctx, user.Id)
+		if err != nil {
+			return err
+		}
+		return s.auditEmployee(ctx, models.ActionEmployeeReset, user, nil, nil)
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.ResetMemberPassword: %w", err)
+	}
+	return token, expires, nil
+}
+
+// SetPasswordWithToken sets password of employee one-time token is issued to, it does not require authentication.
+// Token is spent by it
+func (s *Service) SetPasswordWithToken(ctx context.Context, token, password string) error {
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("service.Service.SetPasswordWithToken: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		userId, err := s.repo.SetUserPasswordHashByToken(ctx, hashSecret(token), hash)
+		if err != nil {
+			return err
+		}
+		if len(userId) == 0 {
+			return fmt.Errorf("%w: unknown or expired password token", models.ErrInvalidCredentials)
+		}
+
+		// change is audited as made by employee, who proves their identity with token
+		user, err := s.employeeByUUID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		return s.auditEmployee(auth.WithUser(ctx, user), models.ActionEmployeePassword, user, nil, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.SetPasswordWithToken: %w", err)
+	}
+	return nil
+}
+
+// issuePasswordToken generates one-time password token of user and stores its hash, it is valid for configured time
+func (s *Service) issuePasswordToken(ctx context.Context, userId string) (string, time.Time, error) {
+	token, err := auth.RandomSecret(32)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.issuePasswordToken: %w", err)
+	}
+	token = passwordTokenPrefix + token
+
+	expires, err := s.repo.SetUserPasswordToken(ctx, userId, hashSecret(token), s.cfg.PasswordTokenExpiry)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service.Service.issuePasswordToken: %w", err)
+	}
+	return token, expires, nil
+}
+
+func (s *Service) currentUser(ctx context.Context) (models.User, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return models.User{}, fmt.Errorf("service.Service.currentUser: %w", models.ErrInvalidUser)
+	}
+	return user, nil
+}
+
+// allowed reports whether request principal is granted permission in organization: either
+// as its employee with corresponding role, or as its API key with corresponding scope
+func (s *Service) allowed(ctx context.Context, organizationId string, perm models.Permission) (bool, error) {
+	if user, ok := auth.UserFromContext(ctx); ok {
+		role, member, err := s.repo.UserRole(ctx, user.Id, organizationId)
+		if err != nil {
+			return false, fmt.Errorf("service.Service.allowed: %w", err)
+		}
+		return member && role.Can(perm), nil
+	}
+
+	if key, ok := auth.APIKeyFromContext(ctx); ok {
+		return key.OrganizationId == organizationId && key.Allows(perm), nil
+	}
+
+	return false, fmt.Errorf("service.Service.allowed: %w", models.ErrInvalidUser)
+}
+
+// authorize is the same as allowed, but reports missing permission as *models.PermissionError
+func (s *Service) authorize(ctx context.Context, organizationId string, perm models.Permission) error {
+	ok, err := s.allowed(ctx, organizationId, perm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &models.PermissionError{Permission: perm}
+	}
+	return nil
+}
+
+// principalOrganizations returns ids of organizations request principal acts within,
+// anonymous requests do not belong to any organization
+func (s *Service) principalOrganizations(ctx context.Context) ([]string, error) {
+	if user, ok := auth.UserFromContext(ctx); ok {
+		orgs, err := s.repo.UserOrganizationIds(ctx, user.Id)
+		if err != nil {
+			return nil, fmt.Errorf("service.Service.principalOrganizations: %w", err)
+		}
+		return orgs, nil
+	}
+
+	if key, ok := auth.APIKeyFromContext(ctx); ok {
+		return []string{key.OrganizationId}, nil
+	}
+
+	return nil, nil
+}
+
+// principalName returns name of request principal for logging and error messages
+func principalName(ctx context.Context) string {
+	if user, ok := auth.UserFromContext(ctx); ok {
+		return user.Username
+	}
+	if key, ok := auth.APIKeyFromContext(ctx); ok {
+		return "api key " + key.Name
+	}
+	return "anonymous"
+}
+
+// authorizeAdministrator ensures request principal is service administrator, which is employee listed in
+// configuration. API keys act within their organizations only, so they are never administrators
+func (s *Service) authorizeAdministrator(ctx context.Context, perm models.Permission) error {
+	if user, ok := auth.UserFromContext(ctx); ok {
+		if slices.Contains(s.cfg.Administrators, user.Username) {
+			return nil
+		}
+		return &models.PermissionError{Permission: perm}
+	}
+
+	if _, ok := auth.APIKeyFromContext(ctx); ok {
+		return &models.PermissionError{Permission: perm}
+	}
+
+	return fmt.Errorf("service.Service.authorizeAdministrator: %w", models.ErrInvalidUser)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"tenders/internal/models"
+)
+
+// GetCategories returns service categories, they are public as tenders referring to them
+func (s *Service) GetCategories(ctx context.Context, includeInactive bool) ([]models.Category, error) {
+	categories, err := s.repo.GetCategories(ctx, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetCategories: %w", err)
+	}
+	return categories, nil
+}
+
+func (s *Service) AddCategory(ctx context.Context, category models.Category) (models.Category, error) {
+	err := s.authorizeAdministrator(ctx, models.PermCategoriesManage)
+	if err != nil {
+		return category, fmt.Errorf("service.Service.AddCategory: %s: %w", principalName(ctx), err)
+	}
+
+	if len(category.ParentCode) > 0 {
+		_, err = s.repo.GetCategory(ctx, category.ParentCode)
+		if err != nil {
+			return category, fmt.Errorf("service.Service.AddCategory: parent: %w", err)
+		}
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		category, err = s.repo.AddCategory(ctx, category)
+		if err != nil {
+			return err
+		}
+		return s.auditCategory(ctx, models.ActionCategoryCreate, category, nil, category)
+	})
+	if err != nil {
+		return category, fmt.Errorf("service.Service.AddCategory: %w", err)
+	}
+
+	return category, nil
+}
+
+// EditCategory changes names, parent or activity of category, its code is kept since tenders refer to it
+func (s *Service) EditCategory(ctx context.Context, code models.ServiceType, changes map[string]any) (models.Category, error) {
+	err := s.authorizeAdministrator(ctx, models.PermCategoriesManage)
+	if err != nil {
+		return models.Category{}, fmt.Errorf("service.Service.EditCategory: %s: %w", principalName(ctx), err)
+	}
+
+	category, err := s.repo.GetCategory(ctx, code)
+	if err != nil {
+		return models.Category{}, fmt.Errorf("service.Service.EditCategory: %w", err)
+	}
+
+	// remarshal changes into category, names are replaced rather than merged
+	before := category
+	if _, ok := changes["names"]; ok {
+		category.Names = nil
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return models.Category{}, fmt.Errorf("service.Service.EditCategory: %w", err)
+	}
+	err = json.Unmarshal(data, &category)
+	if err != nil {
+		return models.Category{}, fmt.Errorf("service.Service.EditCategory: %w", err)
+	}
+
+	if category.ParentCode != before.ParentCode && len(category.ParentCode) > 0 {
+		err = s.checkCategoryParent(ctx, category)
+		if err != nil {
+			return models.Category{}, fmt.Errorf("service.Service.EditCategory: %w", err)
+		}
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		category, err = s.repo.UpdateCategory(ctx, category)
+		if err != nil {
+			return err
+		}
+		return s.auditCategory(ctx, models.ActionCategoryEdit, category, before, category)
+	})
+	if err != nil {
+		return models.Category{}, fmt.Errorf("service.Service.EditCategory: %w", err)
+	}
+
+	return category, nil
+}
+
+// checkCategoryParent ensures new parent of category exists and is not category itself or one of its descendants
+func (s *Service) checkCategoryParent(ctx context.Context, category models.Category) error {
+	categories, err := s.repo.GetCategories(ctx, true)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkCategoryParent: %w", err)
+	}
+
+	parents := make(map[models.ServiceType]models.ServiceType, len(categories))
+	for _, c := range categories {
+		parents[c.Code] = c.ParentCode
+	}
+	if _, ok := parents[category.ParentCode]; !ok {
+		return fmt.Errorf("service.Service.checkCategoryParent: parent: %w: %s", models.ErrNoCategory, category.ParentCode)
+	}
+
+	// walk ancestors of new parent, taxonomy is acyclic so far, so walk ends at the top level
+	for code := category.ParentCode; len(code) > 0; code = parents[code] {
+		if code == category.Code {
+			return fmt.Errorf("service.Service.checkCategoryParent: %w", models.ErrCategoryCycle)
+		}
+	}
+	return nil
+}
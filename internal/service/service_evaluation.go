@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+// GetTenderCriteria returns evaluation criteria of tender, they are public once tender is published
+func (s *Service) GetTenderCriteria(ctx context.Context, tenderId string) ([]models.Criterion, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderCriteria: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderCriteria: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderCriteria: %w", err)
+	}
+
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderCriteria: %w", err)
+	}
+	if !valid && tender.Status != models.TenderPublished {
+		return nil, &models.PermissionError{Permission: models.PermTenderView}
+	}
+
+	criteria, err := s.repo.GetTenderCriteria(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderCriteria: %w", err)
+	}
+	return criteria, nil
+}
+
+// SetTenderCriteria replaces evaluation criteria of tender, which is only possible until first score is given
+func (s *Service) SetTenderCriteria(ctx context.Context, tenderId string, criteria []models.Criterion) ([]models.Criterion, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", models.ErrTenderFinalized)
+	}
+
+	// scores would lose their meaning if criteria were changed
+	scored, err := s.repo.TenderScored(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", err)
+	}
+	if scored {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", models.ErrCriteriaLocked)
+	}
+
+	before, err := s.repo.GetTenderCriteria(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		criteria, err = s.repo.SetTenderCriteria(ctx, tender.Id, criteria)
+		if err != nil {
+			return err
+		}
+		return s.auditTender(ctx, models.ActionTenderCriteria, tender, before, criteria)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderCriteria: %w", err)
+	}
+
+	return criteria, nil
+}
+
+// ScoreBid saves scores current user gives bid by criteria of its tender, evaluator may change
+// their scores until tender is closed
+func (s *Service) ScoreBid(ctx context.Context, bidId string, scores []models.BidScore) ([]models.BidScore, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+
+	// only employees of organization owning tender evaluate its bids
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidScore)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", models.ErrTenderFinalized)
+	}
+	if bid.Status == models.BidCanceled {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", models.ErrNoBid)
+	}
+	if tender.BidsSealed() {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", models.ErrBidsSealed)
+	}
+
+	criteria, err := s.repo.GetTenderCriteria(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+	for _, score := range scores {
+		known := false
+		for _, c := range criteria {
+			known = known || c.Id == score.CriterionId
+		}
+		if !known {
+			return nil, fmt.Errorf("service.Service.ScoreBid: criterion %s: %w", score.CriterionId, models.ErrNoCriterion)
+		}
+	}
+
+	before, err := s.repo.GetBidScores(ctx, bid.Id, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		scores, err = s.repo.SetBidScores(ctx, bid.Id, user.Id, scores)
+		if err != nil {
+			return err
+		}
+		// scores are internal to organization owning tender, so bid's organization does not see the entry
+		return s.audit(ctx, models.AuditEntry{
+			Action:          models.ActionBidScore,
+			EntityType:      models.EntityBid,
+			EntityId:        bid.Id,
+			OrganizationIds: []string{tender.OrganizationId},
+		}, before, scores)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.ScoreBid: %w", err)
+	}
+
+	return scores, nil
+}
+
+// GetBidScores returns scores of bid given by all evaluators
+func (s *Service) GetBidScores(ctx context.Context, bidId string) ([]models.BidScore, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidScores: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidScores: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidScores: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidScores: %w", err)
+	}
+
+	scores, err := s.repo.GetBidScores(ctx, bid.Id, "")
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidScores: %w", err)
+	}
+	return scores, nil
+}
+
+// GetTenderRanking returns bids of tender ordered by weighted score, ranking is only visible to
+// employees of organization owning tender
+func (s *Service) GetTenderRanking(ctx context.Context, tenderId string) ([]models.BidRanking, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderRanking: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderRanking: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderRanking: %w", err)
+	}
+
+	results, err := s.repo.GetScoreResults(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderRanking: %w", err)
+	}
+	return models.RankBids(results), nil
+}
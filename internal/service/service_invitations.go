@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+)
+
+const invitationTokenPrefix = "inv_"
+
+// InviteMember invites employee to organization by username, employee does not have to be registered yet.
+// Returned token is not stored and can not be recovered later
+func (s *Service) InviteMember(ctx context.Context, organizationId, username string, role models.Role) (models.Invitation, string, error) {
+	inviter, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+	}
+
+	// check whether invitee is already a member
+	user, ok, err := s.repo.UserByUsername(ctx, username)
+	if err != nil {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+	}
+	if ok {
+		_, member, err := s.repo.UserRole(ctx, user.Id, organizationId)
+		if err != nil {
+			return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+		}
+		if member {
+			return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", models.ErrAlreadyMember)
+		}
+	}
+
+	exists, err := s.repo.PendingInvitationExists(ctx, organizationId, username)
+	if err != nil {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+	}
+	if exists {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", models.ErrInvitationExists)
+	}
+
+	token, err := auth.RandomSecret(32)
+	if err != nil {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+	}
+	token = invitationTokenPrefix + token
+
+	var inv models.Invitation
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		inv, err = s.repo.AddInvitation(ctx, models.Invitation{
+			OrganizationId: organizationId,
+			Username:       username,
+			Role:           role,
+			InvitedBy:      inviter.Id,
+			TokenHash:      hashSecret(token),
+		}, s.cfg.InvitationExpiry)
+		if err != nil {
+			return err
+		}
+		return s.auditInvitation(ctx, models.ActionInvitationCreate, inv, nil, inv)
+	})
+	if err != nil {
+		return models.Invitation{}, "", fmt.Errorf("service.Service.InviteMember: %w", err)
+	}
+
+	return inv, token, nil
+}
+
+// GetInvitations lists invitations of organization with provided status, empty status means any
+func (s *Service) GetInvitations(ctx context.Context, organizationId string, status models.InvitationStatus, limit, offset int) ([]models.Invitation, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitations: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitations: %w", err)
+	}
+
+	invs, err := s.repo.GetInvitations(ctx, organizationId, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitations: %w", err)
+	}
+	return invs, nil
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, organizationId, invitationId string) error {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.RevokeInvitation: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return fmt.Errorf("service.Service.RevokeInvitation: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.RevokeInvitation(ctx, organizationId, invitationId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoInvitation
+		}
+		return s.auditInvitation(ctx, models.ActionInvitationRevoke, models.Invitation{Id: invitationId, OrganizationId: organizationId}, nil, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.RevokeInvitation: %w", err)
+	}
+	return nil
+}
+
+// GetUserInvitations lists pending invitations of authenticated user
+func (s *Service) GetUserInvitations(ctx context.Context) ([]models.Invitation, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserInvitations: %w", err)
+	}
+
+	invs, err := s.repo.GetUserInvitations(ctx, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetUserInvitations: %w", err)
+	}
+	return invs, nil
+}
+
+// RespondInvitation accepts or declines invitation addressed to authenticated user,
+// accepted invitation makes user a member of organization with invited role
+func (s *Service) RespondInvitation(ctx context.Context, token string, accept bool) (models.Invitation, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("service.Service.RespondInvitation: %w", err)
+	}
+
+	inv, err := s.repo.InvitationByTokenHash(ctx, hashSecret(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Invitation{}, fmt.Errorf("service.Service.RespondInvitation: %w", models.ErrNoInvitation)
+	} else if err != nil {
+		return models.Invitation{}, fmt.Errorf("service.Service.RespondInvitation: %w", err)
+	}
+
+	// invitations of other users are reported as missing
+	if inv.Username != user.Username {
+		return models.Invitation{}, fmt.Errorf("service.Service.RespondInvitation: %w", models.ErrNoInvitation)
+	}
+	if inv.Status != models.InvitationPending {
+		return models.Invitation{}, fmt.Errorf("service.Service.RespondInvitation: %w: %s", models.ErrInvitationClosed, inv.Status)
+	}
+
+	status, action := models.InvitationDeclined, models.ActionInvitationDecline
+	if accept {
+		status, action = models.InvitationAccepted, models.ActionInvitationAccept
+	}
+
+	before := inv
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.RespondInvitation(ctx, inv, status, user.Id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrInvitationClosed
+		}
+
+		inv, err = s.repo.InvitationByTokenHash(ctx, inv.TokenHash)
+		if err != nil {
+			return err
+		}
+		return s.auditInvitation(ctx, action, inv, before, inv)
+	})
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("service.Service.RespondInvitation: %w", err)
+	}
+	return inv, nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"tenders/internal/models"
+)
+
+// GetTenderLots returns lots of tender, they are public once tender is published
+func (s *Service) GetTenderLots(ctx context.Context, tenderId string) ([]models.Lot, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderLots: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderLots: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderLots: %w", err)
+	}
+
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderLots: %w", err)
+	}
+	if !valid && tender.Status != models.TenderPublished {
+		return nil, &models.PermissionError{Permission: models.PermTenderView}
+	}
+
+	lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderLots: %w", err)
+	}
+
+	// lot budgets are as confidential as budget of tender itself
+	if !valid && tender.BudgetConfidential {
+		for i := range lots {
+			lots[i].Budget = nil
+		}
+	}
+	return lots, nil
+}
+
+// SetTenderLots replaces lots of tender, which is only possible until first bid is submitted
+func (s *Service) SetTenderLots(ctx context.Context, tenderId string, lots []models.Lot) ([]models.Lot, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", models.ErrTenderFinalized)
+	}
+
+	// bids refer to lots, so they can not be replaced afterwards
+	submitted, err := s.repo.TenderHasBids(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", err)
+	}
+	if submitted {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", models.ErrLotsLocked)
+	}
+
+	for _, lot := range lots {
+		if lot.Budget != nil && tender.Budget != nil && lot.Budget.Currency != tender.Budget.Currency {
+			return nil, fmt.Errorf("service.Service.SetTenderLots: lot '%s': %w", lot.Name, models.ErrCurrencyMismatch)
+		}
+	}
+
+	before, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		lots, err = s.repo.SetTenderLots(ctx, tender.Id, lots)
+		if err != nil {
+			return err
+		}
+		return s.auditTender(ctx, models.ActionTenderLots, tender, before, lots)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetTenderLots: %w", err)
+	}
+
+	return lots, nil
+}
+
+// SetLotStatus changes status of open lot, lots are only cancelled this way, while awarded by bid decisions.
+// Tender is awarded or cancelled once its last open lot is cancelled
+func (s *Service) SetLotStatus(ctx context.Context, tenderId, lotId string, status models.LotStatus) (models.Lot, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderStatus)
+	if err != nil {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", models.ErrTenderFinalized)
+	}
+
+	lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", err)
+	}
+	i := slices.IndexFunc(lots, func(lot models.Lot) bool { return lot.Id == lotId })
+	if i < 0 {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w: %s", models.ErrNoLot, lotId)
+	}
+	if lots[i].Status != models.LotOpen {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", models.ErrLotFinalized)
+	}
+
+	before := lots[i]
+	lots[i].Status = status
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateLotStatus(ctx, lots[i])
+		if err != nil {
+			return err
+		}
+		err = s.auditTender(ctx, models.ActionTenderLotStatus, tender, before, lots[i])
+		if err != nil {
+			return err
+		}
+		_, err = s.closeSettledTender(ctx, tender, lots)
+		return err
+	})
+	if err != nil {
+		return models.Lot{}, fmt.Errorf("service.Service.SetLotStatus: %w", err)
+	}
+
+	return lots[i], nil
+}
+
+// checkBidLots ensures bid is submitted for open lots of tender if tender is split into lots, and for none otherwise
+func (s *Service) checkBidLots(ctx context.Context, bid models.Bid, tender models.Tender) error {
+	lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkBidLots: %w", err)
+	}
+
+	if len(lots) > 0 && len(bid.LotIds) == 0 {
+		return fmt.Errorf("service.Service.checkBidLots: %w", models.ErrLotRequired)
+	}
+	for _, lotId := range bid.LotIds {
+		i := slices.IndexFunc(lots, func(lot models.Lot) bool { return lot.Id == lotId })
+		if i < 0 {
+			return fmt.Errorf("service.Service.checkBidLots: %w: %s", models.ErrNoLot, lotId)
+		}
+		if lots[i].Status != models.LotOpen {
+			return fmt.Errorf("service.Service.checkBidLots: lot %s: %w", lotId, models.ErrLotFinalized)
+		}
+	}
+	return nil
+}
+
+// applyLotApprovals counts decisions on bid for lot, awards lot to bid once it is approved and closes tender
+// once all of its lots are settled. Bid is approved once it wins any lot and rejected once it loses all of them
+func (s *Service) applyLotApprovals(ctx context.Context, bid models.Bid, tender models.Tender, lots []models.Lot, lotId string) (models.Bid, models.Tender, error) {
+	counts, err := s.repo.ApprovalCounts(ctx, bid.Id, lotId)
+	if err != nil {
+		return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+	}
+
+	if counts[models.ATReject] > 0 {
+		if bid.Status == models.BidApproved {
+			return bid, tender, nil
+		}
+
+		lost, err := s.bidLostLots(ctx, bid, lots)
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+		}
+		if lost {
+			bid.Status = models.BidRejected
+			err = s.repo.UpdateBid(ctx, bid, false)
+			if err != nil {
+				return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+			}
+		}
+
+	} else if counts[models.ATApprove] > 0 {
+		n, err := s.approversCount(ctx, tender.OrganizationId)
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+		}
+		if !approvalQuorum(counts[models.ATApprove], n) {
+			return bid, tender, nil
+		}
+
+		i := slices.IndexFunc(lots, func(lot models.Lot) bool { return lot.Id == lotId })
+		before := lots[i]
+		lots[i].Status = models.LotAwarded
+		lots[i].AwardedBidId = bid.Id
+		err = s.repo.UpdateLotStatus(ctx, lots[i])
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+		}
+		err = s.auditTender(ctx, models.ActionTenderLotStatus, tender, before, lots[i])
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+		}
+
+		if bid.Status != models.BidApproved {
+			bid.Status = models.BidApproved
+			err = s.repo.UpdateBid(ctx, bid, false)
+			if err != nil {
+				return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+			}
+		}
+
+		tender, err = s.closeSettledTender(ctx, tender, lots)
+		if err != nil {
+			return bid, tender, fmt.Errorf("service.Service.applyLotApprovals: %w", err)
+		}
+	}
+
+	return bid, tender, nil
+}
+
+// bidLostLots reports whether bid can not win any of lots it is submitted for anymore: each of them is
+// either cancelled, awarded to another bid or bid is rejected for it
+func (s *Service) bidLostLots(ctx context.Context, bid models.Bid, lots []models.Lot) (bool, error) {
+	for _, lot := range lots {
+		if !slices.Contains(bid.LotIds, lot.Id) {
+			continue
+		}
+		if lot.Status == models.LotCancelled || lot.Status == models.LotAwarded && lot.AwardedBidId != bid.Id {
+			continue
+		}
+		if lot.Status == models.LotAwarded {
+			return false, nil
+		}
+
+		counts, err := s.repo.ApprovalCounts(ctx, bid.Id, lot.Id)
+		if err != nil {
+			return false, fmt.Errorf("service.Service.bidLostLots: %w", err)
+		}
+		if counts[models.ATReject] == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// closeSettledTender sets outcome of tender once none of its lots is open anymore: tender is awarded to bids
+// winning its lots, or cancelled if all of them are cancelled. Tenders without lots are left as is
+func (s *Service) closeSettledTender(ctx context.Context, tender models.Tender, lots []models.Lot) (models.Tender, error) {
+	if !models.LotsSettled(lots) || tender.Status.Outcome() {
+		return tender, nil
+	}
+
+	before := tender
+	tender.Status = models.TenderCancelled
+	tender.CancellationReason = "All lots are cancelled"
+	for _, lot := range lots {
+		if lot.Status == models.LotAwarded && !slices.Contains(tender.AwardedBidIds, lot.AwardedBidId) {
+			tender.Status = models.TenderAwarded
+			tender.CancellationReason = ""
+			tender.AwardedBidIds = append(tender.AwardedBidIds, lot.AwardedBidId)
+		}
+	}
+	err := s.repo.UpdateTender(ctx, tender, false)
+	if err != nil {
+		return tender, fmt.Errorf("service.Service.closeSettledTender: %w", err)
+	}
+	err = s.repo.CloseTenderQuestions(ctx, tender.Id)
+	if err != nil {
+		return tender, fmt.Errorf("service.Service.closeSettledTender: %w", err)
+	}
+	err = s.auditTender(ctx, models.ActionTenderStatus, tender, before, tender)
+	if err != nil {
+		return tender, fmt.Errorf("service.Service.closeSettledTender: %w", err)
+	}
+	return tender, nil
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+	"time"
+)
+
+//// Employees
+
+// AddEmployee registers new employee, it does not require authentication. Registered
+// employee has no access to organizations until one of its administrators adds them.
+// Employee registered without password is given one-time token to set it, since they can not log in otherwise
+func (s *Service) AddEmployee(ctx context.Context, user models.User, password string) (models.User, string, time.Time, error) {
+	var hash, token string
+	var expires time.Time
+	var err error
+
+	if len(password) > 0 {
+		hash, err = auth.HashPassword(password)
+		if err != nil {
+			return models.User{}, "", time.Time{}, fmt.Errorf("service.Service.AddEmployee: %w", err)
+		}
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		user, err = s.repo.AddUser(ctx, user, hash)
+		if err != nil {
+			return err
+		}
+		if len(hash) == 0 {
+			token, expires, err = s.issuePasswordToken(ctx, user.Id)
+			if err != nil {
+				return err
+			}
+		}
+		return s.auditEmployee(ctx, models.ActionEmployeeCreate, user, nil, user)
+	})
+	if err != nil {
+		return models.User{}, "", time.Time{}, fmt.Errorf("service.Service.AddEmployee: %w", err)
+	}
+	return user, token, expires, nil
+}
+
+func (s *Service) GetEmployees(ctx context.Context, limit, offset int) ([]models.User, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetEmployees: %w", err)
+	}
+
+	users, err := s.repo.GetUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetEmployees: %w", err)
+	}
+	return users, nil
+}
+
+func (s *Service) GetEmployee(ctx context.Context, employeeId string) (models.User, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.GetEmployee: %w", err)
+	}
+
+	user, err := s.employeeByUUID(ctx, employeeId)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.GetEmployee: %w", err)
+	}
+	return user, nil
+}
+
+// EditEmployee changes username or names of employee, employees can only edit themselves
+func (s *Service) EditEmployee(ctx context.Context, employeeId string, changes map[string]string) (models.User, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.EditEmployee: %w", err)
+	}
+	if user.Id != employeeId {
+		return models.User{}, fmt.Errorf("service.Service.EditEmployee: %w", models.ErrForbidden)
+	}
+
+	// remarshal changes into user
+	before := user
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.EditEmployee: %w", err)
+	}
+	err = json.Unmarshal(data, &user)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.EditEmployee: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoEmployee
+		}
+
+		user, err = s.employeeByUUID(ctx, user.Id)
+		if err != nil {
+			return err
+		}
+		return s.auditEmployee(ctx, models.ActionEmployeeEdit, user, before, user)
+	})
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.EditEmployee: %w", err)
+	}
+	return user, nil
+}
+
+// DeleteEmployee deletes employee account, employees can only delete themselves
+func (s *Service) DeleteEmployee(ctx context.Context, employeeId string) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteEmployee: %w", err)
+	}
+	if user.Id != employeeId {
+		return fmt.Errorf("service.Service.DeleteEmployee: %w", models.ErrForbidden)
+	}
+
+	// audit entry is written first, while memberships of employee still exist
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.auditEmployee(ctx, models.ActionEmployeeDelete, user, user, nil)
+		if err != nil {
+			return err
+		}
+
+		ok, err := s.repo.DeleteUser(ctx, user.Id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoEmployee
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteEmployee: %w", err)
+	}
+	return nil
+}
+
+//// Organizations
+
+// AddOrganization creates organization, authenticated user becomes its administrator
+func (s *Service) AddOrganization(ctx context.Context, org models.Organization) (models.Organization, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.AddOrganization: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		org, err = s.repo.AddOrganization(ctx, org, user.Id)
+		if err != nil {
+			return err
+		}
+		return s.auditOrganization(ctx, models.ActionOrganizationCreate, org.Id, nil, org)
+	})
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.AddOrganization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *Service) GetOrganizations(ctx context.Context, limit, offset int) ([]models.Organization, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetOrganizations: %w", err)
+	}
+
+	orgs, err := s.repo.GetOrganizations(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetOrganizations: %w", err)
+	}
+	return orgs, nil
+}
+
+func (s *Service) GetOrganization(ctx context.Context, organizationId string) (models.Organization, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.GetOrganization: %w", err)
+	}
+
+	org, err := s.organizationByUUID(ctx, organizationId)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.GetOrganization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *Service) EditOrganization(ctx context.Context, organizationId string, changes map[string]string) (models.Organization, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.EditOrganization: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.EditOrganization: %w", err)
+	}
+
+	org, err := s.organizationByUUID(ctx, organizationId)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.EditOrganization: %w", err)
+	}
+
+	// remarshal changes into organization
+	before := org
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.EditOrganization: %w", err)
+	}
+	err = json.Unmarshal(data, &org)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.EditOrganization: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.UpdateOrganization(ctx, org)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoOrganization
+		}
+
+		org, err = s.organizationByUUID(ctx, organizationId)
+		if err != nil {
+			return err
+		}
+		return s.auditOrganization(ctx, models.ActionOrganizationEdit, org.Id, before, org)
+	})
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.EditOrganization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *Service) DeleteOrganization(ctx context.Context, organizationId string) error {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteOrganization: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteOrganization: %w", err)
+	}
+
+	org, err := s.organizationByUUID(ctx, organizationId)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteOrganization: %w", err)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.DeleteOrganization(ctx, organizationId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoOrganization
+		}
+		return s.auditOrganization(ctx, models.ActionOrganizationDelete, org.Id, org, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteOrganization: %w", err)
+	}
+	return nil
+}
+
+//// Members
+
+// GetMembers lists employees of organization, available to any of its members
+func (s *Service) GetMembers(ctx context.Context, organizationId string, limit, offset int) ([]models.Member, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetMembers: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetMembers: %w", err)
+	}
+
+	members, err := s.repo.GetMembers(ctx, organizationId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetMembers: %w", err)
+	}
+	return members, nil
+}
+
+func (s *Service) SetMemberRole(ctx context.Context, organizationId, employeeId string, role models.Role) (models.Member, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Member{}, fmt.Errorf("service.Service.SetMemberRole: %w", err)
+	}
+
+	err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+	if err != nil {
+		return models.Member{}, fmt.Errorf("service.Service.SetMemberRole: %w", err)
+	}
+
+	user, err := s.employeeByUUID(ctx, employeeId)
+	if err != nil {
+		return models.Member{}, fmt.Errorf("service.Service.SetMemberRole: %w", err)
+	}
+
+	if role != models.RoleAdmin {
+		err = s.checkNotLastAdmin(ctx, organizationId, user.Id)
+		if err != nil {
+			return models.Member{}, fmt.Errorf("service.Service.SetMemberRole: %w", err)
+		}
+	}
+
+	oldRole, _, err := s.repo.UserRole(ctx, user.Id, organizationId)
+	if err != nil {
+		return models.Member{}, fmt.Errorf("service.Service.SetMemberRole: %w", err)
+	}
+
+	member := models.Member{User: user, Role: role}
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.SetMemberRole(ctx, organizationId, user.Id, role)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoMember
+		}
+		return s.auditMember(ctx, models.ActionMemberRole, organizationId, user.Id, models.Member{User: user, Role: oldRole}, member)
+	})
+	if err != nil {
+		return models.Member{}, fmt.Errorf("service.Service.SetMemberRole: %w", err)
+	}
+
+	return member, nil
+}
+
+// RemoveMember removes employee from organization, employees may also leave organization by themselves
+func (s *Service) RemoveMember(ctx context.Context, organizationId, employeeId string) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.RemoveMember: %w", err)
+	}
+
+	if user.Id != employeeId {
+		err = s.authorize(ctx, organizationId, models.PermOrganizationManage)
+		if err != nil {
+			return fmt.Errorf("service.Service.RemoveMember: %w", err)
+		}
+	}
+
+	err = s.checkNotLastAdmin(ctx, organizationId, employeeId)
+	if err != nil {
+		return fmt.Errorf("service.Service.RemoveMember: %w", err)
+	}
+
+	employee, err := s.employeeByUUID(ctx, employeeId)
+	if err != nil {
+		return fmt.Errorf("service.Service.RemoveMember: %w", err)
+	}
+	role, ok, err := s.repo.UserRole(ctx, employee.Id, organizationId)
+	if err != nil {
+		return fmt.Errorf("service.Service.RemoveMember: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("service.Service.RemoveMember: %w", models.ErrNoMember)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.RemoveMember(ctx, organizationId, employeeId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoMember
+		}
+		return s.auditMember(ctx, models.ActionMemberRemove, organizationId, employee.Id, models.Member{User: employee, Role: role}, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.RemoveMember: %w", err)
+	}
+	return nil
+}
+
+//// Service
+
+// checkNotLastAdmin returns models.ErrLastAdmin if employee is the only administrator of organization
+func (s *Service) checkNotLastAdmin(ctx context.Context, organizationId, employeeId string) error {
+	role, ok, err := s.repo.UserRole(ctx, employeeId, organizationId)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkNotLastAdmin: %w", err)
+	}
+	if !ok || role != models.RoleAdmin {
+		return nil
+	}
+
+	count, err := s.repo.EmployeeCountByRoles(ctx, organizationId, []models.Role{models.RoleAdmin})
+	if err != nil {
+		return fmt.Errorf("service.Service.checkNotLastAdmin: %w", err)
+	}
+	if count <= 1 {
+		return models.ErrLastAdmin
+	}
+	return nil
+}
+
+func (s *Service) employeeByUUID(ctx context.Context, employeeId string) (models.User, error) {
+	user, ok, err := s.repo.UserByUUID(ctx, employeeId)
+	if err != nil {
+		return models.User{}, fmt.Errorf("service.Service.employeeByUUID: %w", err)
+	}
+	if !ok {
+		return models.User{}, fmt.Errorf("service.Service.employeeByUUID: %w: %s", models.ErrNoEmployee, employeeId)
+	}
+	return user, nil
+}
+
+func (s *Service) organizationByUUID(ctx context.Context, organizationId string) (models.Organization, error) {
+	org, err := s.repo.OrganizationByUUID(ctx, organizationId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Organization{}, fmt.Errorf("service.Service.organizationByUUID: %w: %s", models.ErrNoOrganization, organizationId)
+	} else if err != nil {
+		return models.Organization{}, fmt.Errorf("service.Service.organizationByUUID: %w", err)
+	}
+	return org, nil
+}
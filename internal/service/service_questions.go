@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+)
+
+// AskQuestion adds clarification question on published tender, its author is never disclosed
+func (s *Service) AskQuestion(ctx context.Context, tenderId, text string) (models.Question, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AskQuestion: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AskQuestion: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AskQuestion: %w", err)
+	}
+
+	if tender.Status != models.TenderPublished {
+		return models.Question{}, fmt.Errorf("service.Service.AskQuestion: %w", models.ErrQuestionsClosed)
+	}
+
+	// question is only audited for organizations of its author, so that tender owner can not identify them
+	orgs, err := s.principalOrganizations(ctx)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AskQuestion: %w", err)
+	}
+
+	question := models.Question{TenderId: tender.Id, AuthorId: user.Id, Question: text}
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		question, err = s.repo.AddQuestion(ctx, question)
+		if err != nil {
+			return err
+		}
+		return s.auditQuestion(ctx, models.ActionQuestionCreate, question, orgs, nil, question)
+	})
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AskQuestion: %w", err)
+	}
+
+	question.Mine = true
+	return question, nil
+}
+
+// GetTenderQuestions lists answered questions on tender along with own ones of request principal,
+// employees of owning organization see unanswered questions as well
+func (s *Service) GetTenderQuestions(ctx context.Context, tenderId string, limit, offset int) ([]models.Question, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderQuestions: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderQuestions: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderQuestions: %w", err)
+	}
+
+	valid, err := s.allowed(ctx, tender.OrganizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderQuestions: %w", err)
+	}
+	// questions stay public after tender is closed
+	if !valid && tender.Status == models.TenderCreated {
+		return nil, &models.PermissionError{Permission: models.PermTenderView}
+	}
+
+	questions, err := s.repo.GetTenderQuestions(ctx, tender.Id, models.QuestionFilter{All: valid, AuthorId: user.Id}, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderQuestions: %w", err)
+	}
+
+	for i := range questions {
+		questions[i].Mine = questions[i].AuthorId == user.Id
+	}
+	return questions, nil
+}
+
+// AnswerQuestion answers question on published tender, answer can be changed until tender is closed
+func (s *Service) AnswerQuestion(ctx context.Context, tenderId, questionId, answer string) (models.Question, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", err)
+	}
+
+	question, err := s.repo.GetQuestion(ctx, questionId)
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", err)
+	}
+	if question.TenderId != tender.Id {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", models.ErrNoQuestion)
+	}
+	if tender.Status != models.TenderPublished {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", models.ErrQuestionsClosed)
+	}
+
+	before := question
+	question.Answer = answer
+	question.AnsweredBy = user.Id
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		question, err = s.repo.AnswerQuestion(ctx, question)
+		if err != nil {
+			return err
+		}
+		return s.auditQuestion(ctx, models.ActionQuestionAnswer, question, []string{tender.OrganizationId}, before, question)
+	})
+	if err != nil {
+		return models.Question{}, fmt.Errorf("service.Service.AnswerQuestion: %w", err)
+	}
+
+	question.Mine = question.AuthorId == user.Id
+	return question, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"tenders/internal/models"
+	"time"
+)
+
+// schedulerBatch limits amount of tenders processed by single call of scheduler job
+const schedulerBatch = 100
+
+// PublishScheduledTenders publishes tenders, publication time of which has come, and returns
+// amount of published tenders. It is meant to be called periodically by scheduler of application
+func (s *Service) PublishScheduledTenders(ctx context.Context) (int, error) {
+	ctx = asSystem(ctx)
+	now := time.Now()
+
+	tenders, err := s.repo.GetScheduledTenders(ctx, now, schedulerBatch)
+	if err != nil {
+		return 0, fmt.Errorf("service.Service.PublishScheduledTenders: %w", err)
+	}
+
+	published := 0
+	for _, tender := range tenders {
+		if ctx.Err() != nil {
+			return published, fmt.Errorf("service.Service.PublishScheduledTenders: %w", ctx.Err())
+		}
+
+		ok := false
+		err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+			var after models.Tender
+			after, ok, err = s.repo.PublishScheduledTender(ctx, tender.Id, now)
+			if err != nil || !ok {
+				return err
+			}
+			return s.auditTender(ctx, models.ActionTenderStatus, tender, tender, after)
+		})
+		if err != nil {
+			// tender is retried on the next run
+			log.Printf("service.Service.PublishScheduledTenders: tender %s: %s\n", tender.Id, err)
+			continue
+		}
+		if ok {
+			published++
+		}
+	}
+
+	return published, nil
+}
+
+// CloseExpiredTenders closes published tenders, submission deadline of which has passed, and returns
+// amount of closed tenders. It is meant to be called periodically by scheduler of application
+func (s *Service) CloseExpiredTenders(ctx context.Context) (int, error) {
+	ctx = asSystem(ctx)
+	now := time.Now()
+
+	tenders, err := s.repo.GetExpiredTenders(ctx, now, schedulerBatch)
+	if err != nil {
+		return 0, fmt.Errorf("service.Service.CloseExpiredTenders: %w", err)
+	}
+
+	closed := 0
+	for _, tender := range tenders {
+		if ctx.Err() != nil {
+			return closed, fmt.Errorf("service.Service.CloseExpiredTenders: %w", ctx.Err())
+		}
+
+		ok := false
+		err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+			var after models.Tender
+			after, ok, err = s.repo.CloseExpiredTender(ctx, tender.Id, now)
+			if err != nil || !ok {
+				return err
+			}
+			err = s.repo.CloseTenderQuestions(ctx, tender.Id)
+			if err != nil {
+				return err
+			}
+			return s.auditTender(ctx, models.ActionTenderStatus, after, tender, after)
+		})
+		if err != nil {
+			// tender is retried on the next run
+			log.Printf("service.Service.CloseExpiredTenders: tender %s: %s\n", tender.Id, err)
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+
+	return closed, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"tenders/internal/models"
+	"time"
+)
+
+// OpenTenderBids opens bids of sealed tender once its submission deadline passes: contents of bids and
+// their versions are decrypted and stored as plain ones, opening is recorded along with employee opening them
+func (s *Service) OpenTenderBids(ctx context.Context, tenderId string) (models.Tender, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.OpenTenderBids: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.OpenTenderBids: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderStatus)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.OpenTenderBids: %w", err)
+	}
+
+	now := time.Now()
+	if !tender.BidsSealed() || !tender.DeadlinePassed(now) {
+		return models.Tender{}, fmt.Errorf("service.Service.OpenTenderBids: %w", models.ErrOpeningNotDue)
+	}
+
+	key, err := s.repo.GetTenderKey(ctx, tender.Id)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.OpenTenderBids: %w", err)
+	}
+
+	before := tender
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		// tender may only be opened once, concurrent opening is rejected here
+		opened, err := s.repo.OpenTender(ctx, tender.Id, user.Id, now)
+		if err != nil {
+			return err
+		}
+		if !opened {
+			return models.ErrOpeningNotDue
+		}
+
+		bids, err := s.repo.GetBids(ctx, 0, 0, models.BidFilter{TenderId: tender.Id})
+		if err != nil {
+			return err
+		}
+		for _, bid := range bids {
+			versions, err := s.repo.GetBidVersions(ctx, bid.Id, 0)
+			if err != nil {
+				return err
+			}
+			for _, version := range append(versions, bid) {
+				if !version.Sealed {
+					continue
+				}
+				content, err := s.openBidContent(key, version)
+				if err != nil {
+					return fmt.Errorf("bid %s: %w", bid.Id, err)
+				}
+				err = s.repo.UnsealBidContent(ctx, bid.Id, version.SealedContent, content)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		tender.OpenedAt = &now
+		tender.OpenedBy = user.Id
+		return s.auditTender(ctx, models.ActionTenderOpen, tender, before, tender)
+	})
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.OpenTenderBids: %w", err)
+	}
+
+	return tender, nil
+}
+
+//// Service
+
+// sealBid encrypts content of bid submitted to tender with sealed bids, bid is stored with blank name,
+// description and price in its place. Bids of other tenders are left as is
+func (s *Service) sealBid(ctx context.Context, tender models.Tender, bid *models.Bid) error {
+	if !tender.BidsSealed() {
+		return nil
+	}
+	if s.sealer == nil {
+		return fmt.Errorf("service.Service.sealBid: %w", models.ErrSealingUnavailable)
+	}
+
+	key, err := s.repo.GetTenderKey(ctx, tender.Id)
+	if err != nil {
+		return fmt.Errorf("service.Service.sealBid: %w", err)
+	}
+	data, err := json.Marshal(models.BidContent{Name: bid.Name, Description: bid.Description, Price: bid.Price})
+	if err != nil {
+		return fmt.Errorf("service.Service.sealBid: %w", err)
+	}
+	sealed, err := s.sealer.Seal(key, tender.Id, data)
+	if err != nil {
+		return fmt.Errorf("service.Service.sealBid: %w", err)
+	}
+
+	bid.Sealed = true
+	bid.SealedContent = sealed
+	bid.Name, bid.Description, bid.Price = "", "", nil
+	return nil
+}
+
+// revealBids fills in content of sealed bids user may act on behalf of, bids of others are left as placeholders
+func (s *Service) revealBids(ctx context.Context, user models.User, bids []models.Bid) error {
+	keys := make(map[string][]byte)
+	for i := range bids {
+		if !bids[i].Sealed {
+			continue
+		}
+		valid, err := s.userAllowedToEditBid(ctx, user, bids[i])
+		if err != nil {
+			return fmt.Errorf("service.Service.revealBids: %w", err)
+		}
+		if !valid {
+			continue
+		}
+
+		key, ok := keys[bids[i].TenderId]
+		if !ok {
+			key, err = s.repo.GetTenderKey(ctx, bids[i].TenderId)
+			if err != nil {
+				return fmt.Errorf("service.Service.revealBids: %w", err)
+			}
+			keys[bids[i].TenderId] = key
+		}
+
+		content, err := s.openBidContent(key, bids[i])
+		if err != nil {
+			return fmt.Errorf("service.Service.revealBids: bid %s: %w", bids[i].Id, err)
+		}
+		bids[i].Name, bids[i].Description, bids[i].Price = content.Name, content.Description, content.Price
+	}
+	return nil
+}
+
+func (s *Service) revealBid(ctx context.Context, user models.User, bid models.Bid) (models.Bid, error) {
+	bids := []models.Bid{bid}
+	err := s.revealBids(ctx, user, bids)
+	return bids[0], err
+}
+
+func (s *Service) openBidContent(key []byte, bid models.Bid) (models.BidContent, error) {
+	var content models.BidContent
+	if s.sealer == nil {
+		return content, models.ErrSealingUnavailable
+	}
+	data, err := s.sealer.Open(key, bid.TenderId, bid.SealedContent)
+	if err != nil {
+		return content, err
+	}
+	err = json.Unmarshal(data, &content)
+	return content, err
+}
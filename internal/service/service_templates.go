@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+)
+
+// GetTemplates returns tender templates of organization, they are only shown to its employees
+func (s *Service) GetTemplates(ctx context.Context, organizationId string, limit, offset int) ([]models.Template, error) {
+	err := s.authorize(ctx, organizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTemplates: %w", err)
+	}
+
+	templates, err := s.repo.GetTemplates(ctx, organizationId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTemplates: %w", err)
+	}
+	return templates, nil
+}
+
+// AddTemplate creates template of organization. Template with source tender takes its content, lots and criteria
+// from the tender, which should belong to the same organization
+func (s *Service) AddTemplate(ctx context.Context, template models.Template) (models.Template, error) {
+	err := s.authorize(ctx, template.OrganizationId, models.PermTenderCreate)
+	if err != nil {
+		return models.Template{}, fmt.Errorf("service.Service.AddTemplate: %w", err)
+	}
+
+	if len(template.SourceTenderId) > 0 {
+		tender, err := s.tenderByUUID(ctx, template.SourceTenderId)
+		if err != nil {
+			return models.Template{}, fmt.Errorf("service.Service.AddTemplate: %w", err)
+		}
+		if tender.OrganizationId != template.OrganizationId {
+			return models.Template{}, fmt.Errorf("service.Service.AddTemplate: %w: %s", models.ErrNoTender, tender.Id)
+		}
+
+		lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+		if err != nil {
+			return models.Template{}, fmt.Errorf("service.Service.AddTemplate: %w", err)
+		}
+		criteria, err := s.repo.GetTenderCriteria(ctx, tender.Id)
+		if err != nil {
+			return models.Template{}, fmt.Errorf("service.Service.AddTemplate: %w", err)
+		}
+
+		template.Description = tender.Description
+		template.ServiceType = tender.ServiceType
+		template.Budget = tender.Budget
+		template.BudgetConfidential = tender.BudgetConfidential
+		template.Visibility = tender.Visibility
+		template.Lots = models.TemplateLots(lots)
+		template.Criteria = models.TemplateCriteria(criteria)
+	}
+
+	// templates created by API keys have no author
+	if user, ok := auth.UserFromContext(ctx); ok {
+		template.AuthorId = user.Id
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		template, err = s.repo.AddTemplate(ctx, template)
+		if err != nil {
+			return err
+		}
+		return s.auditTemplate(ctx, models.ActionTemplateCreate, template, nil, template)
+	})
+	if err != nil {
+		return models.Template{}, fmt.Errorf("service.Service.AddTemplate: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s *Service) DeleteTemplate(ctx context.Context, organizationId, templateId string) error {
+	err := s.authorize(ctx, organizationId, models.PermTenderCreate)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTemplate: %w", err)
+	}
+
+	template, err := s.templateByUUID(ctx, templateId)
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTemplate: %w", err)
+	}
+	if template.OrganizationId != organizationId {
+		return fmt.Errorf("service.Service.DeleteTemplate: %w: %s", models.ErrNoTemplate, templateId)
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.DeleteTemplate(ctx, template.Id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrNoTemplate
+		}
+		return s.auditTemplate(ctx, models.ActionTemplateDelete, template, template, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("service.Service.DeleteTemplate: %w", err)
+	}
+	return nil
+}
+
+// CloneTender creates new tender in Created status from tender or template with provided id. Clone gets content,
+// lots and criteria of its source, tender's clone gets its invited organizations as well. Schedule, deadline and
+// history of source are not copied
+func (s *Service) CloneTender(ctx context.Context, sourceId, name string) (models.Tender, error) {
+	clone, lots, criteria, invited, err := s.cloneSource(ctx, sourceId)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.CloneTender: %w", err)
+	}
+	clone.Name = name
+	clone.Status = models.TenderCreated
+
+	err = s.authorize(ctx, clone.OrganizationId, models.PermTenderCreate)
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.CloneTender: %s: %w", principalName(ctx), err)
+	}
+
+	// clones created by API keys have no author
+	if user, ok := auth.UserFromContext(ctx); ok {
+		clone.Author = user.Id
+	}
+
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		clone, err = s.repo.AddTender(ctx, clone)
+		if err != nil {
+			return err
+		}
+		if len(lots) > 0 {
+			_, err = s.repo.SetTenderLots(ctx, clone.Id, lots)
+			if err != nil {
+				return err
+			}
+		}
+		if len(criteria) > 0 {
+			_, err = s.repo.SetTenderCriteria(ctx, clone.Id, criteria)
+			if err != nil {
+				return err
+			}
+		}
+		if len(invited) > 0 {
+			_, err = s.repo.SetInvitedOrganizations(ctx, clone.Id, invited, clone.Author)
+			if err != nil {
+				return err
+			}
+		}
+		return s.auditTender(ctx, models.ActionTenderClone, clone, nil, clone)
+	})
+	if err != nil {
+		return models.Tender{}, fmt.Errorf("service.Service.CloneTender: %w", err)
+	}
+
+	return clone, nil
+}
+
+// cloneSource reads content of tender or template to clone, along with its lots, criteria and invited organizations
+func (s *Service) cloneSource(ctx context.Context, sourceId string) (models.Tender, []models.Lot, []models.Criterion, []string, error) {
+	tender, err := s.repo.GetTenderByUUID(ctx, sourceId, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		template, err := s.repo.GetTemplate(ctx, sourceId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w: %s", models.ErrNoTender, sourceId)
+		} else if err != nil {
+			return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w", err)
+		}
+
+		clone := models.Tender{
+			OrganizationId:     template.OrganizationId,
+			ServiceType:        template.ServiceType,
+			Description:        template.Description,
+			Budget:             template.Budget,
+			BudgetConfidential: template.BudgetConfidential,
+			Visibility:         template.Visibility,
+			SourceTemplateId:   template.Id,
+		}
+		return clone, template.TenderLots(), template.TenderCriteria(), nil, nil
+	} else if err != nil {
+		return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w", err)
+	}
+
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w", err)
+	}
+
+	lots, err := s.repo.GetTenderLots(ctx, tender.Id)
+	if err != nil {
+		return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w", err)
+	}
+	criteria, err := s.repo.GetTenderCriteria(ctx, tender.Id)
+	if err != nil {
+		return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w", err)
+	}
+	organizations, err := s.repo.GetInvitedOrganizations(ctx, tender.Id)
+	if err != nil {
+		return models.Tender{}, nil, nil, nil, fmt.Errorf("service.Service.cloneSource: %w", err)
+	}
+	invited := make([]string, 0, len(organizations))
+	for _, org := range organizations {
+		invited = append(invited, org.OrganizationId)
+	}
+
+	clone := models.Tender{
+		OrganizationId:     tender.OrganizationId,
+		ServiceType:        tender.ServiceType,
+		Description:        tender.Description,
+		Budget:             tender.Budget,
+		BudgetConfidential: tender.BudgetConfidential,
+		Visibility:         tender.Visibility,
+		SourceTenderId:     tender.Id,
+	}
+	return clone, lots, criteria, invited, nil
+}
+
+func (s *Service) templateByUUID(ctx context.Context, templateId string) (models.Template, error) {
+	template, err := s.repo.GetTemplate(ctx, templateId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Template{}, fmt.Errorf("service.Service.templateByUUID: %w: %s", models.ErrNoTemplate, templateId)
+	} else if err != nil {
+		return models.Template{}, fmt.Errorf("service.Service.templateByUUID: %w", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"tenders/internal/models"
+)
+
+// GetTenderVersions lists versions of tender in ascending order, they are visible to whoever may view tender status
+func (s *Service) GetTenderVersions(ctx context.Context, tenderId string, limit, offset int) ([]models.Tender, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderVersions: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderVersions: %w", err)
+	}
+
+	err = s.checkTenderViewable(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderVersions: %w", err)
+	}
+
+	versions, err := s.repo.GetTenderVersionHistory(ctx, tender.Id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderVersions: %w", err)
+	}
+
+	err = s.hideVersionBudgets(ctx, tender, versions)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetTenderVersions: %w", err)
+	}
+	return versions, nil
+}
+
+// DiffTenderVersions returns fields changed between two versions of tender, either of them may be the later one
+func (s *Service) DiffTenderVersions(ctx context.Context, tenderId string, from, to int) (models.VersionDiff, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", err)
+	}
+
+	err = s.checkTenderViewable(ctx, tender)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", err)
+	}
+
+	versions := make([]models.Tender, 2)
+	for i, version := range []int{from, to} {
+		if version < 1 || version > tender.Version {
+			return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", models.ErrNoVersion)
+		}
+		found, err := s.repo.GetTenderVersions(ctx, tender.Id, version)
+		if err != nil {
+			return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", err)
+		}
+		if len(found) == 0 {
+			return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", models.ErrNoVersion)
+		}
+		versions[i] = found[0]
+	}
+
+	err = s.hideVersionBudgets(ctx, tender, versions)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", err)
+	}
+
+	changes, err := fieldChanges(versions[0], versions[1])
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffTenderVersions: %w", err)
+	}
+	return models.VersionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// GetBidVersions lists versions of bid in ascending order, they are visible to whoever may view bid status
+func (s *Service) GetBidVersions(ctx context.Context, bidId string, limit, offset int) ([]models.Bid, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidVersions: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidVersions: %w", err)
+	}
+
+	err = s.checkBidViewable(ctx, user, bid)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidVersions: %w", err)
+	}
+
+	versions, err := s.repo.GetBidVersionHistory(ctx, bid.Id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidVersions: %w", err)
+	}
+
+	err = s.revealBids(ctx, user, versions)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidVersions: %w", err)
+	}
+	return versions, nil
+}
+
+// DiffBidVersions returns fields changed between two versions of bid, either of them may be the later one
+func (s *Service) DiffBidVersions(ctx context.Context, bidId string, from, to int) (models.VersionDiff, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", err)
+	}
+
+	err = s.checkBidViewable(ctx, user, bid)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", err)
+	}
+
+	versions := make([]models.Bid, 2)
+	for i, version := range []int{from, to} {
+		if version < 1 || version > bid.Version {
+			return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", models.ErrNoVersion)
+		}
+		found, err := s.repo.GetBidVersions(ctx, bid.Id, version)
+		if err != nil {
+			return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", err)
+		}
+		if len(found) == 0 {
+			return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", models.ErrNoVersion)
+		}
+		versions[i] = found[0]
+	}
+
+	err = s.revealBids(ctx, user, versions)
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", err)
+	}
+	changes, err := fieldChanges(versions[0], versions[1])
+	if err != nil {
+		return models.VersionDiff{}, fmt.Errorf("service.Service.DiffBidVersions: %w", err)
+	}
+	return models.VersionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+//// Service
+
+// hideVersionBudgets clears confidential budgets of tender versions unless principal belongs to organization
+// owning tender, budget confidential now is hidden in earlier versions as well
+func (s *Service) hideVersionBudgets(ctx context.Context, tender models.Tender, versions []models.Tender) error {
+	orgs, err := s.principalOrganizations(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.hideVersionBudgets: %w", err)
+	}
+	if slices.Contains(orgs, tender.OrganizationId) {
+		return nil
+	}
+
+	for i := range versions {
+		if tender.BudgetConfidential || versions[i].BudgetConfidential {
+			versions[i].Budget = nil
+		}
+	}
+	return nil
+}
+
+// fieldChanges compares JSON representations of two versions of entity field by field, so that
+// fields hidden from clients are not compared and reported. Version number itself is skipped
+func fieldChanges(from, to any) ([]models.FieldChange, error) {
+	fromFields, err := jsonFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := jsonFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(fromFields)+len(toFields))
+	for name := range fromFields {
+		names = append(names, name)
+	}
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []models.FieldChange{}
+	for _, name := range names {
+		if name == "version" || bytes.Equal(fromFields[name], toFields[name]) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{Field: name, From: fromFields[name], To: toFields[name]})
+	}
+	return changes, nil
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
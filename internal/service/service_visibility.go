@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"tenders/internal/auth"
+	"tenders/internal/models"
+)
+
+// GetInvitedOrganizations returns organizations invited to tender, list is only shown to owning organization
+func (s *Service) GetInvitedOrganizations(ctx context.Context, tenderId string) ([]models.InvitedOrganization, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitedOrganizations: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitedOrganizations: %w", err)
+	}
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitedOrganizations: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitedOrganizations: %w", err)
+	}
+
+	invited, err := s.repo.GetInvitedOrganizations(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetInvitedOrganizations: %w", err)
+	}
+	return invited, nil
+}
+
+// SetInvitedOrganizations replaces organizations invited to tender. List is kept for public tenders as well,
+// so that it applies once tender is made invite-only
+func (s *Service) SetInvitedOrganizations(ctx context.Context, tenderId string, organizationIds []string) ([]models.InvitedOrganization, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+	}
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermTenderEdit)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+	}
+
+	if tender.Status.Final() {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", models.ErrTenderFinalized)
+	}
+
+	// owning organization always sees its tender, so it is never invited
+	organizationIds = slices.DeleteFunc(organizationIds, func(id string) bool { return id == tender.OrganizationId })
+	for _, organizationId := range organizationIds {
+		_, err = s.repo.OrganizationByUUID(ctx, organizationId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w: %s", models.ErrNoOrganization, organizationId)
+		} else if err != nil {
+			return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+		}
+	}
+
+	before, err := s.repo.GetInvitedOrganizations(ctx, tender.Id)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+	}
+
+	// invitations made by API keys have no author
+	invitedBy := ""
+	if user, ok := auth.UserFromContext(ctx); ok {
+		invitedBy = user.Id
+	}
+
+	var invited []models.InvitedOrganization
+	err = s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		invited, err = s.repo.SetInvitedOrganizations(ctx, tender.Id, organizationIds, invitedBy)
+		if err != nil {
+			return err
+		}
+		return s.auditTender(ctx, models.ActionTenderInvited, tender, before, invited)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.SetInvitedOrganizations: %w", err)
+	}
+
+	return invited, nil
+}
+
+// checkTenderVisible ensures request principal may know tender exists: invite-only tender is visible to employees
+// (and API keys) of owning and invited organizations only, it is reported missing to anyone else
+func (s *Service) checkTenderVisible(ctx context.Context, tender models.Tender) error {
+	if tender.Visibility != models.VisibilityInviteOnly {
+		return nil
+	}
+
+	orgs, err := s.principalOrganizations(ctx)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkTenderVisible: %w", err)
+	}
+	if slices.Contains(orgs, tender.OrganizationId) {
+		return nil
+	}
+
+	invited, err := s.repo.TenderInvites(ctx, tender.Id, orgs)
+	if err != nil {
+		return fmt.Errorf("service.Service.checkTenderVisible: %w", err)
+	}
+	if !invited {
+		return fmt.Errorf("service.Service.checkTenderVisible: %w", models.ErrNoTender)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"tenders/internal/models"
+	"time"
+)
+
+// WithdrawBid withdraws bid on behalf of its author with reason, which is recorded in history of bid events.
+// Withdrawn bid is not decided on and may be resubmitted while its tender accepts bids
+func (s *Service) WithdrawBid(ctx context.Context, bidId, reason string, expectedVersion int) (models.Bid, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", err)
+	}
+	if bid.Status == models.BidApproved || bid.Status == models.BidRejected {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", models.ErrBidFinalized)
+	}
+
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+
+	if bid.Status == models.BidCanceled {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", models.ErrBidWithdrawn)
+	}
+	if len(reason) == 0 {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", models.ErrWithdrawalReason)
+	}
+	err = checkVersion(bid.Version, expectedVersion)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", err)
+	}
+
+	bid, err = s.changeWithdrawal(ctx, user, bid, models.BidWithdrawn, reason)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.WithdrawBid: %w", err)
+	}
+	return bid, nil
+}
+
+// ResubmitBid publishes withdrawn bid once again, which is only possible while its tender is published and
+// its submission deadline has not passed. Reason is optional
+func (s *Service) ResubmitBid(ctx context.Context, bidId, reason string, expectedVersion int) (models.Bid, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+
+	bid, err := s.bidByUUID(ctx, bidId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+
+	valid, err := s.userAllowedToEditBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+	if !valid {
+		return models.Bid{}, &models.PermissionError{Permission: models.PermBidSubmit}
+	}
+
+	if bid.Status != models.BidCanceled {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", models.ErrBidNotWithdrawn)
+	}
+
+	// resubmission is submission of bid, so tender should still be open to author's organization
+	tender, err := s.tenderByUUID(ctx, bid.TenderId)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+	err = s.checkTenderVisible(ctx, tender)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+	if tender.Status != models.TenderPublished || tender.DeadlinePassed(time.Now()) {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", models.ErrResubmissionClosed)
+	}
+	err = checkVersion(bid.Version, expectedVersion)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+
+	bid, err = s.changeWithdrawal(ctx, user, bid, models.BidResubmitted, reason)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.ResubmitBid: %w", err)
+	}
+	return bid, nil
+}
+
+// GetBidEvents lists withdrawals and resubmissions of bids of tender, optionally of single bid only.
+// History is only visible to employees of organization owning tender
+func (s *Service) GetBidEvents(ctx context.Context, tenderId, bidId string, limit, offset int) ([]models.BidEvent, error) {
+	_, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidEvents: %w", err)
+	}
+
+	tender, err := s.tenderByUUID(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidEvents: %w", err)
+	}
+
+	err = s.authorize(ctx, tender.OrganizationId, models.PermBidsView)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidEvents: %w", err)
+	}
+
+	events, err := s.repo.GetBidEvents(ctx, tender.Id, bidId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("service.Service.GetBidEvents: %w", err)
+	}
+	return events, nil
+}
+
+//// Service
+
+// changeWithdrawal withdraws or resubmits bid, recording event along with audit entry
+func (s *Service) changeWithdrawal(ctx context.Context, user models.User, bid models.Bid, eventType models.BidEventType, reason string) (models.Bid, error) {
+	before := bid
+	action := models.ActionBidWithdraw
+	bid.Status = models.BidCanceled
+	if eventType == models.BidResubmitted {
+		action = models.ActionBidResubmit
+		bid.Status = models.BidPublished
+	}
+
+	err := s.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateBid(ctx, bid, true)
+		if err != nil {
+			return err
+		}
+		bid.Version++
+
+		_, err = s.repo.AddBidEvent(ctx, models.BidEvent{
+			BidId:          bid.Id,
+			TenderId:       bid.TenderId,
+			Type:           eventType,
+			Reason:         reason,
+			PreviousStatus: before.Status,
+			UserId:         user.Id,
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditBid(ctx, action, bid, before, bid)
+	})
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.changeWithdrawal: %w", err)
+	}
+
+	bid, err = s.revealBid(ctx, user, bid)
+	if err != nil {
+		return models.Bid{}, fmt.Errorf("service.Service.changeWithdrawal: %w", err)
+	}
+	return bid, nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage keeps content in files under root directory
+type LocalStorage struct {
+	dir string
+}
+
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("storage.NewLocalStorage: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+func (s *LocalStorage) Save(ctx context.Context, key string, content io.Reader) error {
+	name, err := s.path(key)
+	if err != nil {
+		return fmt.Errorf("storage.LocalStorage.Save: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(name), 0o750)
+	if err != nil {
+		return fmt.Errorf("storage.LocalStorage.Save: %w", err)
+	}
+
+	// content is written to temporary file first and renamed once complete
+	file, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage.LocalStorage.Save: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, contextReader{ctx: ctx, r: content})
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("storage.LocalStorage.Save: %w", err)
+	}
+
+	err = os.Rename(file.Name(), name)
+	if err != nil {
+		return fmt.Errorf("storage.LocalStorage.Save: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, fmt.Errorf("storage.LocalStorage.Open: %w", err)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("storage.LocalStorage.Open: %w", err)
+	}
+	return file, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return fmt.Errorf("storage.LocalStorage.Delete: %w", err)
+	}
+
+	err = os.Remove(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage.LocalStorage.Delete: %w", err)
+	}
+	return nil
+}
+
+// path resolves key to file under root directory, keys escaping it are rejected
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := path.Clean(key)
+	if len(key) == 0 || path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// contextReader stops reading once ctx is cancelled, so that abandoned uploads are not written to the end
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Save(ctx, "tenders/1/spec", strings.NewReader("specification"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Save(ctx, "tenders/1/spec", strings.NewReader("revised specification"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.Open(ctx, "tenders/1/spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "revised specification" {
+		t.Errorf("Expected saved content to be replaced, got %q", content)
+	}
+
+	// failed upload leaves neither content nor temporary files behind
+	err = s.Save(ctx, "tenders/1/broken", io.MultiReader(strings.NewReader("partial"), errReader{}))
+	if err == nil {
+		t.Fatalf("Expected failing reader to fail save")
+	}
+	_, err = s.Open(ctx, "tenders/1/broken")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected partially written content to be missing, got %v", err)
+	}
+	entries, err := os.ReadDir(s.dir + "/tenders/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected single file in directory, got %d", len(entries))
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "tenders/../../outside"} {
+		err = s.Save(ctx, key, strings.NewReader("escape"))
+		if err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+
+	err = s.Delete(ctx, "tenders/1/spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Open(ctx, "tenders/1/spec")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected deleted content to be missing, got %v", err)
+	}
+	err = s.Delete(ctx, "tenders/1/spec")
+	if err != nil {
+		t.Errorf("Expected deletion of missing content to succeed, got %v", err)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage keeps content of attachments under keys chosen by service, keys are slash-separated paths
+type Storage interface {
+	// Save writes content under key, partially written content is never visible under key
+	Save(ctx context.Context, key string, content io.Reader) error
+	// Open returns reader of content saved under key, error wraps fs.ErrNotExist if there is none
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes content saved under key, missing content is not an error
+	Delete(ctx context.Context, key string) error
+}